@@ -28,7 +28,7 @@ func (srv *Server) createAccount(ctx *gin.Context) {
 		Balance:  0,
 	}
 
-	account, err := srv.store.CreateAccount(ctx, arg)
+	result, err := srv.store.CreateAccountTx(ctx, db.CreateAccountTxParams{CreateAccountParams: arg})
 	if err != nil {
 		if db.ErrorCode(err) == db.UniqueViolation || db.ErrorCode(err) == db.ForeignKeyViolation {
 			ctx.JSON(http.StatusForbidden, errorResponse(err))
@@ -37,7 +37,7 @@ func (srv *Server) createAccount(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, result.Account)
 }
 
 type getAccountRequest struct {
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// loginMaxDelay upper bound of the progressive delay before password check
+const loginMaxDelay = 5 * time.Second
+
+// errInvalidCredentials is returned for every failed login, so the caller
+// can not tell unknown usernames, wrong passwords and locked users apart
+var errInvalidCredentials = errors.New("invalid username or password")
+
+// throttleLogin reject clients which failed too many logins and hold the
+// request for the delay growing with failed logins of the username.
+// The response is written when it returns false.
+func (srv *Server) throttleLogin(ctx *gin.Context, username string) (int64, bool) {
+	since := time.Now().Add(-srv.config.LoginAttemptWindow)
+
+	ipFailures, err := srv.store.CountFailedLoginsByClientIp(ctx, db.CountFailedLoginsByClientIpParams{
+		ClientIp: ctx.ClientIP(),
+		Since:    since,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return 0, false
+	}
+	if srv.config.LoginIpMaxAttempts > 0 && ipFailures >= srv.config.LoginIpMaxAttempts {
+		err := errors.New("too many failed logins, try again later")
+		ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+		return 0, false
+	}
+
+	failures, err := srv.store.CountFailedLoginsByUsername(ctx, db.CountFailedLoginsByUsernameParams{
+		Username: username,
+		Since:    since,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return 0, false
+	}
+
+	if failures > 0 && srv.config.LoginBaseDelay > 0 {
+		delay := srv.config.LoginBaseDelay
+		for i := int64(1); i < failures && delay < loginMaxDelay; i++ {
+			delay *= 2
+		}
+		select {
+		case <-time.After(min(delay, loginMaxDelay)):
+		case <-ctx.Request.Context().Done():
+			ctx.JSON(http.StatusRequestTimeout, errorResponse(ctx.Request.Context().Err()))
+			return 0, false
+		}
+	}
+	return failures, true
+}
+
+// failLogin record the failed login, lock the existing user after
+// LoginMaxAttempts failures and write the uniform error
+func (srv *Server) failLogin(ctx *gin.Context, username string, failures int64, userExists bool) {
+	_, err := srv.store.CreateFailedLogin(ctx, db.CreateFailedLoginParams{
+		Username: username,
+		ClientIp: ctx.ClientIP(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if userExists && srv.config.LoginMaxAttempts > 0 && failures+1 >= srv.config.LoginMaxAttempts {
+		user, err := srv.store.LockUserTx(ctx, db.LockUserTxParams{
+			Username:    username,
+			LockedUntil: time.Now().Add(srv.config.LoginLockoutDuration),
+			UserAgent:   ctx.Request.UserAgent(),
+			ClientIp:    ctx.ClientIP(),
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		log.Warn().Str("username", user.Username).Str("client_ip", ctx.ClientIP()).
+			Time("locked_until", user.LockedUntil.Time).Msg("user locked after failed logins")
+	}
+
+	ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
+}
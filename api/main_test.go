@@ -1,13 +1,18 @@
 package api
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
 
 	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/fraud"
+	"github.com/dubass83/simplebank/refresh"
+	"github.com/dubass83/simplebank/token"
 	"github.com/dubass83/simplebank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,8 +21,15 @@ func NewTestServer(t *testing.T, store db.Store) *Server {
 		TokenString:   util.RandomString(32),
 		TokenDuration: time.Minute * 5,
 	}
-	server, err := NewServer(config, store)
+	tokenMaker, err := token.NewMaker(config)
 	require.NoError(t, err)
+	server, err := NewServer(config, store, tokenMaker)
+	require.NoError(t, err)
+	server.fraudEvaluator = fraudStub{action: fraud.ActionAllow}
+	// router captures the checker, so it is built again with the stub
+	server.revocation = revocationStub{}
+	server.refreshTokens = refresh.NewRotator(store, tokenMaker, server.revocation, config.RefreshTokenDuration)
+	server.setupRouter()
 
 	return server
 }
@@ -26,3 +38,25 @@ func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
 }
+
+// fraudStub return the same decision for every transfer
+type fraudStub struct {
+	action fraud.Action
+}
+
+func (stub fraudStub) Evaluate(ctx context.Context, transfer fraud.Transfer) (fraud.Decision, error) {
+	return fraud.Decision{Action: stub.action}, nil
+}
+
+// revocationStub return the same result for every token without store lookups
+type revocationStub struct {
+	err error
+}
+
+func (stub revocationStub) Check(ctx context.Context, payload *token.Payload) error {
+	return stub.err
+}
+
+func (stub revocationStub) ForgetSession(sessionID uuid.UUID) {}
+
+func (stub revocationStub) ForgetUser(username string) {}
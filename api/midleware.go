@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/dubass83/simplebank/apikey"
+	"github.com/dubass83/simplebank/revocation"
 	"github.com/dubass83/simplebank/token"
 	"github.com/gin-gonic/gin"
 )
@@ -16,7 +18,7 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
-func AuthMidleWare(tokenMaker token.Maker) gin.HandlerFunc {
+func AuthMidleWare(tokenMaker token.Maker, checker revocation.Checker, apiKeys *apikey.Authenticator) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		autorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(autorizationHeader) == 0 {
@@ -33,6 +35,16 @@ func AuthMidleWare(tokenMaker token.Maker) gin.HandlerFunc {
 		}
 
 		tokenType := strings.ToLower(fields[0])
+		if tokenType == apikey.AuthorizationType {
+			payload, err := apiKeys.Authenticate(ctx, fields[1])
+			if err != nil {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+				return
+			}
+			ctx.Set(authorizationPayloadKey, payload)
+			ctx.Next()
+			return
+		}
 		if tokenType != authorizationTypeBearer {
 			err := fmt.Errorf("unsuported autorization type: %s", tokenType)
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
@@ -45,7 +57,36 @@ func AuthMidleWare(tokenMaker token.Maker) gin.HandlerFunc {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
+		if payload.Purpose != "" {
+			err := fmt.Errorf("%s token can not be used for authorization", payload.Purpose)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		// calls under impersonation are audited only by the gRPC server
+		if payload.IsImpersonated() {
+			err := errors.New("impersonated token can not be used for authorization")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		// session blocked or password changed after the token was issued
+		if err := checker.Check(ctx, payload); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
 		ctx.Set(authorizationPayloadKey, payload)
 		ctx.Next()
 	}
 }
+
+// ScopeMidleWare reject API keys without the scope, tokens of the user are not restricted
+func ScopeMidleWare(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if !payload.HasScope(scope) {
+			err := fmt.Errorf("api key has no %s scope", scope)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.Next()
+	}
+}
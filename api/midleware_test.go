@@ -7,9 +7,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dubass83/simplebank/apikey"
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/revocation"
 	"github.com/dubass83/simplebank/token"
 	"github.com/dubass83/simplebank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,6 +38,7 @@ func AddAuthorization(
 func TestAuthMidleware(t *testing.T) {
 	testCases := []struct {
 		name          string
+		revoked       error
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
@@ -80,6 +86,52 @@ func TestAuthMidleware(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "TotpChallengeToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				challenge, _, err := tokenMaker.CreateToken("user", util.DepositorRole, time.Minute,
+					token.WithPurpose(token.PurposeTotpChallenge))
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, challenge))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RefreshToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				refresh, _, err := tokenMaker.CreateToken("user", util.DepositorRole, time.Minute,
+					token.WithPurpose(token.PurposeRefresh))
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, refresh))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ImpersonatedToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				impersonated, _, err := tokenMaker.CreateToken("user", util.DepositorRole, time.Minute,
+					token.WithActor("banker"))
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, impersonated))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:    "RevokedToken",
+			revoked: revocation.ErrIssuedBeforePassword,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				AddAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -90,7 +142,7 @@ func TestAuthMidleware(t *testing.T) {
 			authPath := "/auth"
 			server.router.GET(
 				authPath,
-				AuthMidleWare(server.tokenMaker),
+				AuthMidleWare(server.tokenMaker, revocationStub{err: tc.revoked}, server.apiKeys),
 				func(ctx *gin.Context) {
 					ctx.JSON(http.StatusOK, gin.H{})
 				},
@@ -106,3 +158,124 @@ func TestAuthMidleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthMidlewareApiKey(t *testing.T) {
+	user, _ := randomUser()
+	secret, secretHash, err := apikey.NewSecret()
+	require.NoError(t, err)
+	apiKey := db.ApiKey{
+		ID:         util.RandomInt(1, 1000),
+		Owner:      user.Username,
+		Name:       util.RandomString(6),
+		SecretHash: secretHash,
+		Scopes:     []string{util.ScopeAccountsRead},
+		ExpiredAt:  time.Now().Add(time.Hour),
+		CreatedAt:  time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		key           string
+		scope         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			key:   apikey.Format(apiKey.ID, secret),
+			scope: util.ScopeAccountsRead,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(apiKey, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "MissingScope",
+			key:   apikey.Format(apiKey.ID, secret),
+			scope: util.ScopeTransfersWrite,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(apiKey, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "WrongSecret",
+			key:   apikey.Format(apiKey.ID, util.RandomString(64)),
+			scope: util.ScopeAccountsRead,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(apiKey, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "BadFormat",
+			key:   secret,
+			scope: util.ScopeAccountsRead,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+
+			authPath := "/auth"
+			server.router.GET(
+				authPath,
+				AuthMidleWare(server.tokenMaker, server.revocation, server.apiKeys),
+				ScopeMidleWare(tc.scope),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+			request.Header.Set(authorizationHeaderKey, fmt.Sprintf("ApiKey %s", tc.key))
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
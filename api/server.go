@@ -1,9 +1,11 @@
 package api
 
 import (
-	"fmt"
-
+	"github.com/dubass83/simplebank/apikey"
 	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/fraud"
+	"github.com/dubass83/simplebank/refresh"
+	"github.com/dubass83/simplebank/revocation"
 	"github.com/dubass83/simplebank/token"
 	"github.com/dubass83/simplebank/util"
 	"github.com/gin-gonic/gin"
@@ -12,22 +14,26 @@ import (
 )
 
 type Server struct {
-	config     util.Config
-	store      db.Store
-	tokenMaker token.Maker
-	router     *gin.Engine
+	config         util.Config
+	store          db.Store
+	tokenMaker     token.Maker
+	fraudEvaluator fraud.Evaluator
+	revocation     revocation.Checker
+	apiKeys        *apikey.Authenticator
+	refreshTokens  *refresh.Rotator
+	router         *gin.Engine
 }
 
-func NewServer(config util.Config, store db.Store) (*Server, error) {
-	tokenMaker, err := token.NewJwtMaker(config.TokenString)
-	if err != nil {
-		return nil, fmt.Errorf("can not create token maker: %w", err)
-	}
+func NewServer(config util.Config, store db.Store, tokenMaker token.Maker) (*Server, error) {
 	server := &Server{
-		config:     config,
-		store:      store,
-		tokenMaker: tokenMaker,
+		config:         config,
+		store:          store,
+		tokenMaker:     tokenMaker,
+		fraudEvaluator: fraud.NewEngine(store, config),
+		revocation:     revocation.NewCachedChecker(store, config.AuthCacheTTL),
+		apiKeys:        apikey.NewAuthenticator(store),
 	}
+	server.refreshTokens = refresh.NewRotator(store, tokenMaker, server.revocation, config.RefreshTokenDuration)
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
@@ -43,15 +49,15 @@ func (server *Server) setupRouter() {
 	router.POST("/users/login", server.loginUser)
 	router.POST("/tokens/renew_access", server.renewAccessToken)
 
-	authRouters := router.Group("/").Use(AuthMidleWare(server.tokenMaker))
+	authRouters := router.Group("/").Use(AuthMidleWare(server.tokenMaker, server.revocation, server.apiKeys))
 
-	authRouters.GET("/users/:username", server.getUser)
-	authRouters.POST("/accounts", server.createAccount)
-	authRouters.GET("/accounts/:id", server.getAccount)
-	authRouters.GET("/accounts", server.listAccount)
-	authRouters.PUT("/accounts/:id", server.updateAccount)
-	authRouters.DELETE("/accounts/:id", server.deleteAccount)
-	authRouters.POST("/transfers", server.createTransfer)
+	authRouters.GET("/users/:username", ScopeMidleWare(util.ScopeUsersRead), server.getUser)
+	authRouters.POST("/accounts", ScopeMidleWare(util.ScopeAccountsWrite), server.createAccount)
+	authRouters.GET("/accounts/:id", ScopeMidleWare(util.ScopeAccountsRead), server.getAccount)
+	authRouters.GET("/accounts", ScopeMidleWare(util.ScopeAccountsRead), server.listAccount)
+	authRouters.PUT("/accounts/:id", ScopeMidleWare(util.ScopeAccountsWrite), server.updateAccount)
+	authRouters.DELETE("/accounts/:id", ScopeMidleWare(util.ScopeAccountsWrite), server.deleteAccount)
+	authRouters.POST("/transfers", ScopeMidleWare(util.ScopeTransfersWrite), server.createTransfer)
 
 	server.router = router
 }
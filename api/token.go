@@ -2,12 +2,13 @@ package api
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"time"
 
-	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/refresh"
+	"github.com/dubass83/simplebank/token"
 	"github.com/gin-gonic/gin"
+	gofrsuuid "github.com/gofrs/uuid/v5"
 	"github.com/google/uuid"
 )
 
@@ -16,8 +17,11 @@ type renewAccessTokenRequest struct {
 }
 
 type renewAccessTokenResponse struct {
-	AccessToken          string    `json:"access_token"`
-	AccessTokenExpiresAt time.Time `json:"access_token_exp_at"`
+	SessionID             uuid.UUID `json:"session_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_exp_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_exp_at"`
 }
 
 func (srv *Server) renewAccessToken(ctx *gin.Context) {
@@ -28,56 +32,44 @@ func (srv *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	payload, err := srv.tokenMaker.VerifyToken(req.RefreshToken)
+	payload, session, err := srv.refreshTokens.Verify(ctx, req.RefreshToken)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		ctx.JSON(refreshErrorCode(err), errorResponse(err))
 		return
 	}
 
-	session, err := srv.store.GetSession(ctx, uuid.UUID(payload.ID))
+	refreshToken, newSession, err := srv.refreshTokens.Rotate(ctx, session, payload.Role, ctx.Request.UserAgent(), ctx.ClientIP())
 	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	if session.IsBloked {
-		err := fmt.Errorf("session is bloked")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
-	if session.Username != payload.Username {
-		err := fmt.Errorf("user in session does not much to user in refresh Payload")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
-	if session.RefreshToken != req.RefreshToken {
-		err := fmt.Errorf("refresh token does not much")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
-	if time.Now().After(session.ExpiredAt) {
-		err := fmt.Errorf("expired session")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		ctx.JSON(refreshErrorCode(err), errorResponse(err))
 		return
 	}
 
-	token, accessPayload, err := srv.tokenMaker.CreateToken(session.Username, payload.Role, srv.config.TokenDuration)
+	accessToken, accessPayload, err := srv.tokenMaker.CreateToken(session.Username, payload.Role, srv.config.TokenDuration,
+		token.WithSessionID(gofrsuuid.UUID(newSession.ID)))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
 	rsp := renewAccessTokenResponse{
-		AccessToken:          token,
-		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+		SessionID:             newSession.ID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: newSession.ExpiredAt,
 	}
 
 	ctx.JSON(http.StatusOK, rsp)
 }
+
+// refreshErrorCode return http status for the error of the refresh token rotation
+func refreshErrorCode(err error) int {
+	switch {
+	case errors.Is(err, refresh.ErrSessionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, refresh.ErrInvalidToken), errors.Is(err, refresh.ErrTokenReused):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
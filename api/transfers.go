@@ -4,10 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/fraud"
 	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/totp"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type createTransferRequest struct {
@@ -15,6 +19,7 @@ type createTransferRequest struct {
 	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
 	Amount        int64  `json:"amount" binding:"required,gt=0"`
 	Carrency      string `json:"carrency" binding:"required,currency"`
+	TotpCode      string `json:"totp_code"`
 }
 
 func (srv *Server) createTransfer(ctx *gin.Context) {
@@ -34,15 +39,89 @@ func (srv *Server) createTransfer(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-	_, ok = srv.validAccount(ctx, req.ToAccountID, req.Carrency)
+	toAccount, ok := srv.validAccount(ctx, req.ToAccountID, req.Carrency)
 	if !ok {
 		return
 	}
 
+	// users without verified email can make only small transfers
+	if srv.config.UnverifiedMaxTransfer > 0 && req.Amount > srv.config.UnverifiedMaxTransfer {
+		user, err := srv.store.GetUser(ctx, authPayload.Username)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if !user.IsEmailVerified {
+			err := fmt.Errorf("verify your email to transfer more than %d", srv.config.UnverifiedMaxTransfer)
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+	}
+
+	// large transfers of users with two-factor authentication need fresh totp code
+	if srv.config.TotpTransferAmount > 0 && req.Amount > srv.config.TotpTransferAmount {
+		user, err := srv.store.GetUser(ctx, authPayload.Username)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if user.TotpEnabled {
+			// the time step of the accepted code is stored, so the code can not be replayed
+			step, ok := totp.ValidateStep(req.TotpCode, user.TotpSecret.String, time.Now())
+			if ok {
+				used, err := srv.store.UseTotpStep(ctx, db.UseTotpStepParams{
+					Username: user.Username,
+					Step:     step,
+				})
+				if err != nil {
+					ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+					return
+				}
+				ok = used > 0
+			}
+			if !ok {
+				err := fmt.Errorf("fresh totp code is required for transfer above %d", srv.config.TotpTransferAmount)
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+	}
+
+	decision, err := srv.fraudEvaluator.Evaluate(ctx, fraud.Transfer{
+		Username:    authPayload.Username,
+		SessionID:   uuid.UUID(authPayload.SessionID),
+		FromAccount: fromAccount,
+		ToAccount:   toAccount,
+		Amount:      req.Amount,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if decision.Action == fraud.ActionBlock {
+		err := errors.New("transfer is blocked by fraud check")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
 	arg := db.TransferTxParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		Ammount:       req.Amount,
+		FromAccountID:   req.FromAccountID,
+		ToAccountID:     req.ToAccountID,
+		Ammount:         req.Amount,
+		FraudDecisionID: decision.ID,
+		InitiatedBy:     authPayload.Username,
+	}
+
+	// flagged and large transfers wait for the banker review
+	if decision.Action == fraud.ActionReview ||
+		(srv.config.TransferReviewAmount > 0 && req.Amount > srv.config.TransferReviewAmount) {
+		transfer, err := srv.store.HoldTransferTx(ctx, arg)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusAccepted, transfer)
+		return
 	}
 
 	transfer, err := srv.store.TransferTx(ctx, arg)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/token"
 	"github.com/dubass83/simplebank/util"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -123,29 +124,59 @@ func (srv *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	failures, ok := srv.throttleLogin(ctx, req.Username)
+	if !ok {
+		return
+	}
+
 	user, err := srv.store.GetUser(ctx, req.Username)
 	if err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			util.CheckDummyPassword(req.Password)
+			srv.failLogin(ctx, req.Username, failures, false)
 			return
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	// password of the locked user is not checked until the lockout ends
+	if user.LockedUntil.Valid && time.Now().Before(user.LockedUntil.Time) {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
+		return
+	}
+
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		srv.failLogin(ctx, user.Username, failures, true)
+		return
+	}
+
+	if failures > 0 {
+		err = srv.store.DeleteFailedLogins(ctx, user.Username)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	// the second factor is verified only by the gRPC api
+	if user.TotpEnabled {
+		err := errors.New("two-factor authentication is enabled, login with /v1/login_user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	token, accessPayload, err := srv.tokenMaker.CreateToken(req.Username, user.Role, srv.config.TokenDuration)
+	refreshToken, refreshPayload, err := srv.tokenMaker.CreateToken(req.Username, user.Role, srv.config.RefreshTokenDuration,
+		token.WithPurpose(token.PurposeRefresh))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	refreshToken, refreshPayload, err := srv.tokenMaker.CreateToken(req.Username, user.Role, srv.config.RefreshTokenDuration)
+	// id of the refresh token is the id of the session
+	accessToken, accessPayload, err := srv.tokenMaker.CreateToken(req.Username, user.Role, srv.config.TokenDuration,
+		token.WithSessionID(refreshPayload.ID))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -159,6 +190,7 @@ func (srv *Server) loginUser(ctx *gin.Context) {
 		ClientIp:     ctx.ClientIP(),
 		IsBloked:     false,
 		ExpiredAt:    refreshPayload.ExpiredAt,
+		FamilyID:     uuid.UUID(refreshPayload.ID),
 	})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -167,7 +199,7 @@ func (srv *Server) loginUser(ctx *gin.Context) {
 
 	rsp := loginUserResponse{
 		SesionID:              session.ID,
-		AccessToken:           token,
+		AccessToken:           accessToken,
 		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
 		RefreshToken:          refreshToken,
 		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
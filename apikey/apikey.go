@@ -0,0 +1,118 @@
+package apikey
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/gofrs/uuid/v5"
+)
+
+// AuthorizationType scheme of the authorization header: "ApiKey <id>.<secret>"
+const AuthorizationType = "apikey"
+
+// secretSize random bytes in the secret part of the key
+const secretSize = 32
+
+var (
+	ErrInvalidKey = errors.New("invalid api key")
+	ErrKeyRevoked = errors.New("api key is revoked")
+	ErrKeyExpired = errors.New("api key is expired")
+)
+
+// NewSecret return random secret for the new key and its hash stored in db
+func NewSecret() (secret string, secretHash string, err error) {
+	secret, err = util.RandomSecret(secretSize)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, util.HashSecretCode(secret), nil
+}
+
+// Format join id and secret into the key given to the user
+func Format(id int64, secret string) string {
+	return fmt.Sprintf("%d.%s", id, secret)
+}
+
+// Parse split the key into id and secret
+func Parse(key string) (int64, string, error) {
+	idPart, secret, ok := strings.Cut(key, ".")
+	if !ok || secret == "" {
+		return 0, "", ErrInvalidKey
+	}
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, "", ErrInvalidKey
+	}
+	return id, secret, nil
+}
+
+// Authenticator verify API keys against the store
+type Authenticator struct {
+	store db.Store
+	now   func() time.Time
+}
+
+// NewAuthenticator creates a new Authenticator
+func NewAuthenticator(store db.Store) *Authenticator {
+	return &Authenticator{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Authenticate check the key and return payload of the key owner restricted
+// to the scopes of the key
+func (auth *Authenticator) Authenticate(ctx context.Context, key string) (*token.Payload, error) {
+	id, secret, err := Parse(key)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := auth.store.GetApiKey(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, ErrInvalidKey
+		}
+		return nil, fmt.Errorf("cannot get api key: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(util.HashSecretCode(secret)), []byte(apiKey.SecretHash)) != 1 {
+		return nil, ErrInvalidKey
+	}
+	if apiKey.IsRevoked {
+		return nil, ErrKeyRevoked
+	}
+	if auth.now().After(apiKey.ExpiredAt) {
+		return nil, ErrKeyExpired
+	}
+
+	user, err := auth.store.GetUser(ctx, apiKey.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get owner of api key: %w", err)
+	}
+
+	// nil scopes would leave the payload unrestricted
+	scopes := apiKey.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	payloadID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	return &token.Payload{
+		ID:        payloadID,
+		Username:  user.Username,
+		Role:      user.Role,
+		IssuedAt:  apiKey.CreatedAt,
+		ExpiredAt: apiKey.ExpiredAt,
+		Scopes:    scopes,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	id, secret, err := Parse(Format(42, "secret"))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), id)
+	require.Equal(t, "secret", secret)
+
+	for _, key := range []string{"", "secret", "42", "42.", "abc.secret", "-1.secret", "0.secret"} {
+		_, _, err := Parse(key)
+		require.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	user := db.User{Username: util.RandomOwner(), Role: util.DepositorRole}
+	secret, secretHash, err := NewSecret()
+	require.NoError(t, err)
+	apiKey := db.ApiKey{
+		ID:         util.RandomInt(1, 1000),
+		Owner:      user.Username,
+		SecretHash: secretHash,
+		Scopes:     []string{util.ScopeAccountsRead},
+		ExpiredAt:  time.Now().Add(time.Hour),
+		CreatedAt:  time.Now().Add(-time.Hour),
+	}
+
+	testCases := []struct {
+		name       string
+		key        string
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			key:  Format(apiKey.ID, secret),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(apiKey, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		}, {
+			name: "NotFound",
+			key:  Format(apiKey.ID, secret),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(db.ApiKey{}, db.ErrRecordNotFound)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidKey)
+			},
+		}, {
+			name: "WrongSecret",
+			key:  Format(apiKey.ID, secret+"0"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(apiKey, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidKey)
+			},
+		}, {
+			name: "Revoked",
+			key:  Format(apiKey.ID, secret),
+			buildStubs: func(store *mockdb.MockStore) {
+				revoked := apiKey
+				revoked.IsRevoked = true
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(revoked, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrKeyRevoked)
+			},
+		}, {
+			name: "Expired",
+			key:  Format(apiKey.ID, secret),
+			buildStubs: func(store *mockdb.MockStore) {
+				expired := apiKey
+				expired.ExpiredAt = time.Now().Add(-time.Minute)
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(expired, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrKeyExpired)
+			},
+		}, {
+			name: "InternalError",
+			key:  Format(apiKey.ID, secret),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(db.ApiKey{}, sql.ErrConnDone)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			payload, err := NewAuthenticator(store).Authenticate(context.Background(), tc.key)
+			tc.checkErr(t, err)
+			if err != nil {
+				require.Nil(t, payload)
+				return
+			}
+			require.Equal(t, user.Username, payload.Username)
+			require.Equal(t, user.Role, payload.Role)
+			require.Equal(t, apiKey.Scopes, payload.Scopes)
+			require.Equal(t, apiKey.ExpiredAt, payload.ExpiredAt)
+		})
+	}
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "fraud_decisions";
+
+DROP INDEX IF EXISTS "transfers_from_account_id_created_at_idx";
@@ -0,0 +1,30 @@
+CREATE TABLE "fraud_decisions" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "score" int NOT NULL,
+  "action" varchar NOT NULL,
+  "signals" jsonb NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "fraud_decisions" ("username");
+
+CREATE INDEX ON "fraud_decisions" ("from_account_id");
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+COMMENT ON COLUMN "fraud_decisions"."action" IS 'allow, review or block';
+
+COMMENT ON COLUMN "fraud_decisions"."signals" IS 'scores and reasons of the triggered rules';
+
+ALTER TABLE "fraud_decisions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "fraud_decisions" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "fraud_decisions" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "fraud_decisions" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,7 @@
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "reviewed_at";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "reviewed_by";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "initiated_by";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "status";
@@ -0,0 +1,15 @@
+ALTER TABLE "transfers" ADD COLUMN "status" varchar NOT NULL DEFAULT 'posted';
+
+ALTER TABLE "transfers" ADD COLUMN "initiated_by" varchar;
+
+ALTER TABLE "transfers" ADD COLUMN "reviewed_by" varchar;
+
+ALTER TABLE "transfers" ADD COLUMN "reviewed_at" timestamptz;
+
+CREATE INDEX ON "transfers" ("status");
+
+COMMENT ON COLUMN "transfers"."status" IS 'posted, pending_review or rejected';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reviewed_by") REFERENCES "users" ("username");
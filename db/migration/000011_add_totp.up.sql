@@ -0,0 +1,17 @@
+ALTER TABLE "users" ADD COLUMN "totp_secret" varchar;
+
+ALTER TABLE "users" ADD COLUMN "totp_enabled" bool NOT NULL DEFAULT false;
+
+CREATE TABLE "totp_recovery_codes" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "totp_recovery_codes" ("username", "code_hash");
+
+COMMENT ON COLUMN "totp_recovery_codes"."code_hash" IS 'sha256 of the normalized recovery code';
+
+ALTER TABLE "totp_recovery_codes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "failed_logins";
+
+ALTER TABLE "users" DROP COLUMN "locked_until";
@@ -0,0 +1,14 @@
+ALTER TABLE "users" ADD COLUMN "locked_until" timestamptz;
+
+CREATE TABLE "failed_logins" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "failed_logins" ("username", "created_at");
+
+CREATE INDEX ON "failed_logins" ("client_ip", "created_at");
+
+COMMENT ON COLUMN "failed_logins"."username" IS 'not a foreign key, guesses of unknown usernames are counted too';
@@ -0,0 +1,16 @@
+CREATE TABLE "api_keys" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "secret_hash" varchar NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "is_revoked" bool NOT NULL DEFAULT false,
+  "expired_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "api_keys"."secret_hash" IS 'sha256 of the secret part of the key';
+
+CREATE INDEX ON "api_keys" ("owner");
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
@@ -0,0 +1,11 @@
+CREATE TABLE "roles" (
+  "name" varchar PRIMARY KEY,
+  "permissions" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+INSERT INTO "roles" ("name", "permissions") VALUES
+  ('depositor', '{}'),
+  ('banker', '{accounts:read:any,users:update:any,users:unlock,sessions:revoke:any,transfers:approve,roles:assign}');
+
+ALTER TABLE "users" ADD FOREIGN KEY ("role") REFERENCES "roles" ("name");
@@ -0,0 +1,13 @@
+CREATE TABLE "login_events" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "user_agent" varchar NOT NULL,
+  "success" bool NOT NULL,
+  "reason" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "login_events"."username" IS 'not a foreign key, attempts with unknown usernames are recorded too';
+
+CREATE INDEX ON "login_events" ("username", "created_at");
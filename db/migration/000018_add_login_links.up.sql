@@ -0,0 +1,12 @@
+CREATE TABLE "login_links" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "secret_code_hash" varchar NOT NULL,
+  "is_used" bool NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expired_at" timestamptz NOT NULL DEFAULT (now() + interval '15 minutes')
+);
+
+COMMENT ON COLUMN "login_links"."secret_code_hash" IS 'sha256 of the secret code sent by email';
+
+ALTER TABLE "login_links" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
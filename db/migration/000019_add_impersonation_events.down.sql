@@ -0,0 +1,3 @@
+UPDATE "roles" SET "permissions" = array_remove("permissions", 'users:impersonate') WHERE "name" = 'banker';
+
+DROP TABLE IF EXISTS "impersonation_events";
@@ -0,0 +1,24 @@
+CREATE TABLE "impersonation_events" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "subject" varchar NOT NULL,
+  "token_id" uuid NOT NULL,
+  "method" varchar NOT NULL,
+  "allowed" bool NOT NULL,
+  "reason" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "impersonation_events"."actor" IS 'banker acting as the subject';
+
+COMMENT ON COLUMN "impersonation_events"."reason" IS 'reason given by the banker when the impersonation started';
+
+CREATE INDEX ON "impersonation_events" ("actor", "created_at");
+
+CREATE INDEX ON "impersonation_events" ("subject", "created_at");
+
+ALTER TABLE "impersonation_events" ADD FOREIGN KEY ("actor") REFERENCES "users" ("username");
+
+ALTER TABLE "impersonation_events" ADD FOREIGN KEY ("subject") REFERENCES "users" ("username");
+
+UPDATE "roles" SET "permissions" = array_append("permissions", 'users:impersonate') WHERE "name" = 'banker';
@@ -0,0 +1,9 @@
+UPDATE "roles" SET "permissions" = array_remove("permissions", 'users:read:any') WHERE "name" = 'banker';
+
+DROP INDEX IF EXISTS "accounts_carrency_id_idx";
+
+DROP INDEX IF EXISTS "users_created_at_idx";
+
+DROP INDEX IF EXISTS "users_lower_idx";
+
+DROP INDEX IF EXISTS "users_username_idx";
@@ -0,0 +1,9 @@
+CREATE INDEX ON "users" ("username" varchar_pattern_ops);
+
+CREATE INDEX ON "users" (lower("full_name") text_pattern_ops);
+
+CREATE INDEX ON "users" ("created_at");
+
+CREATE INDEX ON "accounts" ("carrency", "id");
+
+UPDATE "roles" SET "permissions" = array_append("permissions", 'users:read:any') WHERE "name" = 'banker';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "totp_used_steps";
+
+DROP TABLE IF EXISTS "totp_challenges";
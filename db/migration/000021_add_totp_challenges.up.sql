@@ -0,0 +1,21 @@
+CREATE TABLE "totp_challenges" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expired_at" timestamptz NOT NULL
+);
+
+COMMENT ON COLUMN "totp_challenges"."id" IS 'id of the challenge token returned by login';
+
+ALTER TABLE "totp_challenges" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+CREATE TABLE "totp_used_steps" (
+  "username" varchar PRIMARY KEY,
+  "step" bigint NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "totp_used_steps"."step" IS 'last accepted time step, codes of this and earlier steps are rejected';
+
+ALTER TABLE "totp_used_steps" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
@@ -0,0 +1,9 @@
+CREATE TABLE "verify_email_resends" (
+  "username" varchar PRIMARY KEY,
+  "sent" bigint NOT NULL DEFAULT 1,
+  "window_started_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "verify_email_resends"."sent" IS 'verify emails requested since window_started_at';
+
+ALTER TABLE "verify_email_resends" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
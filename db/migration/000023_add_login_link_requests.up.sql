@@ -0,0 +1,9 @@
+CREATE TABLE "login_link_requests" (
+  "key" varchar PRIMARY KEY,
+  "sent" bigint NOT NULL DEFAULT 1,
+  "window_started_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "login_link_requests"."key" IS 'email or client ip the requests are counted for';
+
+COMMENT ON COLUMN "login_link_requests"."sent" IS 'login links requested since window_started_at';
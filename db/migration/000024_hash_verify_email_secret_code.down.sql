@@ -0,0 +1,6 @@
+-- the plain codes can not be restored, so invalidate the pending links
+UPDATE "verify_emails" SET "is_used" = true WHERE "is_used" = false;
+
+COMMENT ON COLUMN "verify_emails"."secret_code_hash" IS NULL;
+
+ALTER TABLE "verify_emails" RENAME COLUMN "secret_code_hash" TO "secret_code";
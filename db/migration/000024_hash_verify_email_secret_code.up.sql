@@ -0,0 +1,5 @@
+ALTER TABLE "verify_emails" RENAME COLUMN "secret_code" TO "secret_code_hash";
+
+UPDATE "verify_emails" SET "secret_code_hash" = encode(sha256("secret_code_hash"::bytea), 'hex');
+
+COMMENT ON COLUMN "verify_emails"."secret_code_hash" IS 'sha256 of the secret code sent by email';
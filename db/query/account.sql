@@ -22,6 +22,21 @@ ORDER BY id
 LIMIT $2
 OFFSET $3;
 
+-- name: SearchAccounts :many
+SELECT * FROM accounts
+WHERE
+  (sqlc.narg('owner')::varchar IS NULL OR owner = sqlc.narg('owner'))
+  AND (sqlc.narg('carrency')::varchar IS NULL OR carrency = sqlc.narg('carrency'))
+  AND id > sqlc.arg('after_id')
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: CountAccounts :one
+SELECT count(*) FROM accounts
+WHERE
+  (sqlc.narg('owner')::varchar IS NULL OR owner = sqlc.narg('owner'))
+  AND (sqlc.narg('carrency')::varchar IS NULL OR carrency = sqlc.narg('carrency'));
+
 -- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
@@ -36,4 +51,7 @@ RETURNING *;
 
 -- name: DeleteAccount :exec
 DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: NotifyAccountEvent :exec
+SELECT pg_notify('account_events', sqlc.arg(payload)::text);
@@ -0,0 +1,22 @@
+-- name: CreateApiKey :one
+INSERT INTO api_keys (
+  owner, name, secret_hash, scopes, expired_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetApiKey :one
+SELECT * FROM api_keys
+WHERE id = $1 LIMIT 1;
+
+-- name: ListApiKeys :many
+SELECT * FROM api_keys
+WHERE owner = $1
+ORDER BY id;
+
+-- name: RevokeApiKey :one
+UPDATE api_keys
+SET is_revoked = true
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,21 @@
+-- name: CreateFailedLogin :one
+INSERT INTO failed_logins (
+  username, client_ip
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: CountFailedLoginsByUsername :one
+SELECT count(*) FROM failed_logins
+WHERE username = @username
+AND created_at > @since;
+
+-- name: CountFailedLoginsByClientIp :one
+SELECT count(*) FROM failed_logins
+WHERE client_ip = @client_ip
+AND created_at > @since;
+
+-- name: DeleteFailedLogins :exec
+DELETE FROM failed_logins
+WHERE username = $1;
@@ -0,0 +1,16 @@
+-- name: CreateFraudDecision :one
+INSERT INTO fraud_decisions (
+  username, from_account_id, to_account_id, amount, score, action, signals
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetFraudDecision :one
+SELECT * FROM fraud_decisions
+WHERE id = $1 LIMIT 1;
+
+-- name: SetFraudDecisionTransfer :exec
+UPDATE fraud_decisions
+SET transfer_id = sqlc.arg(transfer_id)
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,7 @@
+-- name: CreateImpersonationEvent :one
+INSERT INTO impersonation_events (
+  actor, subject, token_id, method, allowed, reason
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
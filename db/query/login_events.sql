@@ -0,0 +1,21 @@
+-- name: CreateLoginEvent :one
+INSERT INTO login_events (
+  username, client_ip, user_agent, success, reason
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: ListLoginEvents :many
+SELECT * FROM login_events
+WHERE username = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetLoginDeviceStats :one
+SELECT
+  count(*) FILTER (WHERE success) AS logins,
+  count(*) FILTER (WHERE success AND user_agent = @user_agent AND client_ip = @client_ip) AS device_logins
+FROM login_events
+WHERE username = @username;
@@ -0,0 +1,12 @@
+-- name: AddLoginLinkRequest :one
+INSERT INTO login_link_requests (
+  key
+) VALUES (
+  @key
+)
+ON CONFLICT (key) DO UPDATE
+SET sent = CASE WHEN login_link_requests.window_started_at > @window_start
+    THEN login_link_requests.sent + 1 ELSE 1 END,
+  window_started_at = CASE WHEN login_link_requests.window_started_at > @window_start
+    THEN login_link_requests.window_started_at ELSE now() END
+RETURNING sent;
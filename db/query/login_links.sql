@@ -0,0 +1,16 @@
+-- name: CreateLoginLink :one
+INSERT INTO login_links (
+  username, secret_code_hash
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: UseLoginLink :one
+UPDATE login_links
+SET is_used = true
+WHERE id = @id
+AND secret_code_hash = @secret_code_hash
+AND is_used = false
+AND expired_at > now()
+RETURNING *;
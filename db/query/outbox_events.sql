@@ -0,0 +1,37 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox_events (
+  event_type, payload
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: ClaimOutboxEvents :many
+UPDATE outbox_events
+SET
+  locked_until = sqlc.arg(locked_until),
+  attempts = attempts + 1
+WHERE id IN (
+  SELECT id FROM outbox_events
+  WHERE sent_at IS NULL
+  AND (locked_until IS NULL OR locked_until < now())
+  ORDER BY id
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkOutboxEventSent :exec
+UPDATE outbox_events
+SET
+  sent_at = now(),
+  locked_until = NULL,
+  last_error = NULL
+WHERE id = $1;
+
+-- name: MarkOutboxEventFailed :exec
+UPDATE outbox_events
+SET
+  last_error = sqlc.arg(last_error),
+  locked_until = sqlc.arg(retry_at)
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,24 @@
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets (
+  username, secret_code_hash
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: GetActivePasswordReset :one
+SELECT * FROM password_resets
+WHERE id = @id
+AND secret_code_hash = @secret_code_hash
+AND is_used = false
+AND expired_at > now()
+LIMIT 1;
+
+-- name: UsePasswordReset :one
+UPDATE password_resets
+SET is_used = true
+WHERE id = @id
+AND secret_code_hash = @secret_code_hash
+AND is_used = false
+AND expired_at > now()
+RETURNING *;
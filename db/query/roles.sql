@@ -0,0 +1,8 @@
+-- name: GetRole :one
+SELECT * FROM roles
+WHERE name = $1 LIMIT 1;
+
+-- name: GetUserPermissions :one
+SELECT roles.permissions FROM users
+JOIN roles ON roles.name = users.role
+WHERE users.username = $1 LIMIT 1;
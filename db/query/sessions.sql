@@ -1,11 +1,60 @@
 -- name: CreateSession :one
 INSERT INTO sessions (
-  id,  username, refresh_token, user_agent, client_ip, is_bloked, expired_at
+  id,  username, refresh_token, user_agent, client_ip, is_bloked, expired_at, family_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8
 )
 RETURNING *;
 
 -- name: GetSession :one
 SELECT * FROM sessions
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+WHERE id = $1 LIMIT 1;
+
+-- name: GetSessionForUpdate :one
+SELECT * FROM sessions
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetSessionFamilyStartedAt :one
+SELECT min(created_at)::timestamptz AS started_at FROM sessions
+WHERE family_id = $1;
+
+-- name: BlockSession :one
+UPDATE sessions
+SET is_bloked = true
+WHERE id = $1
+RETURNING *;
+
+-- name: ListActiveSessions :many
+SELECT * FROM sessions
+WHERE username = $1
+AND is_bloked = false
+AND expired_at > now()
+ORDER BY created_at DESC;
+
+-- name: BlockOtherSessions :execrows
+UPDATE sessions
+SET is_bloked = true
+WHERE username = sqlc.arg(username)
+AND id <> sqlc.arg(id)
+AND is_bloked = false;
+
+-- name: BlockUserSessions :execrows
+UPDATE sessions
+SET is_bloked = true
+WHERE username = $1
+AND is_bloked = false;
+
+-- name: RotateSession :one
+UPDATE sessions
+SET
+  is_bloked = true,
+  rotated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: BlockSessionFamily :execrows
+UPDATE sessions
+SET is_bloked = true
+WHERE family_id = $1
+AND is_bloked = false;
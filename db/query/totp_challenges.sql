@@ -0,0 +1,15 @@
+-- name: CreateTotpChallenge :one
+INSERT INTO totp_challenges (
+  id, username, expired_at
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: UseTotpChallenge :execrows
+UPDATE totp_challenges
+SET used_at = now()
+WHERE id = @id
+AND username = @username
+AND used_at IS NULL
+AND expired_at > now();
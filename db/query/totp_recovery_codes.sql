@@ -0,0 +1,18 @@
+-- name: CreateTotpRecoveryCode :one
+INSERT INTO totp_recovery_codes (
+  username, code_hash
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: DeleteTotpRecoveryCodes :exec
+DELETE FROM totp_recovery_codes
+WHERE username = $1;
+
+-- name: UseTotpRecoveryCode :execrows
+UPDATE totp_recovery_codes
+SET used_at = now()
+WHERE username = $1
+AND code_hash = $2
+AND used_at IS NULL;
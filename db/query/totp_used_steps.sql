@@ -0,0 +1,9 @@
+-- name: UseTotpStep :execrows
+INSERT INTO totp_used_steps (
+  username, step
+) VALUES (
+  @username, @step
+)
+ON CONFLICT (username) DO UPDATE
+SET step = EXCLUDED.step, updated_at = now()
+WHERE totp_used_steps.step < EXCLUDED.step;
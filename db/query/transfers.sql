@@ -1,8 +1,8 @@
 -- name: CreateTransfer :one
 INSERT INTO transfers (
-  from_account_id, to_account_id, amount
+  from_account_id, to_account_id, amount, status, initiated_by
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 )
 RETURNING *;
 
@@ -16,6 +16,27 @@ ORDER BY id
 LIMIT $1
 OFFSET $2;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListPendingTransfers :many
+SELECT * FROM transfers
+WHERE status = 'pending_review'
+ORDER BY id
+LIMIT $1
+OFFSET $2;
+
+-- name: ReviewTransfer :one
+UPDATE transfers
+SET
+  status = sqlc.arg(status),
+  reviewed_by = sqlc.arg(reviewed_by),
+  reviewed_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
 -- name: UpdateTransfer :one
 UPDATE transfers
 SET amount = $2
@@ -24,4 +45,25 @@ RETURNING *;
 
 -- name: DeleteTransfer :exec
 DELETE FROM transfers
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: CountTransfersFromAccountSince :one
+SELECT COUNT(*) FROM transfers
+WHERE from_account_id = sqlc.arg(from_account_id)
+AND created_at > sqlc.arg(since)
+AND status <> 'rejected';
+
+-- name: CountTransfersBetweenAccounts :one
+SELECT COUNT(*) FROM transfers
+WHERE from_account_id = sqlc.arg(from_account_id)
+AND to_account_id = sqlc.arg(to_account_id)
+AND status = sqlc.arg(status);
+
+-- name: GetAccountTransferStats :one
+SELECT
+  COUNT(*) AS transfer_count,
+  COALESCE(AVG(amount), 0)::bigint AS average_amount
+FROM transfers
+WHERE from_account_id = sqlc.arg(from_account_id)
+AND created_at > sqlc.arg(since)
+AND status = sqlc.arg(status);
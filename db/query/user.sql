@@ -10,6 +10,10 @@ RETURNING *;
 SELECT * FROM users
 WHERE username = $1 LIMIT 1;
 
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1 LIMIT 1;
+
 -- name: UpdateUser :one
 UPDATE users 
 SET 
@@ -20,4 +24,77 @@ SET
   password_changed_at = COALESCE(sqlc.narg('password_changed_at'), password_changed_at)
 WHERE 
   username = sqlc.arg('username')
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: SetUserTotpSecret :one
+UPDATE users
+SET
+  totp_secret = sqlc.arg('totp_secret'),
+  totp_enabled = false
+WHERE
+  username = sqlc.arg('username')
+RETURNING *;
+
+-- name: EnableUserTotp :one
+UPDATE users
+SET totp_enabled = true
+WHERE username = $1
+RETURNING *;
+
+-- name: LockUser :one
+UPDATE users
+SET locked_until = sqlc.arg('locked_until')
+WHERE username = sqlc.arg('username')
+RETURNING *;
+
+-- name: UnlockUser :one
+UPDATE users
+SET locked_until = NULL
+WHERE username = $1
+RETURNING *;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = sqlc.arg('role')
+WHERE username = sqlc.arg('username')
+RETURNING *;
+
+-- name: SetUserPendingEmail :one
+UPDATE users
+SET pending_email = sqlc.narg('pending_email')
+WHERE username = sqlc.arg('username')
+RETURNING *;
+
+-- name: ConfirmUserEmail :one
+UPDATE users
+SET
+  email = pending_email,
+  pending_email = NULL,
+  is_email_verified = true
+WHERE
+  username = sqlc.arg('username')
+  AND pending_email = sqlc.arg('email')
+RETURNING *;
+
+-- name: SearchUsers :many
+SELECT * FROM users
+WHERE
+  (sqlc.narg('username_prefix')::varchar IS NULL OR username LIKE sqlc.narg('username_prefix') || '%')
+  AND (sqlc.narg('email')::varchar IS NULL OR email = sqlc.narg('email'))
+  AND (sqlc.narg('full_name')::varchar IS NULL OR lower(full_name) LIKE lower(sqlc.narg('full_name')) || '%')
+  AND (sqlc.narg('is_email_verified')::bool IS NULL OR is_email_verified = sqlc.narg('is_email_verified'))
+  AND (sqlc.narg('created_from')::timestamptz IS NULL OR created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamptz IS NULL OR created_at < sqlc.narg('created_to'))
+  AND username > sqlc.arg('after_username')
+ORDER BY username
+LIMIT sqlc.arg('limit');
+
+-- name: CountUsers :one
+SELECT count(*) FROM users
+WHERE
+  (sqlc.narg('username_prefix')::varchar IS NULL OR username LIKE sqlc.narg('username_prefix') || '%')
+  AND (sqlc.narg('email')::varchar IS NULL OR email = sqlc.narg('email'))
+  AND (sqlc.narg('full_name')::varchar IS NULL OR lower(full_name) LIKE lower(sqlc.narg('full_name')) || '%')
+  AND (sqlc.narg('is_email_verified')::bool IS NULL OR is_email_verified = sqlc.narg('is_email_verified'))
+  AND (sqlc.narg('created_from')::timestamptz IS NULL OR created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamptz IS NULL OR created_at < sqlc.narg('created_to'));
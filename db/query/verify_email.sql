@@ -1,6 +1,6 @@
 -- name: CreateVerifyEmail :one
 INSERT INTO verify_emails (
-  username, email, secret_code
+  username, email, secret_code_hash
 ) VALUES (
   $1, $2, $3
 )
@@ -10,7 +10,18 @@ RETURNING *;
 UPDATE verify_emails
 SET is_used = true
 WHERE id = @id
-AND secret_code = @secret_code
+AND secret_code_hash = @secret_code_hash
 AND is_used = false
 AND expired_at > now() 
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: InvalidateVerifyEmails :exec
+UPDATE verify_emails
+SET is_used = true
+WHERE username = $1
+AND is_used = false;
+
+-- name: CountRecentVerifyEmails :one
+SELECT count(*) FROM verify_emails
+WHERE username = sqlc.arg('username')
+AND created_at > sqlc.arg('since');
@@ -0,0 +1,12 @@
+-- name: AddVerifyEmailResend :one
+INSERT INTO verify_email_resends (
+  username
+) VALUES (
+  @username
+)
+ON CONFLICT (username) DO UPDATE
+SET sent = CASE WHEN verify_email_resends.window_started_at > @window_start
+    THEN verify_email_resends.sent + 1 ELSE 1 END,
+  window_started_at = CASE WHEN verify_email_resends.window_started_at > @window_start
+    THEN verify_email_resends.window_started_at ELSE now() END
+RETURNING sent;
@@ -0,0 +1,68 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+  owner, url, event_types, secret
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions
+WHERE owner = $1
+AND is_active = true
+ORDER BY id;
+
+-- name: ListWebhookSubscriptionsForEvent :many
+SELECT * FROM webhook_subscriptions
+WHERE is_active = true
+AND sqlc.arg(event_type)::varchar = ANY(event_types)
+AND owner = ANY(sqlc.arg(owners)::varchar[])
+ORDER BY id;
+
+-- name: DeactivateWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET is_active = false
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  subscription_id, event_id, event_type, payload
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (subscription_id, event_id) DO UPDATE
+SET event_type = EXCLUDED.event_type
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE subscription_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateWebhookDelivery :one
+UPDATE webhook_deliveries
+SET
+  status = $2,
+  attempts = attempts + 1,
+  response_code = $3,
+  last_error = $4,
+  delivered_at = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: ResetWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = 'pending'
+WHERE id = $1
+RETURNING *;
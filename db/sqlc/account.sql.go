@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addToAccountBalance = `-- name: AddToAccountBalance :one
@@ -34,6 +36,25 @@ func (q *Queries) AddToAccountBalance(ctx context.Context, arg AddToAccountBalan
 	return i, err
 }
 
+const countAccounts = `-- name: CountAccounts :one
+SELECT count(*) FROM accounts
+WHERE
+  ($1::varchar IS NULL OR owner = $1)
+  AND ($2::varchar IS NULL OR carrency = $2)
+`
+
+type CountAccountsParams struct {
+	Owner    pgtype.Text `json:"owner"`
+	Carrency pgtype.Text `json:"carrency"`
+}
+
+func (q *Queries) CountAccounts(ctx context.Context, arg CountAccountsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countAccounts, arg.Owner, arg.Carrency)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
   owner, balance, carrency
@@ -149,6 +170,63 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 	return items, nil
 }
 
+const notifyAccountEvent = `-- name: NotifyAccountEvent :exec
+SELECT pg_notify('account_events', $1::text)
+`
+
+func (q *Queries) NotifyAccountEvent(ctx context.Context, payload string) error {
+	_, err := q.db.Exec(ctx, notifyAccountEvent, payload)
+	return err
+}
+
+const searchAccounts = `-- name: SearchAccounts :many
+SELECT id, owner, balance, carrency, created_at FROM accounts
+WHERE
+  ($1::varchar IS NULL OR owner = $1)
+  AND ($2::varchar IS NULL OR carrency = $2)
+  AND id > $3
+ORDER BY id
+LIMIT $4
+`
+
+type SearchAccountsParams struct {
+	Owner    pgtype.Text `json:"owner"`
+	Carrency pgtype.Text `json:"carrency"`
+	AfterID  int64       `json:"afterId"`
+	Limit    int32       `json:"limit"`
+}
+
+func (q *Queries) SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, searchAccounts,
+		arg.Owner,
+		arg.Carrency,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Carrency,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
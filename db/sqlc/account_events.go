@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AccountEventsChannel postgres channel used by NotifyAccountEvent query
+const AccountEventsChannel = "account_events"
+
+// AccountEvent notification about account balance change.
+// It is sent with pg_notify inside of the transaction, so listeners
+// receive it only after the transaction was committed.
+type AccountEvent struct {
+	AccountID   int64     `json:"account_id"`
+	Owner       string    `json:"owner"`
+	Balance     int64     `json:"balance"`
+	Currency    string    `json:"currency"`
+	EntryID     int64     `json:"entry_id"`
+	EntryAmount int64     `json:"entry_amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// publishAccountEvent send balance change of the account with the entry which caused it
+func publishAccountEvent(ctx context.Context, q *Queries, account Account, entry Entry) error {
+	payload, err := json.Marshal(AccountEvent{
+		AccountID:   account.ID,
+		Owner:       account.Owner,
+		Balance:     account.Balance,
+		Currency:    account.Carrency,
+		EntryID:     entry.ID,
+		EntryAmount: entry.Amount,
+		CreatedAt:   entry.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed marshal account event: %w", err)
+	}
+	return q.NotifyAccountEvent(ctx, string(payload))
+}
+
+// ListenAccountEvents listen account events channel on a dedicated connection
+// and call handler for every notification until context is canceled
+func (store *SQLStore) ListenAccountEvents(ctx context.Context, handler func(AccountEvent)) error {
+	poolConn, err := store.connPool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	// connection in LISTEN state must not go back to the pool
+	conn := poolConn.Hijack()
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN "+AccountEventsChannel)
+	if err != nil {
+		return fmt.Errorf("failed to listen %s: %w", AccountEventsChannel, err)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+
+		var event AccountEvent
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			// skip payloads which were not sent by publishAccountEvent
+			continue
+		}
+		handler(event)
+	}
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenAccountEvents(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	events := make(chan AccountEvent, 10)
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- testStore.ListenAccountEvents(ctx, func(event AccountEvent) {
+			if event.AccountID == account1.ID || event.AccountID == account2.ID {
+				events <- event
+			}
+		})
+	}()
+
+	// give the listener time to subscribe before the transfer is committed
+	time.Sleep(200 * time.Millisecond)
+
+	result, err := testStore.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Ammount:       10,
+	})
+	require.NoError(t, err)
+
+	received := make(map[int64]AccountEvent)
+	for len(received) < 2 {
+		select {
+		case event := <-events:
+			received[event.AccountID] = event
+		case <-ctx.Done():
+			t.Fatal("account events were not received")
+		}
+	}
+
+	require.Equal(t, result.FromAccount.Balance, received[account1.ID].Balance)
+	require.Equal(t, result.FromEntry.ID, received[account1.ID].EntryID)
+	require.Equal(t, int64(-10), received[account1.ID].EntryAmount)
+	require.Equal(t, result.ToAccount.Balance, received[account2.ID].Balance)
+	require.Equal(t, result.ToEntry.ID, received[account2.ID].EntryID)
+
+	cancel()
+	require.NoError(t, <-listenErr)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/dubass83/simplebank/util"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -108,3 +109,31 @@ func TestListAccount(t *testing.T) {
 	}
 
 }
+
+func TestSearchAccounts(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	arg := SearchAccountsParams{
+		Owner: pgtype.Text{String: account2.Owner, Valid: true},
+		Limit: 5,
+	}
+	accounts, err := testStore.SearchAccounts(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []Account{account2}, accounts)
+
+	accounts, err = testStore.SearchAccounts(context.Background(), SearchAccountsParams{
+		AfterID: account1.ID,
+		Limit:   5,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, accounts)
+	require.Equal(t, account2.ID, accounts[0].ID)
+
+	count, err := testStore.CountAccounts(context.Background(), CountAccountsParams{
+		Owner:    pgtype.Text{String: account1.Owner, Valid: true},
+		Carrency: pgtype.Text{String: account1.Carrency, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomApiKey(t *testing.T, owner string) ApiKey {
+	arg := CreateApiKeyParams{
+		Owner:      owner,
+		Name:       util.RandomString(6),
+		SecretHash: util.HashSecretCode(util.RandomString(32)),
+		Scopes:     []string{util.ScopeAccountsRead, util.ScopeTransfersWrite},
+		ExpiredAt:  time.Now().Add(time.Hour),
+	}
+
+	apiKey, err := testStore.CreateApiKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, apiKey.ID)
+	require.Equal(t, arg.Owner, apiKey.Owner)
+	require.Equal(t, arg.Name, apiKey.Name)
+	require.Equal(t, arg.SecretHash, apiKey.SecretHash)
+	require.Equal(t, arg.Scopes, apiKey.Scopes)
+	require.False(t, apiKey.IsRevoked)
+	require.WithinDuration(t, arg.ExpiredAt, apiKey.ExpiredAt, time.Second)
+	require.NotZero(t, apiKey.CreatedAt)
+	return apiKey
+}
+
+func TestCreateApiKey(t *testing.T) {
+	createRandomApiKey(t, createRandomUser(t).Username)
+}
+
+func TestListAndRevokeApiKey(t *testing.T) {
+	user := createRandomUser(t)
+	first := createRandomApiKey(t, user.Username)
+	second := createRandomApiKey(t, user.Username)
+	createRandomApiKey(t, createRandomUser(t).Username)
+
+	revoked, err := testStore.RevokeApiKey(context.Background(), first.ID)
+	require.NoError(t, err)
+	require.True(t, revoked.IsRevoked)
+
+	apiKeys, err := testStore.ListApiKeys(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, apiKeys, 2)
+	require.Equal(t, first.ID, apiKeys[0].ID)
+	require.True(t, apiKeys[0].IsRevoked)
+	require.Equal(t, second.ID, apiKeys[1].ID)
+
+	apiKey, err := testStore.GetApiKey(context.Background(), second.ID)
+	require.NoError(t, err)
+	require.Equal(t, second.SecretHash, apiKey.SecretHash)
+}
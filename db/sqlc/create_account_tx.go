@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+)
+
+// CreateAccountTxParams struct with arguments for CreateAccountTx function
+type CreateAccountTxParams struct {
+	CreateAccountParams
+}
+
+// CreateAccountTxResult struct with results from CreateAccountTx function
+type CreateAccountTxResult struct {
+	Account
+}
+
+// CreateAccountTx public method to crete new Account in transaction
+// together with account.created event in the outbox
+func (store *SQLStore) CreateAccountTx(
+	ctx context.Context,
+	arg CreateAccountTxParams) (CreateAccountTxResult, error) {
+
+	var result CreateAccountTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Account, err = q.CreateAccount(ctx, arg.CreateAccountParams)
+		if err != nil {
+			return err
+		}
+		return writeOutboxEvent(ctx, q, EventAccountCreated, AccountCreatedEvent{
+			AccountID: result.Account.ID,
+			Owner:     result.Account.Owner,
+			Balance:   result.Account.Balance,
+			Currency:  result.Account.Carrency,
+			CreatedAt: result.Account.CreatedAt,
+		})
+	})
+
+	return result, err
+}
@@ -2,13 +2,11 @@ package db
 
 import (
 	"context"
-	// "github.com/dubass83/simplebank/worker"
 )
 
 // CreateUserTxParams struct with arguments for CreateUserTx function
 type CreateUserTxParams struct {
 	CreateUserParams
-	AfterFunc func(User) error
 }
 
 // CreateUserTxResults struct with results from CreateUserTx function
@@ -17,6 +15,7 @@ type CreateUserTxResult struct {
 }
 
 // CreateUserTx public method to crete new CreateUser in transaction
+// together with user.created event in the outbox
 func (store *SQLStore) CreateUserTx(
 	ctx context.Context,
 	arg CreateUserTxParams) (CreateUserTxResult, error) {
@@ -29,7 +28,10 @@ func (store *SQLStore) CreateUserTx(
 		if err != nil {
 			return err
 		}
-		return arg.AfterFunc(result.User)
+		return writeOutboxEvent(ctx, q, EventUserCreated, UserCreatedEvent{
+			Username: result.User.Username,
+			Email:    result.User.Email,
+		})
 	})
 
 	return result, err
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+)
+
+// EnableTotpTxParams struct with arguments for EnableTotpTx function
+type EnableTotpTxParams struct {
+	Username           string
+	RecoveryCodeHashes []string
+}
+
+// EnableTotpTx turn on the second factor for the user and replace
+// the recovery codes with the new ones
+func (store *SQLStore) EnableTotpTx(ctx context.Context, arg EnableTotpTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.EnableUserTotp(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteTotpRecoveryCodes(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		for _, codeHash := range arg.RecoveryCodeHashes {
+			_, err = q.CreateTotpRecoveryCode(ctx, CreateTotpRecoveryCodeParams{
+				Username: arg.Username,
+				CodeHash: codeHash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return user, err
+}
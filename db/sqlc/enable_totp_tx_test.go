@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableTotpTx(t *testing.T) {
+	user := createRandomUser(t)
+	require.False(t, user.TotpEnabled)
+	require.False(t, user.TotpSecret.Valid)
+
+	secret := util.RandomString(32)
+	user, err := testStore.SetUserTotpSecret(context.Background(), SetUserTotpSecretParams{
+		Username:   user.Username,
+		TotpSecret: pgtype.Text{String: secret, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, secret, user.TotpSecret.String)
+	require.False(t, user.TotpEnabled)
+
+	hashes := []string{util.RandomString(64), util.RandomString(64)}
+	user, err = testStore.EnableTotpTx(context.Background(), EnableTotpTxParams{
+		Username:           user.Username,
+		RecoveryCodeHashes: hashes,
+	})
+	require.NoError(t, err)
+	require.True(t, user.TotpEnabled)
+
+	arg := UseTotpRecoveryCodeParams{
+		Username: user.Username,
+		CodeHash: hashes[0],
+	}
+	used, err := testStore.UseTotpRecoveryCode(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), used)
+
+	// recovery code works only once
+	used, err = testStore.UseTotpRecoveryCode(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, used)
+}
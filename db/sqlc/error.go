@@ -25,3 +25,15 @@ func ErrorCode(err error) string {
 	}
 	return ""
 }
+
+var ErrTransferNotPending = errors.New("transfer is not pending review")
+
+var ErrSelfReview = errors.New("transfer can not be reviewed by its initiator")
+
+var ErrRefreshTokenReused = errors.New("refresh token was already rotated")
+
+var ErrVerifyEmailOutdated = errors.New("verify email link is for an outdated email address")
+
+var ErrTotpChallengeUsed = errors.New("totp challenge was already used or expired")
+
+var ErrTotpCodeUsed = errors.New("totp code or recovery code was already used")
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain event types written to the outbox_events table
+const (
+	EventUserCreated        = "user.created"
+	EventUserEmailVerified  = "user.email_verified"
+	EventTransferCreated    = "transfer.created"
+	EventAccountCreated     = "account.created"
+	EventTransferReviewed   = "transfer.reviewed"
+	EventRefreshTokenReused = "session.refresh_token_reused"
+	EventUserLocked         = "user.locked"
+	EventUserEmailChanged   = "user.email_change_requested"
+)
+
+// WebhookEventTypes domain events which can be delivered to webhook subscribers
+var WebhookEventTypes = []string{
+	EventTransferCreated,
+	EventAccountCreated,
+	EventUserEmailVerified,
+}
+
+// Statuses of the webhook_deliveries rows
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// UserCreatedEvent payload of the user.created event
+type UserCreatedEvent struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// UserEmailVerifiedEvent payload of the user.email_verified event
+type UserEmailVerifiedEvent struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// TransferCreatedEvent payload of the transfer.created event
+type TransferCreatedEvent struct {
+	TransferID    int64     `json:"transfer_id"`
+	FromAccountID int64     `json:"from_account_id"`
+	FromOwner     string    `json:"from_owner"`
+	ToAccountID   int64     `json:"to_account_id"`
+	ToOwner       string    `json:"to_owner"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TransferReviewedEvent payload of the transfer.reviewed event
+type TransferReviewedEvent struct {
+	TransferID    int64     `json:"transfer_id"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	Status        string    `json:"status"`
+	InitiatedBy   string    `json:"initiated_by"`
+	ReviewedBy    string    `json:"reviewed_by"`
+	ReviewedAt    time.Time `json:"reviewed_at"`
+}
+
+// RefreshTokenReusedEvent payload of the session.refresh_token_reused event
+type RefreshTokenReusedEvent struct {
+	Username        string    `json:"username"`
+	SessionID       uuid.UUID `json:"session_id"`
+	FamilyID        uuid.UUID `json:"family_id"`
+	UserAgent       string    `json:"user_agent"`
+	ClientIp        string    `json:"client_ip"`
+	RevokedSessions int64     `json:"revoked_sessions"`
+	DetectedAt      time.Time `json:"detected_at"`
+}
+
+// UserLockedEvent payload of the user.locked event
+type UserLockedEvent struct {
+	Username    string    `json:"username"`
+	UserAgent   string    `json:"user_agent"`
+	ClientIp    string    `json:"client_ip"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// UserEmailChangedEvent payload of the user.email_change_requested event,
+// OldEmail stays active until NewEmail is verified
+type UserEmailChangedEvent struct {
+	Username string `json:"username"`
+	OldEmail string `json:"old_email"`
+	NewEmail string `json:"new_email"`
+}
+
+// AccountCreatedEvent payload of the account.created event
+type AccountCreatedEvent struct {
+	AccountID int64     `json:"account_id"`
+	Owner     string    `json:"owner"`
+	Balance   int64     `json:"balance"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// writeOutboxEvent store domain event in the outbox inside of the running transaction
+func writeOutboxEvent(ctx context.Context, q *Queries, eventType string, event any) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed marshal %s event: %w", eventType, err)
+	}
+	_, err = q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		EventType: eventType,
+		Payload:   payload,
+	})
+	return err
+}
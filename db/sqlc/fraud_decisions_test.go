@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomFraudDecision(t *testing.T) FraudDecision {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	arg := CreateFraudDecisionParams{
+		Username:      account1.Owner,
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Score:         40,
+		Action:        "allow",
+		Signals:       []byte(`[{"rule":"velocity","score":40,"reason":"too many transfers"}]`),
+	}
+
+	decision, err := testStore.CreateFraudDecision(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, decision.ID)
+	require.Equal(t, arg.Username, decision.Username)
+	require.Equal(t, arg.Score, decision.Score)
+	require.Equal(t, arg.Action, decision.Action)
+	require.JSONEq(t, string(arg.Signals), string(decision.Signals))
+	require.False(t, decision.TransferID.Valid)
+	return decision
+}
+
+func TestSetFraudDecisionTransfer(t *testing.T) {
+	decision := createRandomFraudDecision(t)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID:   decision.FromAccountID,
+		ToAccountID:     decision.ToAccountID,
+		Ammount:         decision.Amount,
+		FraudDecisionID: decision.ID,
+	})
+	require.NoError(t, err)
+
+	updated, err := testStore.GetFraudDecision(context.Background(), decision.ID)
+	require.NoError(t, err)
+	require.Equal(t, pgtype.Int8{Int64: result.Transfer.ID, Valid: true}, updated.TransferID)
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateImpersonationEvent(t *testing.T) {
+	actor := createRandomUser(t)
+	subject := createRandomUser(t)
+
+	arg := CreateImpersonationEventParams{
+		Actor:   actor.Username,
+		Subject: subject.Username,
+		TokenID: uuid.New(),
+		Method:  "/pb.SimpleBank/GetUser",
+		Allowed: true,
+	}
+	event, err := testStore.CreateImpersonationEvent(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, event.ID)
+	require.Equal(t, arg.Actor, event.Actor)
+	require.Equal(t, arg.Subject, event.Subject)
+	require.Equal(t, arg.TokenID, event.TokenID)
+	require.Equal(t, arg.Method, event.Method)
+	require.True(t, event.Allowed)
+	require.Empty(t, event.Reason)
+	require.NotZero(t, event.CreatedAt)
+}
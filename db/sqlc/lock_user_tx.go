@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// LockUserTxParams struct with arguments for LockUserTx function,
+// UserAgent and ClientIp describe the client of the last failed login
+type LockUserTxParams struct {
+	Username    string
+	LockedUntil time.Time
+	UserAgent   string
+	ClientIp    string
+}
+
+// LockUserTx block logins of the user until LockedUntil, restart the count
+// of failed logins and record the security event for the user
+func (store *SQLStore) LockUserTx(ctx context.Context, arg LockUserTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.LockUser(ctx, LockUserParams{
+			Username: arg.Username,
+			LockedUntil: pgtype.Timestamptz{
+				Time:  arg.LockedUntil,
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteFailedLogins(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		return writeOutboxEvent(ctx, q, EventUserLocked, UserLockedEvent{
+			Username:    arg.Username,
+			UserAgent:   arg.UserAgent,
+			ClientIp:    arg.ClientIp,
+			LockedUntil: arg.LockedUntil,
+		})
+	})
+
+	return user, err
+}
+
+// UnlockUserTx allow the user to login again and forget previous failed logins
+func (store *SQLStore) UnlockUserTx(ctx context.Context, username string) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.UnlockUser(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteFailedLogins(ctx, username)
+	})
+
+	return user, err
+}
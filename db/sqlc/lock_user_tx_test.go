@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockUserTx(t *testing.T) {
+	user := createRandomUser(t)
+	require.False(t, user.LockedUntil.Valid)
+
+	for i := 0; i < 3; i++ {
+		_, err := testStore.CreateFailedLogin(context.Background(), CreateFailedLoginParams{
+			Username: user.Username,
+			ClientIp: "10.0.0.1",
+		})
+		require.NoError(t, err)
+	}
+	countArg := CountFailedLoginsByUsernameParams{
+		Username: user.Username,
+		Since:    time.Now().Add(-time.Minute),
+	}
+	failures, err := testStore.CountFailedLoginsByUsername(context.Background(), countArg)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), failures)
+
+	lockedUntil := time.Now().Add(15 * time.Minute)
+	locked, err := testStore.LockUserTx(context.Background(), LockUserTxParams{
+		Username:    user.Username,
+		LockedUntil: lockedUntil,
+		UserAgent:   "test-agent",
+		ClientIp:    "10.0.0.1",
+	})
+	require.NoError(t, err)
+	require.True(t, locked.LockedUntil.Valid)
+	require.WithinDuration(t, lockedUntil, locked.LockedUntil.Time, time.Second)
+
+	failures, err = testStore.CountFailedLoginsByUsername(context.Background(), countArg)
+	require.NoError(t, err)
+	require.Zero(t, failures)
+
+	unlocked, err := testStore.UnlockUserTx(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.False(t, unlocked.LockedUntil.Valid)
+}
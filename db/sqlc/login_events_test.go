@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomLoginEvent(t *testing.T, username, userAgent string, success bool) LoginEvent {
+	arg := CreateLoginEventParams{
+		Username:  username,
+		ClientIp:  "127.0.0.1",
+		UserAgent: userAgent,
+		Success:   success,
+		Reason:    util.RandomString(6),
+	}
+	event, err := testStore.CreateLoginEvent(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, event.ID)
+	require.Equal(t, arg.Username, event.Username)
+	require.Equal(t, arg.Success, event.Success)
+	require.Equal(t, arg.Reason, event.Reason)
+	require.NotZero(t, event.CreatedAt)
+	return event
+}
+
+func TestListLoginEvents(t *testing.T) {
+	user := createRandomUser(t)
+	first := createRandomLoginEvent(t, user.Username, "curl", false)
+	last := createRandomLoginEvent(t, user.Username, "curl", true)
+
+	events, err := testStore.ListLoginEvents(context.Background(), ListLoginEventsParams{
+		Username: user.Username,
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, last.ID, events[0].ID)
+	require.Equal(t, first.ID, events[1].ID)
+}
+
+func TestGetLoginDeviceStats(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomLoginEvent(t, user.Username, "curl", true)
+	createRandomLoginEvent(t, user.Username, "firefox", false)
+
+	stats, err := testStore.GetLoginDeviceStats(context.Background(), GetLoginDeviceStatsParams{
+		UserAgent: "firefox",
+		ClientIp:  "127.0.0.1",
+		Username:  user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stats.Logins)
+	require.Zero(t, stats.DeviceLogins)
+}
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddLoginLinkRequest(t *testing.T) {
+	arg := AddLoginLinkRequestParams{
+		Key:         "email:" + util.RandomEmail(),
+		WindowStart: time.Now().Add(-time.Hour),
+	}
+
+	for i := int64(1); i <= 3; i++ {
+		sent, err := testStore.AddLoginLinkRequest(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, i, sent)
+	}
+
+	// the window started after the first request, the counter starts again
+	arg.WindowStart = time.Now().Add(time.Minute)
+	sent, err := testStore.AddLoginLinkRequest(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), sent)
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseLoginLink(t *testing.T) {
+	user := createRandomUser(t)
+
+	codeHash := util.HashSecretCode(util.RandomString(32))
+	link, err := testStore.CreateLoginLink(context.Background(), CreateLoginLinkParams{
+		Username:       user.Username,
+		SecretCodeHash: codeHash,
+	})
+	require.NoError(t, err)
+	require.False(t, link.IsUsed)
+	require.True(t, link.ExpiredAt.After(link.CreatedAt))
+
+	_, err = testStore.UseLoginLink(context.Background(), UseLoginLinkParams{
+		ID:             link.ID,
+		SecretCodeHash: util.HashSecretCode(util.RandomString(32)),
+	})
+	require.Error(t, err)
+
+	used, err := testStore.UseLoginLink(context.Background(), UseLoginLinkParams{
+		ID:             link.ID,
+		SecretCodeHash: codeHash,
+	})
+	require.NoError(t, err)
+	require.True(t, used.IsUsed)
+	require.Equal(t, user.Username, used.Username)
+
+	// the link is single-use
+	_, err = testStore.UseLoginLink(context.Background(), UseLoginLinkParams{
+		ID:             link.ID,
+		SecretCodeHash: codeHash,
+	})
+	require.Error(t, err)
+}
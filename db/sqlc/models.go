@@ -19,6 +19,18 @@ type Account struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type ApiKey struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	Name  string `json:"name"`
+	// sha256 of the secret part of the key
+	SecretHash string    `json:"secretHash"`
+	Scopes     []string  `json:"scopes"`
+	IsRevoked  bool      `json:"isRevoked"`
+	ExpiredAt  time.Time `json:"expiredAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
 type Entry struct {
 	ID        int64       `json:"id"`
 	AccountID pgtype.Int8 `json:"accountId"`
@@ -27,6 +39,98 @@ type Entry struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type FailedLogin struct {
+	ID int64 `json:"id"`
+	// not a foreign key, guesses of unknown usernames are counted too
+	Username  string    `json:"username"`
+	ClientIp  string    `json:"clientIp"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type FraudDecision struct {
+	ID            int64  `json:"id"`
+	Username      string `json:"username"`
+	FromAccountID int64  `json:"fromAccountId"`
+	ToAccountID   int64  `json:"toAccountId"`
+	Amount        int64  `json:"amount"`
+	Score         int32  `json:"score"`
+	// allow, review or block
+	Action string `json:"action"`
+	// scores and reasons of the triggered rules
+	Signals    []byte      `json:"signals"`
+	TransferID pgtype.Int8 `json:"transferId"`
+	CreatedAt  time.Time   `json:"createdAt"`
+}
+
+type ImpersonationEvent struct {
+	ID int64 `json:"id"`
+	// banker acting as the subject
+	Actor   string    `json:"actor"`
+	Subject string    `json:"subject"`
+	TokenID uuid.UUID `json:"tokenId"`
+	Method  string    `json:"method"`
+	Allowed bool      `json:"allowed"`
+	// reason given by the banker when the impersonation started
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type LoginEvent struct {
+	ID int64 `json:"id"`
+	// not a foreign key, attempts with unknown usernames are recorded too
+	Username  string    `json:"username"`
+	ClientIp  string    `json:"clientIp"`
+	UserAgent string    `json:"userAgent"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type LoginLink struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// sha256 of the secret code sent by email
+	SecretCodeHash string    `json:"secretCodeHash"`
+	IsUsed         bool      `json:"isUsed"`
+	CreatedAt      time.Time `json:"createdAt"`
+	ExpiredAt      time.Time `json:"expiredAt"`
+}
+
+type LoginLinkRequest struct {
+	// email or client ip the requests are counted for
+	Key string `json:"key"`
+	// login links requested since window_started_at
+	Sent            int64     `json:"sent"`
+	WindowStartedAt time.Time `json:"windowStartedAt"`
+}
+
+type OutboxEvent struct {
+	ID          int64              `json:"id"`
+	EventType   string             `json:"eventType"`
+	Payload     []byte             `json:"payload"`
+	Attempts    int32              `json:"attempts"`
+	LastError   pgtype.Text        `json:"lastError"`
+	LockedUntil pgtype.Timestamptz `json:"lockedUntil"`
+	SentAt      pgtype.Timestamptz `json:"sentAt"`
+	CreatedAt   time.Time          `json:"createdAt"`
+}
+
+type PasswordReset struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// sha256 of the secret code sent by email
+	SecretCodeHash string    `json:"secretCodeHash"`
+	IsUsed         bool      `json:"isUsed"`
+	CreatedAt      time.Time `json:"createdAt"`
+	ExpiredAt      time.Time `json:"expiredAt"`
+}
+
+type Role struct {
+	Name        string    `json:"name"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
 type Session struct {
 	ID           uuid.UUID `json:"id"`
 	Username     string    `json:"username"`
@@ -36,6 +140,34 @@ type Session struct {
 	IsBloked     bool      `json:"isBloked"`
 	ExpiredAt    time.Time `json:"expiredAt"`
 	CreatedAt    time.Time `json:"createdAt"`
+	// id of the first session in the refresh token rotation chain
+	FamilyID  uuid.UUID          `json:"familyId"`
+	RotatedAt pgtype.Timestamptz `json:"rotatedAt"`
+}
+
+type TotpChallenge struct {
+	// id of the challenge token returned by login
+	ID        uuid.UUID          `json:"id"`
+	Username  string             `json:"username"`
+	UsedAt    pgtype.Timestamptz `json:"usedAt"`
+	CreatedAt time.Time          `json:"createdAt"`
+	ExpiredAt time.Time          `json:"expiredAt"`
+}
+
+type TotpRecoveryCode struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// sha256 of the normalized recovery code
+	CodeHash  string             `json:"codeHash"`
+	UsedAt    pgtype.Timestamptz `json:"usedAt"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+type TotpUsedStep struct {
+	Username string `json:"username"`
+	// last accepted time step, codes of this and earlier steps are rejected
+	Step      int64     `json:"step"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 type Transfer struct {
@@ -45,25 +177,66 @@ type Transfer struct {
 	// should be posetive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"createdAt"`
+	// posted, pending_review or rejected
+	Status      string             `json:"status"`
+	InitiatedBy pgtype.Text        `json:"initiatedBy"`
+	ReviewedBy  pgtype.Text        `json:"reviewedBy"`
+	ReviewedAt  pgtype.Timestamptz `json:"reviewedAt"`
 }
 
 type User struct {
-	Username          string    `json:"username"`
-	HashedPassword    string    `json:"hashedPassword"`
-	FullName          string    `json:"fullName"`
-	Email             string    `json:"email"`
-	PasswordChangedAt time.Time `json:"passwordChangedAt"`
-	CreatedAt         time.Time `json:"createdAt"`
-	IsEmailVerified   bool      `json:"isEmailVerified"`
-	Role              string    `json:"role"`
+	Username          string             `json:"username"`
+	HashedPassword    string             `json:"hashedPassword"`
+	FullName          string             `json:"fullName"`
+	Email             string             `json:"email"`
+	PasswordChangedAt time.Time          `json:"passwordChangedAt"`
+	CreatedAt         time.Time          `json:"createdAt"`
+	IsEmailVerified   bool               `json:"isEmailVerified"`
+	Role              string             `json:"role"`
+	TotpSecret        pgtype.Text        `json:"totpSecret"`
+	TotpEnabled       bool               `json:"totpEnabled"`
+	LockedUntil       pgtype.Timestamptz `json:"lockedUntil"`
+	PendingEmail      pgtype.Text        `json:"pendingEmail"`
 }
 
 type VerifyEmail struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	// sha256 of the secret code sent by email
+	SecretCodeHash string    `json:"secretCodeHash"`
+	IsUsed         bool      `json:"isUsed"`
+	CreatedAt      time.Time `json:"createdAt"`
+	ExpiredAt      time.Time `json:"expiredAt"`
+}
+
+type VerifyEmailResend struct {
+	Username string `json:"username"`
+	// verify emails requested since window_started_at
+	Sent            int64     `json:"sent"`
+	WindowStartedAt time.Time `json:"windowStartedAt"`
+}
+
+type WebhookDelivery struct {
+	ID             int64              `json:"id"`
+	SubscriptionID int64              `json:"subscriptionId"`
+	EventID        int64              `json:"eventId"`
+	EventType      string             `json:"eventType"`
+	Payload        []byte             `json:"payload"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	ResponseCode   pgtype.Int4        `json:"responseCode"`
+	LastError      pgtype.Text        `json:"lastError"`
+	DeliveredAt    pgtype.Timestamptz `json:"deliveredAt"`
+	CreatedAt      time.Time          `json:"createdAt"`
+}
+
+type WebhookSubscription struct {
 	ID         int64     `json:"id"`
-	Username   string    `json:"username"`
-	Email      string    `json:"email"`
-	SecretCode string    `json:"secretCode"`
-	IsUsed     bool      `json:"isUsed"`
+	Owner      string    `json:"owner"`
+	Url        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	Secret     string    `json:"secret"`
+	IsActive   bool      `json:"isActive"`
 	CreatedAt  time.Time `json:"createdAt"`
-	ExpiredAt  time.Time `json:"expiredAt"`
 }
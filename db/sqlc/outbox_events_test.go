@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomOutboxEvent(t *testing.T) OutboxEvent {
+	payload, err := json.Marshal(UserCreatedEvent{
+		Username: util.RandomOwner(),
+		Email:    util.RandomEmail(),
+	})
+	require.NoError(t, err)
+
+	event, err := testStore.CreateOutboxEvent(context.Background(), CreateOutboxEventParams{
+		EventType: EventUserCreated,
+		Payload:   payload,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, event.ID)
+	require.Equal(t, EventUserCreated, event.EventType)
+	require.JSONEq(t, string(payload), string(event.Payload))
+	require.Zero(t, event.Attempts)
+	require.False(t, event.SentAt.Valid)
+	require.False(t, event.LockedUntil.Valid)
+	require.NotZero(t, event.CreatedAt)
+	return event
+}
+
+// claimOutboxEvent claim pending events until the expected one is found
+func claimOutboxEvent(t *testing.T, id int64) (OutboxEvent, bool) {
+	for {
+		events, err := testStore.ClaimOutboxEvents(context.Background(), ClaimOutboxEventsParams{
+			LockedUntil: pgtype.Timestamptz{
+				Time:  time.Now().Add(time.Minute),
+				Valid: true,
+			},
+			BatchSize: 100,
+		})
+		require.NoError(t, err)
+		if len(events) == 0 {
+			return OutboxEvent{}, false
+		}
+		for _, event := range events {
+			if event.ID == id {
+				return event, true
+			}
+		}
+	}
+}
+
+func TestCreateOutboxEvent(t *testing.T) {
+	createRandomOutboxEvent(t)
+}
+
+func TestClaimOutboxEvents(t *testing.T) {
+	event1 := createRandomOutboxEvent(t)
+
+	event2, ok := claimOutboxEvent(t, event1.ID)
+	require.True(t, ok)
+	require.Equal(t, int32(1), event2.Attempts)
+	require.True(t, event2.LockedUntil.Valid)
+	require.False(t, event2.SentAt.Valid)
+
+	// locked event must not be claimed twice
+	_, ok = claimOutboxEvent(t, event1.ID)
+	require.False(t, ok)
+}
+
+func TestMarkOutboxEventSent(t *testing.T) {
+	event1 := createRandomOutboxEvent(t)
+
+	err := testStore.MarkOutboxEventSent(context.Background(), event1.ID)
+	require.NoError(t, err)
+
+	_, ok := claimOutboxEvent(t, event1.ID)
+	require.False(t, ok)
+}
+
+func TestMarkOutboxEventFailed(t *testing.T) {
+	event1 := createRandomOutboxEvent(t)
+
+	_, ok := claimOutboxEvent(t, event1.ID)
+	require.True(t, ok)
+
+	err := testStore.MarkOutboxEventFailed(context.Background(), MarkOutboxEventFailedParams{
+		ID: event1.ID,
+		LastError: pgtype.Text{
+			String: "redis is not available",
+			Valid:  true,
+		},
+		RetryAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(-time.Second),
+			Valid: true,
+		},
+	})
+	require.NoError(t, err)
+
+	event2, ok := claimOutboxEvent(t, event1.ID)
+	require.True(t, ok)
+	require.Equal(t, int32(2), event2.Attempts)
+	require.Equal(t, "redis is not available", event2.LastError.String)
+}
@@ -6,35 +6,109 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddLoginLinkRequest(ctx context.Context, arg AddLoginLinkRequestParams) (int64, error)
 	AddToAccountBalance(ctx context.Context, arg AddToAccountBalanceParams) (Account, error)
+	AddVerifyEmailResend(ctx context.Context, arg AddVerifyEmailResendParams) (int64, error)
+	BlockOtherSessions(ctx context.Context, arg BlockOtherSessionsParams) (int64, error)
+	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	BlockSessionFamily(ctx context.Context, familyID uuid.UUID) (int64, error)
+	BlockUserSessions(ctx context.Context, username string) (int64, error)
+	ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]OutboxEvent, error)
+	ConfirmUserEmail(ctx context.Context, arg ConfirmUserEmailParams) (User, error)
+	CountAccounts(ctx context.Context, arg CountAccountsParams) (int64, error)
+	CountFailedLoginsByClientIp(ctx context.Context, arg CountFailedLoginsByClientIpParams) (int64, error)
+	CountFailedLoginsByUsername(ctx context.Context, arg CountFailedLoginsByUsernameParams) (int64, error)
+	CountRecentVerifyEmails(ctx context.Context, arg CountRecentVerifyEmailsParams) (int64, error)
+	CountTransfersBetweenAccounts(ctx context.Context, arg CountTransfersBetweenAccountsParams) (int64, error)
+	CountTransfersFromAccountSince(ctx context.Context, arg CountTransfersFromAccountSinceParams) (int64, error)
+	CountUsers(ctx context.Context, arg CountUsersParams) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateFailedLogin(ctx context.Context, arg CreateFailedLoginParams) (FailedLogin, error)
+	CreateFraudDecision(ctx context.Context, arg CreateFraudDecisionParams) (FraudDecision, error)
+	CreateImpersonationEvent(ctx context.Context, arg CreateImpersonationEventParams) (ImpersonationEvent, error)
+	CreateLoginEvent(ctx context.Context, arg CreateLoginEventParams) (LoginEvent, error)
+	CreateLoginLink(ctx context.Context, arg CreateLoginLinkParams) (LoginLink, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvent, error)
+	CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateTotpChallenge(ctx context.Context, arg CreateTotpChallengeParams) (TotpChallenge, error)
+	CreateTotpRecoveryCode(ctx context.Context, arg CreateTotpRecoveryCodeParams) (TotpRecoveryCode, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
+	DeactivateWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
 	DeleteAccount(ctx context.Context, id int64) error
 	DeleteEntry(ctx context.Context, id int64) error
+	DeleteFailedLogins(ctx context.Context, username string) error
+	DeleteTotpRecoveryCodes(ctx context.Context, username string) error
 	DeleteTransfer(ctx context.Context, id int64) error
+	EnableUserTotp(ctx context.Context, username string) (User, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountTransferStats(ctx context.Context, arg GetAccountTransferStatsParams) (GetAccountTransferStatsRow, error)
+	GetActivePasswordReset(ctx context.Context, arg GetActivePasswordResetParams) (PasswordReset, error)
+	GetApiKey(ctx context.Context, id int64) (ApiKey, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetFraudDecision(ctx context.Context, id int64) (FraudDecision, error)
+	GetLoginDeviceStats(ctx context.Context, arg GetLoginDeviceStatsParams) (GetLoginDeviceStatsRow, error)
+	GetRole(ctx context.Context, name string) (Role, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetSessionFamilyStartedAt(ctx context.Context, familyID uuid.UUID) (time.Time, error)
+	GetSessionForUpdate(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserPermissions(ctx context.Context, username string) ([]string, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
+	InvalidateVerifyEmails(ctx context.Context, username string) error
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListActiveSessions(ctx context.Context, username string) ([]Session, error)
+	ListApiKeys(ctx context.Context, owner string) ([]ApiKey, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListLoginEvents(ctx context.Context, arg ListLoginEventsParams) ([]LoginEvent, error)
+	ListPendingTransfers(ctx context.Context, arg ListPendingTransfersParams) ([]Transfer, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookSubscriptions(ctx context.Context, owner string) ([]WebhookSubscription, error)
+	ListWebhookSubscriptionsForEvent(ctx context.Context, arg ListWebhookSubscriptionsForEventParams) ([]WebhookSubscription, error)
+	LockUser(ctx context.Context, arg LockUserParams) (User, error)
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error
+	MarkOutboxEventSent(ctx context.Context, id int64) error
+	NotifyAccountEvent(ctx context.Context, payload string) error
+	ResetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	ReviewTransfer(ctx context.Context, arg ReviewTransferParams) (Transfer, error)
+	RevokeApiKey(ctx context.Context, id int64) (ApiKey, error)
+	RotateSession(ctx context.Context, id uuid.UUID) (Session, error)
+	SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error)
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error)
+	SetFraudDecisionTransfer(ctx context.Context, arg SetFraudDecisionTransferParams) error
+	SetUserPendingEmail(ctx context.Context, arg SetUserPendingEmailParams) (User, error)
+	SetUserTotpSecret(ctx context.Context, arg SetUserTotpSecretParams) (User, error)
+	UnlockUser(ctx context.Context, username string) (User, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
 	UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry, error)
 	UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfer, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
 	UpdateVerifyEmail(ctx context.Context, arg UpdateVerifyEmailParams) (VerifyEmail, error)
+	UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) (WebhookDelivery, error)
+	UseLoginLink(ctx context.Context, arg UseLoginLinkParams) (LoginLink, error)
+	UsePasswordReset(ctx context.Context, arg UsePasswordResetParams) (PasswordReset, error)
+	UseTotpChallenge(ctx context.Context, arg UseTotpChallengeParams) (int64, error)
+	UseTotpRecoveryCode(ctx context.Context, arg UseTotpRecoveryCodeParams) (int64, error)
+	UseTotpStep(ctx context.Context, arg UseTotpStepParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
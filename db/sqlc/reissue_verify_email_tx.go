@@ -0,0 +1,22 @@
+package db
+
+import (
+	"context"
+)
+
+// ReissueVerifyEmailTx invalidate all unused verify links of the user
+// and create the new one in one transaction, so only the last link works
+func (store *SQLStore) ReissueVerifyEmailTx(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error) {
+	var verifyEmail VerifyEmail
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := q.InvalidateVerifyEmails(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+		verifyEmail, err = q.CreateVerifyEmail(ctx, arg)
+		return err
+	})
+
+	return verifyEmail, err
+}
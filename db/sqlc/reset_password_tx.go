@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ResetPasswordTxParams struct with arguments for ResetPasswordTx function
+type ResetPasswordTxParams struct {
+	ID             int64
+	SecretCodeHash string
+	HashedPassword string
+}
+
+// ResetPasswordTxResult struct with results from ResetPasswordTx function
+type ResetPasswordTxResult struct {
+	User            User
+	RevokedSessions int64
+}
+
+// ResetPasswordTx consume the single-use reset code, set the new password,
+// lift the login lockout and revoke all sessions of the user in one transaction
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error) {
+	var result ResetPasswordTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		reset, err := q.UsePasswordReset(ctx, UsePasswordResetParams{
+			ID:             arg.ID,
+			SecretCodeHash: arg.SecretCodeHash,
+		})
+		if err != nil {
+			return err
+		}
+
+		// proving access to the email is enough to lift the login lockout
+		_, err = q.UnlockUser(ctx, reset.Username)
+		if err != nil {
+			return err
+		}
+		err = q.DeleteFailedLogins(ctx, reset.Username)
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.UpdateUser(ctx, UpdateUserParams{
+			Username: reset.Username,
+			HashedPassword: pgtype.Text{
+				String: arg.HashedPassword,
+				Valid:  true,
+			},
+			PasswordChangedAt: pgtype.Timestamptz{
+				Time:  time.Now(),
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.RevokedSessions, err = q.BlockUserSessions(ctx, reset.Username)
+		return err
+	})
+
+	return result, err
+}
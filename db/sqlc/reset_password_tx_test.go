@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetPasswordTx(t *testing.T) {
+	user := createRandomUser(t)
+	session := createRandomSession(t, user.Username)
+
+	codeHash := util.HashSecretCode(util.RandomString(32))
+	reset, err := testStore.CreatePasswordReset(context.Background(), CreatePasswordResetParams{
+		Username:       user.Username,
+		SecretCodeHash: codeHash,
+	})
+	require.NoError(t, err)
+	require.False(t, reset.IsUsed)
+	require.True(t, reset.ExpiredAt.After(reset.CreatedAt))
+
+	hashedPassword, err := util.HashPassword(util.RandomString(10))
+	require.NoError(t, err)
+
+	active, err := testStore.GetActivePasswordReset(context.Background(), GetActivePasswordResetParams{
+		ID:             reset.ID,
+		SecretCodeHash: codeHash,
+	})
+	require.NoError(t, err)
+	require.Equal(t, reset, active)
+
+	arg := ResetPasswordTxParams{
+		ID:             reset.ID,
+		SecretCodeHash: codeHash,
+		HashedPassword: hashedPassword,
+	}
+	result, err := testStore.ResetPasswordTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, result.User.HashedPassword)
+	require.True(t, result.User.PasswordChangedAt.After(user.PasswordChangedAt))
+	require.Equal(t, int64(1), result.RevokedSessions)
+
+	blocked, err := testStore.GetSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.True(t, blocked.IsBloked)
+
+	// the code is single-use
+	_, err = testStore.ResetPasswordTx(context.Background(), arg)
+	require.Error(t, err)
+	_, err = testStore.GetActivePasswordReset(context.Background(), GetActivePasswordResetParams{
+		ID:             reset.ID,
+		SecretCodeHash: codeHash,
+	})
+	require.Error(t, err)
+}
+
+func TestResetPasswordTxWrongCode(t *testing.T) {
+	user := createRandomUser(t)
+
+	reset, err := testStore.CreatePasswordReset(context.Background(), CreatePasswordResetParams{
+		Username:       user.Username,
+		SecretCodeHash: util.HashSecretCode(util.RandomString(32)),
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		ID:             reset.ID,
+		SecretCodeHash: util.HashSecretCode(util.RandomString(32)),
+		HashedPassword: user.HashedPassword,
+	})
+	require.Error(t, err)
+}
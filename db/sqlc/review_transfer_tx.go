@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// HoldTransferTx create transfer in the pending_review status without moving money
+func (store *SQLStore) HoldTransferTx(ctx context.Context, arg TransferTxParams) (Transfer, error) {
+	var transfer Transfer
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		transfer, err = insertTransfer(ctx, q, arg, TransferStatusPendingReview)
+		return err
+	})
+
+	return transfer, err
+}
+
+// ReviewTransferTxParams struct with arguments for ReviewTransferTx function
+type ReviewTransferTxParams struct {
+	TransferID int64  `json:"transfer_id"`
+	ReviewedBy string `json:"reviewed_by"`
+	Approved   bool   `json:"approved"`
+}
+
+// ReviewTransferTxResult struct with results from ReviewTransferTx function,
+// accounts and entries are empty when the transfer was rejected
+type ReviewTransferTxResult struct {
+	TransferTxResult
+}
+
+// ReviewTransferTx approve or reject transfer held for review. The transfer
+// can not be reviewed by the same user who initiated it.
+func (store *SQLStore) ReviewTransferTx(ctx context.Context, arg ReviewTransferTxParams) (ReviewTransferTxResult, error) {
+	var result ReviewTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		transfer, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != TransferStatusPendingReview {
+			return ErrTransferNotPending
+		}
+		if transfer.InitiatedBy.String == arg.ReviewedBy {
+			return ErrSelfReview
+		}
+
+		status := TransferStatusRejected
+		if arg.Approved {
+			status = TransferStatusPosted
+		}
+		transfer, err = q.ReviewTransfer(ctx, ReviewTransferParams{
+			ID:     transfer.ID,
+			Status: status,
+			ReviewedBy: pgtype.Text{
+				String: arg.ReviewedBy,
+				Valid:  true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transfer = transfer
+		if arg.Approved {
+			result.TransferTxResult, err = postTransfer(ctx, q, transfer)
+			if err != nil {
+				return err
+			}
+		}
+
+		return writeOutboxEvent(ctx, q, EventTransferReviewed, TransferReviewedEvent{
+			TransferID:    transfer.ID,
+			FromAccountID: transfer.FromAccountID.Int64,
+			ToAccountID:   transfer.ToAccountID.Int64,
+			Amount:        transfer.Amount,
+			Status:        transfer.Status,
+			InitiatedBy:   transfer.InitiatedBy.String,
+			ReviewedBy:    transfer.ReviewedBy.String,
+			ReviewedAt:    transfer.ReviewedAt.Time,
+		})
+	})
+
+	return result, err
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createPendingTransfer(t *testing.T) (Transfer, Account, Account) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	transfer, err := testStore.HoldTransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Ammount:       10,
+		InitiatedBy:   account1.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusPendingReview, transfer.Status)
+	require.Equal(t, account1.Owner, transfer.InitiatedBy.String)
+	require.False(t, transfer.ReviewedBy.Valid)
+
+	// money is not moved until the transfer is approved
+	updatedAccount1, err := testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	return transfer, account1, account2
+}
+
+func TestApproveTransferTx(t *testing.T) {
+	transfer, account1, account2 := createPendingTransfer(t)
+	banker := createRandomUser(t)
+
+	result, err := testStore.ReviewTransferTx(context.Background(), ReviewTransferTxParams{
+		TransferID: transfer.ID,
+		ReviewedBy: banker.Username,
+		Approved:   true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusPosted, result.Transfer.Status)
+	require.Equal(t, banker.Username, result.Transfer.ReviewedBy.String)
+	require.True(t, result.Transfer.ReviewedAt.Valid)
+	require.Equal(t, account1.Balance-transfer.Amount, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+transfer.Amount, result.ToAccount.Balance)
+	require.Equal(t, -transfer.Amount, result.FromEntry.Amount)
+
+	// transfer can be reviewed only once
+	_, err = testStore.ReviewTransferTx(context.Background(), ReviewTransferTxParams{
+		TransferID: transfer.ID,
+		ReviewedBy: banker.Username,
+		Approved:   false,
+	})
+	require.ErrorIs(t, err, ErrTransferNotPending)
+}
+
+func TestRejectTransferTx(t *testing.T) {
+	transfer, account1, _ := createPendingTransfer(t)
+	banker := createRandomUser(t)
+
+	result, err := testStore.ReviewTransferTx(context.Background(), ReviewTransferTxParams{
+		TransferID: transfer.ID,
+		ReviewedBy: banker.Username,
+		Approved:   false,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusRejected, result.Transfer.Status)
+	require.Empty(t, result.FromEntry)
+
+	updatedAccount1, err := testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	// rejected transfer is not the history of payments to the payee
+	count, err := testStore.CountTransfersBetweenAccounts(context.Background(), CountTransfersBetweenAccountsParams{
+		FromAccountID: transfer.FromAccountID,
+		ToAccountID:   transfer.ToAccountID,
+		Status:        TransferStatusPosted,
+	})
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestReviewTransferTxSelfReview(t *testing.T) {
+	transfer, account1, _ := createPendingTransfer(t)
+
+	_, err := testStore.ReviewTransferTx(context.Background(), ReviewTransferTxParams{
+		TransferID: transfer.ID,
+		ReviewedBy: account1.Owner,
+		Approved:   true,
+	})
+	require.ErrorIs(t, err, ErrSelfReview)
+
+	pending, err := testStore.GetTransfer(context.Background(), transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusPendingReview, pending.Status)
+}
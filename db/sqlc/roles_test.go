@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRole(t *testing.T) {
+	banker, err := testStore.GetRole(context.Background(), util.BankerRole)
+	require.NoError(t, err)
+	require.ElementsMatch(t, util.Permissions, banker.Permissions)
+
+	depositor, err := testStore.GetRole(context.Background(), util.DepositorRole)
+	require.NoError(t, err)
+	require.Empty(t, depositor.Permissions)
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	user := createRandomUser(t)
+
+	permissions, err := testStore.GetUserPermissions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, permissions)
+
+	updated, err := testStore.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Role:     util.BankerRole,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.BankerRole, updated.Role)
+
+	permissions, err = testStore.GetUserPermissions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Contains(t, permissions, util.PermissionTransfersApprove)
+
+	_, err = testStore.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Role:     util.RandomString(6),
+		Username: user.Username,
+	})
+	require.Error(t, err)
+	require.Equal(t, ForeignKeyViolation, ErrorCode(err))
+}
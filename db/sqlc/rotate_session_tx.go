@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RotateSessionTxParams struct with arguments for RotateSessionTx function,
+// CreateSessionParams describe the new session issued instead of the old one
+type RotateSessionTxParams struct {
+	SessionID uuid.UUID
+	CreateSessionParams
+}
+
+// RotateSessionTx replace the session by the new one in the same family.
+// The old session is blocked and marked as rotated, presenting its refresh
+// token again returns ErrRefreshTokenReused.
+func (store *SQLStore) RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (Session, error) {
+	var session Session
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		old, err := q.GetSessionForUpdate(ctx, arg.SessionID)
+		if err != nil {
+			return err
+		}
+		if old.RotatedAt.Valid {
+			return ErrRefreshTokenReused
+		}
+
+		_, err = q.RotateSession(ctx, old.ID)
+		if err != nil {
+			return err
+		}
+
+		create := arg.CreateSessionParams
+		create.FamilyID = old.FamilyID
+		session, err = q.CreateSession(ctx, create)
+		return err
+	})
+
+	return session, err
+}
+
+// RevokeSessionFamilyTx block every session of the family the reused
+// session belongs to and record the security event for the user
+func (store *SQLStore) RevokeSessionFamilyTx(ctx context.Context, session Session) (int64, error) {
+	var revoked int64
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		revoked, err = q.BlockSessionFamily(ctx, session.FamilyID)
+		if err != nil {
+			return err
+		}
+
+		return writeOutboxEvent(ctx, q, EventRefreshTokenReused, RefreshTokenReusedEvent{
+			Username:        session.Username,
+			SessionID:       session.ID,
+			FamilyID:        session.FamilyID,
+			UserAgent:       session.UserAgent,
+			ClientIp:        session.ClientIp,
+			RevokedSessions: revoked,
+			DetectedAt:      time.Now(),
+		})
+	})
+
+	return revoked, err
+}
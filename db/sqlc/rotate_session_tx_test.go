@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func rotateRandomSession(t *testing.T, session Session) (Session, error) {
+	return testStore.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		SessionID: session.ID,
+		CreateSessionParams: CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     session.Username,
+			RefreshToken: util.RandomString(32),
+			UserAgent:    session.UserAgent,
+			ClientIp:     session.ClientIp,
+			ExpiredAt:    time.Now().Add(time.Hour),
+		},
+	})
+}
+
+func TestRotateSessionTx(t *testing.T) {
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user.Username)
+
+	session2, err := rotateRandomSession(t, session1)
+	require.NoError(t, err)
+	require.NotEqual(t, session1.ID, session2.ID)
+	require.Equal(t, session1.FamilyID, session2.FamilyID)
+	require.False(t, session2.IsBloked)
+
+	rotated, err := testStore.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.True(t, rotated.IsBloked)
+	require.True(t, rotated.RotatedAt.Valid)
+
+	// the old refresh token can not be exchanged twice
+	_, err = rotateRandomSession(t, session1)
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+}
+
+func TestRevokeSessionFamilyTx(t *testing.T) {
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user.Username)
+	other := createRandomSession(t, user.Username)
+
+	session2, err := rotateRandomSession(t, session1)
+	require.NoError(t, err)
+
+	rotated, err := testStore.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+
+	revoked, err := testStore.RevokeSessionFamilyTx(context.Background(), rotated)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), revoked)
+
+	session2, err = testStore.GetSession(context.Background(), session2.ID)
+	require.NoError(t, err)
+	require.True(t, session2.IsBloked)
+
+	// sessions from another login stay active
+	sessions, err := testStore.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, other.ID, sessions[0].ID)
+}
@@ -12,13 +12,89 @@ import (
 	"github.com/google/uuid"
 )
 
+const blockOtherSessions = `-- name: BlockOtherSessions :execrows
+UPDATE sessions
+SET is_bloked = true
+WHERE username = $1
+AND id <> $2
+AND is_bloked = false
+`
+
+type BlockOtherSessionsParams struct {
+	Username string    `json:"username"`
+	ID       uuid.UUID `json:"id"`
+}
+
+func (q *Queries) BlockOtherSessions(ctx context.Context, arg BlockOtherSessionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, blockOtherSessions, arg.Username, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const blockSession = `-- name: BlockSession :one
+UPDATE sessions
+SET is_bloked = true
+WHERE id = $1
+RETURNING id, username, refresh_token, user_agent, client_ip, is_bloked, expired_at, created_at, family_id, rotated_at
+`
+
+func (q *Queries) BlockSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRow(ctx, blockSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBloked,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const blockSessionFamily = `-- name: BlockSessionFamily :execrows
+UPDATE sessions
+SET is_bloked = true
+WHERE family_id = $1
+AND is_bloked = false
+`
+
+func (q *Queries) BlockSessionFamily(ctx context.Context, familyID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, blockSessionFamily, familyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const blockUserSessions = `-- name: BlockUserSessions :execrows
+UPDATE sessions
+SET is_bloked = true
+WHERE username = $1
+AND is_bloked = false
+`
+
+func (q *Queries) BlockUserSessions(ctx context.Context, username string) (int64, error) {
+	result, err := q.db.Exec(ctx, blockUserSessions, username)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (
-  id,  username, refresh_token, user_agent, client_ip, is_bloked, expired_at
+  id,  username, refresh_token, user_agent, client_ip, is_bloked, expired_at, family_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8
 )
-RETURNING id, username, refresh_token, user_agent, client_ip, is_bloked, expired_at, created_at
+RETURNING id, username, refresh_token, user_agent, client_ip, is_bloked, expired_at, created_at, family_id, rotated_at
 `
 
 type CreateSessionParams struct {
@@ -29,6 +105,7 @@ type CreateSessionParams struct {
 	ClientIp     string    `json:"clientIp"`
 	IsBloked     bool      `json:"isBloked"`
 	ExpiredAt    time.Time `json:"expiredAt"`
+	FamilyID     uuid.UUID `json:"familyId"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -40,6 +117,7 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.ClientIp,
 		arg.IsBloked,
 		arg.ExpiredAt,
+		arg.FamilyID,
 	)
 	var i Session
 	err := row.Scan(
@@ -51,12 +129,14 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IsBloked,
 		&i.ExpiredAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
 const getSession = `-- name: GetSession :one
-SELECT id, username, refresh_token, user_agent, client_ip, is_bloked, expired_at, created_at FROM sessions
+SELECT id, username, refresh_token, user_agent, client_ip, is_bloked, expired_at, created_at, family_id, rotated_at FROM sessions
 WHERE id = $1 LIMIT 1
 `
 
@@ -72,6 +152,110 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 		&i.IsBloked,
 		&i.ExpiredAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const getSessionFamilyStartedAt = `-- name: GetSessionFamilyStartedAt :one
+SELECT min(created_at)::timestamptz AS started_at FROM sessions
+WHERE family_id = $1
+`
+
+func (q *Queries) GetSessionFamilyStartedAt(ctx context.Context, familyID uuid.UUID) (time.Time, error) {
+	row := q.db.QueryRow(ctx, getSessionFamilyStartedAt, familyID)
+	var started_at time.Time
+	err := row.Scan(&started_at)
+	return started_at, err
+}
+
+const getSessionForUpdate = `-- name: GetSessionForUpdate :one
+SELECT id, username, refresh_token, user_agent, client_ip, is_bloked, expired_at, created_at, family_id, rotated_at FROM sessions
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetSessionForUpdate(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRow(ctx, getSessionForUpdate, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBloked,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const listActiveSessions = `-- name: ListActiveSessions :many
+SELECT id, username, refresh_token, user_agent, client_ip, is_bloked, expired_at, created_at, family_id, rotated_at FROM sessions
+WHERE username = $1
+AND is_bloked = false
+AND expired_at > now()
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListActiveSessions(ctx context.Context, username string) ([]Session, error) {
+	rows, err := q.db.Query(ctx, listActiveSessions, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.RefreshToken,
+			&i.UserAgent,
+			&i.ClientIp,
+			&i.IsBloked,
+			&i.ExpiredAt,
+			&i.CreatedAt,
+			&i.FamilyID,
+			&i.RotatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateSession = `-- name: RotateSession :one
+UPDATE sessions
+SET
+  is_bloked = true,
+  rotated_at = now()
+WHERE id = $1
+RETURNING id, username, refresh_token, user_agent, client_ip, is_bloked, expired_at, created_at, family_id, rotated_at
+`
+
+func (q *Queries) RotateSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRow(ctx, rotateSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBloked,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T, username string) Session {
+	id := uuid.New()
+	arg := CreateSessionParams{
+		ID:           id,
+		Username:     username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    "test-agent",
+		ClientIp:     "127.0.0.1",
+		ExpiredAt:    time.Now().Add(time.Hour),
+		FamilyID:     id,
+	}
+	session, err := testStore.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.Username, session.Username)
+	require.Equal(t, arg.FamilyID, session.FamilyID)
+	require.False(t, session.IsBloked)
+	require.False(t, session.RotatedAt.Valid)
+	return session
+}
+
+func TestListActiveSessions(t *testing.T) {
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user.Username)
+	session2 := createRandomSession(t, user.Username)
+
+	_, err := testStore.BlockSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+
+	sessions, err := testStore.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, session2.ID, sessions[0].ID)
+}
+
+func TestBlockOtherSessions(t *testing.T) {
+	user := createRandomUser(t)
+	current := createRandomSession(t, user.Username)
+	for i := 0; i < 3; i++ {
+		createRandomSession(t, user.Username)
+	}
+
+	revoked, err := testStore.BlockOtherSessions(context.Background(), BlockOtherSessionsParams{
+		Username: user.Username,
+		ID:       current.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), revoked)
+
+	sessions, err := testStore.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, current.ID, sessions[0].ID)
+}
+
+func TestBlockUserSessions(t *testing.T) {
+	user := createRandomUser(t)
+	for i := 0; i < 2; i++ {
+		createRandomSession(t, user.Username)
+	}
+
+	revoked, err := testStore.BlockUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), revoked)
+
+	sessions, err := testStore.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
@@ -9,8 +9,21 @@ import (
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	HoldTransferTx(ctx context.Context, arg TransferTxParams) (Transfer, error)
+	ReviewTransferTx(ctx context.Context, arg ReviewTransferTxParams) (ReviewTransferTxResult, error)
 	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (User, error)
 	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	ReissueVerifyEmailTx(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (CreateAccountTxResult, error)
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (Session, error)
+	RevokeSessionFamilyTx(ctx context.Context, session Session) (int64, error)
+	EnableTotpTx(ctx context.Context, arg EnableTotpTxParams) (User, error)
+	VerifyTotpLoginTx(ctx context.Context, arg VerifyTotpLoginTxParams) error
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error)
+	LockUserTx(ctx context.Context, arg LockUserTxParams) (User, error)
+	UnlockUserTx(ctx context.Context, username string) (User, error)
+	ListenAccountEvents(ctx context.Context, handler func(AccountEvent)) error
 }
 
 type SQLStore struct {
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseTotpChallenge(t *testing.T) {
+	user := createRandomUser(t)
+
+	challenge, err := testStore.CreateTotpChallenge(context.Background(), CreateTotpChallengeParams{
+		ID:        uuid.New(),
+		Username:  user.Username,
+		ExpiredAt: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.False(t, challenge.UsedAt.Valid)
+
+	used, err := testStore.UseTotpChallenge(context.Background(), UseTotpChallengeParams{
+		ID:       challenge.ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), used)
+
+	// the challenge is single-use
+	used, err = testStore.UseTotpChallenge(context.Background(), UseTotpChallengeParams{
+		ID:       challenge.ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.Zero(t, used)
+}
+
+func TestUseTotpStep(t *testing.T) {
+	user := createRandomUser(t)
+
+	used, err := testStore.UseTotpStep(context.Background(), UseTotpStepParams{Username: user.Username, Step: 100})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), used)
+
+	// the same and the earlier steps are replays
+	used, err = testStore.UseTotpStep(context.Background(), UseTotpStepParams{Username: user.Username, Step: 100})
+	require.NoError(t, err)
+	require.Zero(t, used)
+
+	used, err = testStore.UseTotpStep(context.Background(), UseTotpStepParams{Username: user.Username, Step: 99})
+	require.NoError(t, err)
+	require.Zero(t, used)
+
+	used, err = testStore.UseTotpStep(context.Background(), UseTotpStepParams{Username: user.Username, Step: 101})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), used)
+}
@@ -6,11 +6,22 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// Statuses of the transfers rows
+const (
+	TransferStatusPosted        = "posted"
+	TransferStatusPendingReview = "pending_review"
+	TransferStatusRejected      = "rejected"
+)
+
 // TransferTxParams struct with arguments for TransferTx function
 type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Ammount       int64 `json:"ammount"`
+	// FraudDecisionID link the fraud_decisions row to the created transfer, zero if there is no decision
+	FraudDecisionID int64 `json:"fraud_decision_id"`
+	// InitiatedBy username of the user who requested the transfer
+	InitiatedBy string `json:"initiated_by"`
 }
 
 // TransferTxResults struct with results from TransferTx function
@@ -30,59 +41,103 @@ func (store *SQLStore) TransferTx(
 	var result TransferTxResult
 
 	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: pgtype.Int8{
-				Int64: arg.FromAccountID,
-				Valid: true,
-			},
-			ToAccountID: pgtype.Int8{
-				Int64: arg.ToAccountID,
-				Valid: true,
-			},
-			Amount: arg.Ammount,
-		})
+		transfer, err := insertTransfer(ctx, q, arg, TransferStatusPosted)
 		if err != nil {
 			return err
 		}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: pgtype.Int8{
-				Int64: arg.FromAccountID,
-				Valid: true,
-			},
-			Amount: -arg.Ammount,
-		})
-		if err != nil {
-			return err
-		}
+		result, err = postTransfer(ctx, q, transfer)
+		return err
+	})
+
+	return result, err
+}
+
+// insertTransfer insert transfer row with the given status and link the fraud decision to it
+func insertTransfer(ctx context.Context, q *Queries, arg TransferTxParams, status string) (Transfer, error) {
+	transfer, err := q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: pgtype.Int8{
+			Int64: arg.FromAccountID,
+			Valid: true,
+		},
+		ToAccountID: pgtype.Int8{
+			Int64: arg.ToAccountID,
+			Valid: true,
+		},
+		Amount: arg.Ammount,
+		Status: status,
+		InitiatedBy: pgtype.Text{
+			String: arg.InitiatedBy,
+			Valid:  arg.InitiatedBy != "",
+		},
+	})
+	if err != nil {
+		return transfer, err
+	}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: pgtype.Int8{
-				Int64: arg.ToAccountID,
+	if arg.FraudDecisionID != 0 {
+		err = q.SetFraudDecisionTransfer(ctx, SetFraudDecisionTransferParams{
+			ID: arg.FraudDecisionID,
+			TransferID: pgtype.Int8{
+				Int64: transfer.ID,
 				Valid: true,
 			},
-			Amount: arg.Ammount,
 		})
-		if err != nil {
-			return err
-		}
+	}
+	return transfer, err
+}
 
-		result.FromAccount, result.ToAccount, err = updateAcoountBalanceInOrder(
-			ctx,
-			q,
-			arg.FromAccountID,
-			arg.ToAccountID,
-			arg.Ammount,
-		)
-		if err != nil {
-			return err
-		}
+// postTransfer move money of the transfer between accounts and publish the events about it
+func postTransfer(ctx context.Context, q *Queries, transfer Transfer) (result TransferTxResult, err error) {
+	result.Transfer = transfer
 
-		return nil
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: transfer.FromAccountID,
+		Amount:    -transfer.Amount,
 	})
+	if err != nil {
+		return
+	}
 
-	return result, err
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: transfer.ToAccountID,
+		Amount:    transfer.Amount,
+	})
+	if err != nil {
+		return
+	}
+
+	result.FromAccount, result.ToAccount, err = updateAcoountBalanceInOrder(
+		ctx,
+		q,
+		transfer.FromAccountID.Int64,
+		transfer.ToAccountID.Int64,
+		transfer.Amount,
+	)
+	if err != nil {
+		return
+	}
+
+	err = publishAccountEvent(ctx, q, result.FromAccount, result.FromEntry)
+	if err != nil {
+		return
+	}
+	err = publishAccountEvent(ctx, q, result.ToAccount, result.ToEntry)
+	if err != nil {
+		return
+	}
+
+	err = writeOutboxEvent(ctx, q, EventTransferCreated, TransferCreatedEvent{
+		TransferID:    result.Transfer.ID,
+		FromAccountID: result.FromAccount.ID,
+		FromOwner:     result.FromAccount.Owner,
+		ToAccountID:   result.ToAccount.ID,
+		ToOwner:       result.ToAccount.Owner,
+		Amount:        result.Transfer.Amount,
+		Currency:      result.FromAccount.Carrency,
+		CreatedAt:     result.Transfer.CreatedAt,
+	})
+	return
 }
 
 func updateAcoountBalanceInOrder(
@@ -7,27 +7,75 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countTransfersBetweenAccounts = `-- name: CountTransfersBetweenAccounts :one
+SELECT COUNT(*) FROM transfers
+WHERE from_account_id = $1
+AND to_account_id = $2
+AND status = $3
+`
+
+type CountTransfersBetweenAccountsParams struct {
+	FromAccountID pgtype.Int8 `json:"fromAccountId"`
+	ToAccountID   pgtype.Int8 `json:"toAccountId"`
+	Status        string      `json:"status"`
+}
+
+func (q *Queries) CountTransfersBetweenAccounts(ctx context.Context, arg CountTransfersBetweenAccountsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countTransfersBetweenAccounts, arg.FromAccountID, arg.ToAccountID, arg.Status)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTransfersFromAccountSince = `-- name: CountTransfersFromAccountSince :one
+SELECT COUNT(*) FROM transfers
+WHERE from_account_id = $1
+AND created_at > $2
+AND status <> 'rejected'
+`
+
+type CountTransfersFromAccountSinceParams struct {
+	FromAccountID pgtype.Int8 `json:"fromAccountId"`
+	Since         time.Time   `json:"since"`
+}
+
+func (q *Queries) CountTransfersFromAccountSince(ctx context.Context, arg CountTransfersFromAccountSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countTransfersFromAccountSince, arg.FromAccountID, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
-  from_account_id, to_account_id, amount
+  from_account_id, to_account_id, amount, status, initiated_by
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 )
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, status, initiated_by, reviewed_by, reviewed_at
 `
 
 type CreateTransferParams struct {
 	FromAccountID pgtype.Int8 `json:"fromAccountId"`
 	ToAccountID   pgtype.Int8 `json:"toAccountId"`
 	Amount        int64       `json:"amount"`
+	Status        string      `json:"status"`
+	InitiatedBy   pgtype.Text `json:"initiatedBy"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRow(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Status,
+		arg.InitiatedBy,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -35,6 +83,10 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Status,
+		&i.InitiatedBy,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
 	)
 	return i, err
 }
@@ -49,8 +101,36 @@ func (q *Queries) DeleteTransfer(ctx context.Context, id int64) error {
 	return err
 }
 
+const getAccountTransferStats = `-- name: GetAccountTransferStats :one
+SELECT
+  COUNT(*) AS transfer_count,
+  COALESCE(AVG(amount), 0)::bigint AS average_amount
+FROM transfers
+WHERE from_account_id = $1
+AND created_at > $2
+AND status = $3
+`
+
+type GetAccountTransferStatsParams struct {
+	FromAccountID pgtype.Int8 `json:"fromAccountId"`
+	Since         time.Time   `json:"since"`
+	Status        string      `json:"status"`
+}
+
+type GetAccountTransferStatsRow struct {
+	TransferCount int64 `json:"transferCount"`
+	AverageAmount int64 `json:"averageAmount"`
+}
+
+func (q *Queries) GetAccountTransferStats(ctx context.Context, arg GetAccountTransferStatsParams) (GetAccountTransferStatsRow, error) {
+	row := q.db.QueryRow(ctx, getAccountTransferStats, arg.FromAccountID, arg.Since, arg.Status)
+	var i GetAccountTransferStatsRow
+	err := row.Scan(&i.TransferCount, &i.AverageAmount)
+	return i, err
+}
+
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, status, initiated_by, reviewed_by, reviewed_at FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -63,12 +143,82 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Status,
+		&i.InitiatedBy,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, status, initiated_by, reviewed_by, reviewed_at FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRow(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Status,
+		&i.InitiatedBy,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
 	)
 	return i, err
 }
 
+const listPendingTransfers = `-- name: ListPendingTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, status, initiated_by, reviewed_by, reviewed_at FROM transfers
+WHERE status = 'pending_review'
+ORDER BY id
+LIMIT $1
+OFFSET $2
+`
+
+type ListPendingTransfersParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListPendingTransfers(ctx context.Context, arg ListPendingTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.Query(ctx, listPendingTransfers, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Status,
+			&i.InitiatedBy,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, status, initiated_by, reviewed_by, reviewed_at FROM transfers
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -94,6 +244,10 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Status,
+			&i.InitiatedBy,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -105,11 +259,44 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	return items, nil
 }
 
+const reviewTransfer = `-- name: ReviewTransfer :one
+UPDATE transfers
+SET
+  status = $1,
+  reviewed_by = $2,
+  reviewed_at = now()
+WHERE id = $3
+RETURNING id, from_account_id, to_account_id, amount, created_at, status, initiated_by, reviewed_by, reviewed_at
+`
+
+type ReviewTransferParams struct {
+	Status     string      `json:"status"`
+	ReviewedBy pgtype.Text `json:"reviewedBy"`
+	ID         int64       `json:"id"`
+}
+
+func (q *Queries) ReviewTransfer(ctx context.Context, arg ReviewTransferParams) (Transfer, error) {
+	row := q.db.QueryRow(ctx, reviewTransfer, arg.Status, arg.ReviewedBy, arg.ID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Status,
+		&i.InitiatedBy,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
 const updateTransfer = `-- name: UpdateTransfer :one
 UPDATE transfers
 SET amount = $2
 WHERE id = $1
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, status, initiated_by, reviewed_by, reviewed_at
 `
 
 type UpdateTransferParams struct {
@@ -126,6 +313,10 @@ func (q *Queries) UpdateTransfer(ctx context.Context, arg UpdateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Status,
+		&i.InitiatedBy,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
 	)
 	return i, err
 }
@@ -23,6 +23,11 @@ func createRandomTransfer(t *testing.T) Transfer {
 			Valid: true,
 		},
 		Amount: util.RandomMoney(),
+		Status: TransferStatusPosted,
+		InitiatedBy: pgtype.Text{
+			String: account1.Owner,
+			Valid:  true,
+		},
 	}
 
 	transfer, err := testStore.CreateTransfer(context.Background(), arg)
@@ -32,6 +37,8 @@ func createRandomTransfer(t *testing.T) Transfer {
 	require.Equal(t, account1.ID, transfer.FromAccountID.Int64)
 	require.Equal(t, account2.ID, transfer.ToAccountID.Int64)
 	require.Equal(t, arg.Amount, transfer.Amount)
+	require.Equal(t, arg.Status, transfer.Status)
+	require.Equal(t, arg.InitiatedBy, transfer.InitiatedBy)
 
 	return transfer
 }
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// UpdateUserTxParams struct with arguments for UpdateUserTx function,
+// NewEmail is not written to the email column but kept as pending
+// until the user verify it
+type UpdateUserTxParams struct {
+	UpdateUserParams
+	NewEmail pgtype.Text
+}
+
+// UpdateUserTx update the user and request the email change in one transaction
+// together with user.email_change_requested event in the outbox
+func (store *SQLStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.UpdateUser(ctx, arg.UpdateUserParams)
+		if err != nil {
+			return err
+		}
+		if !arg.NewEmail.Valid {
+			return nil
+		}
+
+		// going back to the active email cancel the pending change
+		if arg.NewEmail.String == user.Email {
+			if user.PendingEmail.Valid {
+				user, err = q.SetUserPendingEmail(ctx, SetUserPendingEmailParams{
+					Username: user.Username,
+				})
+			}
+			return err
+		}
+
+		user, err = q.SetUserPendingEmail(ctx, SetUserPendingEmailParams{
+			PendingEmail: arg.NewEmail,
+			Username:     user.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		return writeOutboxEvent(ctx, q, EventUserEmailChanged, UserEmailChangedEvent{
+			Username: user.Username,
+			OldEmail: user.Email,
+			NewEmail: arg.NewEmail.String,
+		})
+	})
+
+	return user, err
+}
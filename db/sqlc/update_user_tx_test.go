@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomVerifyEmail(t *testing.T, username, email string) VerifyEmail {
+	verifyEmail, err := testStore.CreateVerifyEmail(context.Background(), CreateVerifyEmailParams{
+		Username:       username,
+		Email:          email,
+		SecretCodeHash: util.HashSecretCode(util.RandomString(32)),
+	})
+	require.NoError(t, err)
+	return verifyEmail
+}
+
+func TestUpdateUserTxEmailChange(t *testing.T) {
+	user := createRandomUser(t)
+	newEmail := util.RandomEmail()
+
+	updated, err := testStore.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{Username: user.Username},
+		NewEmail:         pgtype.Text{String: newEmail, Valid: true},
+	})
+	require.NoError(t, err)
+	// the old address stays active until the new one is verified
+	require.Equal(t, user.Email, updated.Email)
+	require.Equal(t, newEmail, updated.PendingEmail.String)
+
+	verifyEmail := createRandomVerifyEmail(t, user.Username, newEmail)
+	result, err := testStore.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		ID:             verifyEmail.ID,
+		SecretCodeHash: verifyEmail.SecretCodeHash,
+	})
+	require.NoError(t, err)
+	require.Equal(t, newEmail, result.User.Email)
+	require.False(t, result.User.PendingEmail.Valid)
+	require.True(t, result.User.IsEmailVerified)
+}
+
+func TestUpdateUserTxCancelEmailChange(t *testing.T) {
+	user := createRandomUser(t)
+	newEmail := util.RandomEmail()
+
+	_, err := testStore.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{Username: user.Username},
+		NewEmail:         pgtype.Text{String: newEmail, Valid: true},
+	})
+	require.NoError(t, err)
+	verifyEmail := createRandomVerifyEmail(t, user.Username, newEmail)
+
+	updated, err := testStore.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{Username: user.Username},
+		NewEmail:         pgtype.Text{String: user.Email, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.Email, updated.Email)
+	require.False(t, updated.PendingEmail.Valid)
+
+	// the link sent to the canceled address can not be used anymore
+	_, err = testStore.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		ID:             verifyEmail.ID,
+		SecretCodeHash: verifyEmail.SecretCodeHash,
+	})
+	require.ErrorIs(t, err, ErrVerifyEmailOutdated)
+}
+
+func TestReissueVerifyEmailTx(t *testing.T) {
+	user := createRandomUser(t)
+	first := createRandomVerifyEmail(t, user.Username, user.Email)
+
+	second, err := testStore.ReissueVerifyEmailTx(context.Background(), CreateVerifyEmailParams{
+		Username:       user.Username,
+		Email:          user.Email,
+		SecretCodeHash: util.HashSecretCode(util.RandomString(32)),
+	})
+	require.NoError(t, err)
+	require.False(t, second.IsUsed)
+
+	count, err := testStore.CountRecentVerifyEmails(context.Background(), CountRecentVerifyEmailsParams{
+		Username: user.Username,
+		Since:    first.CreatedAt.Add(-time.Second),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	// only the last link works
+	_, err = testStore.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		ID:             first.ID,
+		SecretCodeHash: first.SecretCodeHash,
+	})
+	require.Error(t, err)
+	result, err := testStore.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		ID:             second.ID,
+		SecretCodeHash: second.SecretCodeHash,
+	})
+	require.NoError(t, err)
+	require.True(t, result.User.IsEmailVerified)
+}
@@ -11,13 +11,84 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const confirmUserEmail = `-- name: ConfirmUserEmail :one
+UPDATE users
+SET
+  email = pending_email,
+  pending_email = NULL,
+  is_email_verified = true
+WHERE
+  username = $1
+  AND pending_email = $2
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email
+`
+
+type ConfirmUserEmailParams struct {
+	Username string      `json:"username"`
+	Email    pgtype.Text `json:"email"`
+}
+
+func (q *Queries) ConfirmUserEmail(ctx context.Context, arg ConfirmUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, confirmUserEmail, arg.Username, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.LockedUntil,
+		&i.PendingEmail,
+	)
+	return i, err
+}
+
+const countUsers = `-- name: CountUsers :one
+SELECT count(*) FROM users
+WHERE
+  ($1::varchar IS NULL OR username LIKE $1 || '%')
+  AND ($2::varchar IS NULL OR email = $2)
+  AND ($3::varchar IS NULL OR lower(full_name) LIKE lower($3) || '%')
+  AND ($4::bool IS NULL OR is_email_verified = $4)
+  AND ($5::timestamptz IS NULL OR created_at >= $5)
+  AND ($6::timestamptz IS NULL OR created_at < $6)
+`
+
+type CountUsersParams struct {
+	UsernamePrefix  pgtype.Text        `json:"usernamePrefix"`
+	Email           pgtype.Text        `json:"email"`
+	FullName        pgtype.Text        `json:"fullName"`
+	IsEmailVerified pgtype.Bool        `json:"isEmailVerified"`
+	CreatedFrom     pgtype.Timestamptz `json:"createdFrom"`
+	CreatedTo       pgtype.Timestamptz `json:"createdTo"`
+}
+
+func (q *Queries) CountUsers(ctx context.Context, arg CountUsersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsers,
+		arg.UsernamePrefix,
+		arg.Email,
+		arg.FullName,
+		arg.IsEmailVerified,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   username, hashed_password, full_name, email
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email
 `
 
 type CreateUserParams struct {
@@ -44,12 +115,43 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.IsEmailVerified,
 		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.LockedUntil,
+		&i.PendingEmail,
+	)
+	return i, err
+}
+
+const enableUserTotp = `-- name: EnableUserTotp :one
+UPDATE users
+SET totp_enabled = true
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email
+`
+
+func (q *Queries) EnableUserTotp(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRow(ctx, enableUserTotp, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.LockedUntil,
+		&i.PendingEmail,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role FROM users
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -65,6 +167,228 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.CreatedAt,
 		&i.IsEmailVerified,
 		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.LockedUntil,
+		&i.PendingEmail,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email FROM users
+WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.LockedUntil,
+		&i.PendingEmail,
+	)
+	return i, err
+}
+
+const lockUser = `-- name: LockUser :one
+UPDATE users
+SET locked_until = $1
+WHERE username = $2
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email
+`
+
+type LockUserParams struct {
+	LockedUntil pgtype.Timestamptz `json:"lockedUntil"`
+	Username    string             `json:"username"`
+}
+
+func (q *Queries) LockUser(ctx context.Context, arg LockUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, lockUser, arg.LockedUntil, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.LockedUntil,
+		&i.PendingEmail,
+	)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email FROM users
+WHERE
+  ($1::varchar IS NULL OR username LIKE $1 || '%')
+  AND ($2::varchar IS NULL OR email = $2)
+  AND ($3::varchar IS NULL OR lower(full_name) LIKE lower($3) || '%')
+  AND ($4::bool IS NULL OR is_email_verified = $4)
+  AND ($5::timestamptz IS NULL OR created_at >= $5)
+  AND ($6::timestamptz IS NULL OR created_at < $6)
+  AND username > $7
+ORDER BY username
+LIMIT $8
+`
+
+type SearchUsersParams struct {
+	UsernamePrefix  pgtype.Text        `json:"usernamePrefix"`
+	Email           pgtype.Text        `json:"email"`
+	FullName        pgtype.Text        `json:"fullName"`
+	IsEmailVerified pgtype.Bool        `json:"isEmailVerified"`
+	CreatedFrom     pgtype.Timestamptz `json:"createdFrom"`
+	CreatedTo       pgtype.Timestamptz `json:"createdTo"`
+	AfterUsername   string             `json:"afterUsername"`
+	Limit           int32              `json:"limit"`
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, searchUsers,
+		arg.UsernamePrefix,
+		arg.Email,
+		arg.FullName,
+		arg.IsEmailVerified,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.AfterUsername,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.Username,
+			&i.HashedPassword,
+			&i.FullName,
+			&i.Email,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+			&i.IsEmailVerified,
+			&i.Role,
+			&i.TotpSecret,
+			&i.TotpEnabled,
+			&i.LockedUntil,
+			&i.PendingEmail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setUserPendingEmail = `-- name: SetUserPendingEmail :one
+UPDATE users
+SET pending_email = $1
+WHERE username = $2
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email
+`
+
+type SetUserPendingEmailParams struct {
+	PendingEmail pgtype.Text `json:"pendingEmail"`
+	Username     string      `json:"username"`
+}
+
+func (q *Queries) SetUserPendingEmail(ctx context.Context, arg SetUserPendingEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserPendingEmail, arg.PendingEmail, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.LockedUntil,
+		&i.PendingEmail,
+	)
+	return i, err
+}
+
+const setUserTotpSecret = `-- name: SetUserTotpSecret :one
+UPDATE users
+SET
+  totp_secret = $1,
+  totp_enabled = false
+WHERE
+  username = $2
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email
+`
+
+type SetUserTotpSecretParams struct {
+	TotpSecret pgtype.Text `json:"totpSecret"`
+	Username   string      `json:"username"`
+}
+
+func (q *Queries) SetUserTotpSecret(ctx context.Context, arg SetUserTotpSecretParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserTotpSecret, arg.TotpSecret, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.LockedUntil,
+		&i.PendingEmail,
+	)
+	return i, err
+}
+
+const unlockUser = `-- name: UnlockUser :one
+UPDATE users
+SET locked_until = NULL
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email
+`
+
+func (q *Queries) UnlockUser(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRow(ctx, unlockUser, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.LockedUntil,
+		&i.PendingEmail,
 	)
 	return i, err
 }
@@ -79,7 +403,7 @@ SET
   password_changed_at = COALESCE($5, password_changed_at)
 WHERE 
   username = $6
-RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email
 `
 
 type UpdateUserParams struct {
@@ -110,6 +434,42 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.CreatedAt,
 		&i.IsEmailVerified,
 		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.LockedUntil,
+		&i.PendingEmail,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET role = $1
+WHERE username = $2
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, totp_secret, totp_enabled, locked_until, pending_email
+`
+
+type UpdateUserRoleParams struct {
+	Role     string `json:"role"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.Role, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.LockedUntil,
+		&i.PendingEmail,
 	)
 	return i, err
 }
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -136,3 +137,32 @@ func TestUpdateUserHashedAll(t *testing.T) {
 	require.Equal(t, newFullName, userUpdated.FullName)
 	require.Equal(t, hp, userUpdated.HashedPassword)
 }
+
+func TestSearchUsers(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := SearchUsersParams{
+		UsernamePrefix: pgtype.Text{String: user.Username[:4], Valid: true},
+		FullName:       pgtype.Text{String: strings.ToUpper(user.FullName), Valid: true},
+		CreatedFrom:    pgtype.Timestamptz{Time: user.CreatedAt.Add(-time.Second), Valid: true},
+		Limit:          5,
+	}
+	users, err := testStore.SearchUsers(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, users)
+	require.Contains(t, users, user)
+
+	count, err := testStore.CountUsers(context.Background(), CountUsersParams{
+		Email: pgtype.Text{String: user.Email, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// the cursor skips users up to and including the last one seen
+	arg.AfterUsername = user.Username
+	users, err = testStore.SearchUsers(context.Background(), arg)
+	require.NoError(t, err)
+	for _, u := range users {
+		require.Greater(t, u.Username, user.Username)
+	}
+}
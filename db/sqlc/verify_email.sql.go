@@ -7,31 +7,50 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
+const countRecentVerifyEmails = `-- name: CountRecentVerifyEmails :one
+SELECT count(*) FROM verify_emails
+WHERE username = $1
+AND created_at > $2
+`
+
+type CountRecentVerifyEmailsParams struct {
+	Username string    `json:"username"`
+	Since    time.Time `json:"since"`
+}
+
+func (q *Queries) CountRecentVerifyEmails(ctx context.Context, arg CountRecentVerifyEmailsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countRecentVerifyEmails, arg.Username, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createVerifyEmail = `-- name: CreateVerifyEmail :one
 INSERT INTO verify_emails (
-  username, email, secret_code
+  username, email, secret_code_hash
 ) VALUES (
   $1, $2, $3
 )
-RETURNING id, username, email, secret_code, is_used, created_at, expired_at
+RETURNING id, username, email, secret_code_hash, is_used, created_at, expired_at
 `
 
 type CreateVerifyEmailParams struct {
-	Username   string `json:"username"`
-	Email      string `json:"email"`
-	SecretCode string `json:"secretCode"`
+	Username       string `json:"username"`
+	Email          string `json:"email"`
+	SecretCodeHash string `json:"secretCodeHash"`
 }
 
 func (q *Queries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error) {
-	row := q.db.QueryRow(ctx, createVerifyEmail, arg.Username, arg.Email, arg.SecretCode)
+	row := q.db.QueryRow(ctx, createVerifyEmail, arg.Username, arg.Email, arg.SecretCodeHash)
 	var i VerifyEmail
 	err := row.Scan(
 		&i.ID,
 		&i.Username,
 		&i.Email,
-		&i.SecretCode,
+		&i.SecretCodeHash,
 		&i.IsUsed,
 		&i.CreatedAt,
 		&i.ExpiredAt,
@@ -39,29 +58,41 @@ func (q *Queries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailPa
 	return i, err
 }
 
+const invalidateVerifyEmails = `-- name: InvalidateVerifyEmails :exec
+UPDATE verify_emails
+SET is_used = true
+WHERE username = $1
+AND is_used = false
+`
+
+func (q *Queries) InvalidateVerifyEmails(ctx context.Context, username string) error {
+	_, err := q.db.Exec(ctx, invalidateVerifyEmails, username)
+	return err
+}
+
 const updateVerifyEmail = `-- name: UpdateVerifyEmail :one
 UPDATE verify_emails
 SET is_used = true
 WHERE id = $1
-AND secret_code = $2
+AND secret_code_hash = $2
 AND is_used = false
 AND expired_at > now() 
-RETURNING id, username, email, secret_code, is_used, created_at, expired_at
+RETURNING id, username, email, secret_code_hash, is_used, created_at, expired_at
 `
 
 type UpdateVerifyEmailParams struct {
-	ID         int64  `json:"id"`
-	SecretCode string `json:"secretCode"`
+	ID             int64  `json:"id"`
+	SecretCodeHash string `json:"secretCodeHash"`
 }
 
 func (q *Queries) UpdateVerifyEmail(ctx context.Context, arg UpdateVerifyEmailParams) (VerifyEmail, error) {
-	row := q.db.QueryRow(ctx, updateVerifyEmail, arg.ID, arg.SecretCode)
+	row := q.db.QueryRow(ctx, updateVerifyEmail, arg.ID, arg.SecretCodeHash)
 	var i VerifyEmail
 	err := row.Scan(
 		&i.ID,
 		&i.Username,
 		&i.Email,
-		&i.SecretCode,
+		&i.SecretCodeHash,
 		&i.IsUsed,
 		&i.CreatedAt,
 		&i.ExpiredAt,
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddVerifyEmailResend(t *testing.T) {
+	user := createRandomUser(t)
+	arg := AddVerifyEmailResendParams{
+		Username:    user.Username,
+		WindowStart: time.Now().Add(-time.Hour),
+	}
+
+	for i := int64(1); i <= 3; i++ {
+		sent, err := testStore.AddVerifyEmailResend(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, i, sent)
+	}
+
+	// the window started after the first request, the counter starts again
+	arg.WindowStart = time.Now().Add(time.Minute)
+	sent, err := testStore.AddVerifyEmailResend(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), sent)
+}
@@ -4,13 +4,12 @@ import (
 	"context"
 
 	"github.com/jackc/pgx/v5/pgtype"
-	// "github.com/dubass83/simplebank/worker"
 )
 
 // VerifyEmailTxParams struct with arguments for VerifyEmailTx function
 type VerifyEmailTxParams struct {
-	ID         int64
-	SecretCode string
+	ID             int64
+	SecretCodeHash string
 }
 
 // VerifyEmailTxResults struct with results from VerifyEmailTx function
@@ -29,21 +28,44 @@ func (store *SQLStore) VerifyEmailTx(
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
 		result.VerifyEmail, err = q.UpdateVerifyEmail(ctx, UpdateVerifyEmailParams{
-			ID:         arg.ID,
-			SecretCode: arg.SecretCode,
+			ID:             arg.ID,
+			SecretCodeHash: arg.SecretCodeHash,
 		})
 		if err != nil {
 			return err
 		}
-		result.User, err = q.UpdateUser(ctx, UpdateUserParams{
-			Username: result.VerifyEmail.Username,
-			IsEmailVerified: pgtype.Bool{
-				Bool:  true,
-				Valid: true,
-			},
-		})
+		user, err := q.GetUser(ctx, result.VerifyEmail.Username)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case user.PendingEmail.Valid && user.PendingEmail.String == result.VerifyEmail.Email:
+			// the new address is proven, swap it with the old one
+			result.User, err = q.ConfirmUserEmail(ctx, ConfirmUserEmailParams{
+				Username: user.Username,
+				Email:    user.PendingEmail,
+			})
+		case user.Email == result.VerifyEmail.Email:
+			result.User, err = q.UpdateUser(ctx, UpdateUserParams{
+				Username: user.Username,
+				IsEmailVerified: pgtype.Bool{
+					Bool:  true,
+					Valid: true,
+				},
+			})
+		default:
+			// the email was changed again after the link was sent
+			return ErrVerifyEmailOutdated
+		}
+		if err != nil {
+			return err
+		}
 
-		return err
+		return writeOutboxEvent(ctx, q, EventUserEmailVerified, UserEmailVerifiedEvent{
+			Username: result.User.Username,
+			Email:    result.User.Email,
+		})
 	})
 
 	return result, err
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// VerifyTotpLoginTxParams struct with arguments for VerifyTotpLoginTx function,
+// the recovery code is used when RecoveryCodeHash is set, otherwise the totp Step
+type VerifyTotpLoginTxParams struct {
+	ChallengeID      uuid.UUID
+	Username         string
+	Step             int64
+	RecoveryCodeHash string
+}
+
+// VerifyTotpLoginTx claim the challenge and then consume the second factor in one
+// transaction, so a replayed challenge does not burn the code and a rejected code
+// does not burn the challenge
+func (store *SQLStore) VerifyTotpLoginTx(ctx context.Context, arg VerifyTotpLoginTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		used, err := q.UseTotpChallenge(ctx, UseTotpChallengeParams{
+			ID:       arg.ChallengeID,
+			Username: arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+		if used == 0 {
+			return ErrTotpChallengeUsed
+		}
+
+		if arg.RecoveryCodeHash != "" {
+			used, err = q.UseTotpRecoveryCode(ctx, UseTotpRecoveryCodeParams{
+				Username: arg.Username,
+				CodeHash: arg.RecoveryCodeHash,
+			})
+		} else {
+			used, err = q.UseTotpStep(ctx, UseTotpStepParams{
+				Username: arg.Username,
+				Step:     arg.Step,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		if used == 0 {
+			return ErrTotpCodeUsed
+		}
+		return nil
+	})
+}
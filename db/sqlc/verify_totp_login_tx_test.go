@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyTotpLoginTx(t *testing.T) {
+	user := createRandomUser(t)
+	codeHash := util.RandomString(64)
+	_, err := testStore.CreateTotpRecoveryCode(context.Background(), CreateTotpRecoveryCodeParams{
+		Username: user.Username,
+		CodeHash: codeHash,
+	})
+	require.NoError(t, err)
+
+	challenge, err := testStore.CreateTotpChallenge(context.Background(), CreateTotpChallengeParams{
+		ID:        uuid.New(),
+		Username:  user.Username,
+		ExpiredAt: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	// the rejected code does not burn the challenge
+	err = testStore.VerifyTotpLoginTx(context.Background(), VerifyTotpLoginTxParams{
+		ChallengeID:      challenge.ID,
+		Username:         user.Username,
+		RecoveryCodeHash: util.RandomString(64),
+	})
+	require.ErrorIs(t, err, ErrTotpCodeUsed)
+
+	arg := VerifyTotpLoginTxParams{
+		ChallengeID:      challenge.ID,
+		Username:         user.Username,
+		RecoveryCodeHash: codeHash,
+	}
+	err = testStore.VerifyTotpLoginTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// the replayed challenge does not burn the totp step
+	err = testStore.VerifyTotpLoginTx(context.Background(), VerifyTotpLoginTxParams{
+		ChallengeID: challenge.ID,
+		Username:    user.Username,
+		Step:        100,
+	})
+	require.ErrorIs(t, err, ErrTotpChallengeUsed)
+	used, err := testStore.UseTotpStep(context.Background(), UseTotpStepParams{Username: user.Username, Step: 100})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), used)
+}
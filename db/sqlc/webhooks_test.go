@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhookSubscription(t *testing.T, owner string) WebhookSubscription {
+	arg := CreateWebhookSubscriptionParams{
+		Owner:      owner,
+		Url:        "https://example.com/hooks/" + util.RandomString(6),
+		EventTypes: []string{EventTransferCreated, EventAccountCreated},
+		Secret:     util.RandomString(32),
+	}
+
+	subscription, err := testStore.CreateWebhookSubscription(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, subscription.ID)
+	require.Equal(t, arg.Owner, subscription.Owner)
+	require.Equal(t, arg.Url, subscription.Url)
+	require.Equal(t, arg.EventTypes, subscription.EventTypes)
+	require.Equal(t, arg.Secret, subscription.Secret)
+	require.True(t, subscription.IsActive)
+	require.NotZero(t, subscription.CreatedAt)
+	return subscription
+}
+
+func createRandomWebhookDelivery(t *testing.T, subscription WebhookSubscription) WebhookDelivery {
+	event := createRandomOutboxEvent(t)
+	arg := CreateWebhookDeliveryParams{
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      EventTransferCreated,
+		Payload:        event.Payload,
+	}
+
+	delivery, err := testStore.CreateWebhookDelivery(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, delivery.ID)
+	require.Equal(t, arg.SubscriptionID, delivery.SubscriptionID)
+	require.Equal(t, arg.EventID, delivery.EventID)
+	require.Equal(t, WebhookDeliveryPending, delivery.Status)
+	require.Zero(t, delivery.Attempts)
+	return delivery
+}
+
+func TestCreateWebhookSubscription(t *testing.T) {
+	createRandomWebhookSubscription(t, createRandomUser(t).Username)
+}
+
+func TestListWebhookSubscriptionsForEvent(t *testing.T) {
+	user := createRandomUser(t)
+	subscription := createRandomWebhookSubscription(t, user.Username)
+	inactive := createRandomWebhookSubscription(t, user.Username)
+	_, err := testStore.DeactivateWebhookSubscription(context.Background(), inactive.ID)
+	require.NoError(t, err)
+
+	subscriptions, err := testStore.ListWebhookSubscriptionsForEvent(context.Background(), ListWebhookSubscriptionsForEventParams{
+		EventType: EventTransferCreated,
+		Owners:    []string{user.Username},
+	})
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	require.Equal(t, subscription.ID, subscriptions[0].ID)
+
+	subscriptions, err = testStore.ListWebhookSubscriptionsForEvent(context.Background(), ListWebhookSubscriptionsForEventParams{
+		EventType: EventUserEmailVerified,
+		Owners:    []string{user.Username},
+	})
+	require.NoError(t, err)
+	require.Empty(t, subscriptions)
+}
+
+func TestCreateWebhookDeliveryIdempotent(t *testing.T) {
+	subscription := createRandomWebhookSubscription(t, createRandomUser(t).Username)
+	delivery := createRandomWebhookDelivery(t, subscription)
+
+	again, err := testStore.CreateWebhookDelivery(context.Background(), CreateWebhookDeliveryParams{
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+	})
+	require.NoError(t, err)
+	require.Equal(t, delivery.ID, again.ID)
+}
+
+func TestUpdateAndResetWebhookDelivery(t *testing.T) {
+	subscription := createRandomWebhookSubscription(t, createRandomUser(t).Username)
+	delivery := createRandomWebhookDelivery(t, subscription)
+
+	updated, err := testStore.UpdateWebhookDelivery(context.Background(), UpdateWebhookDeliveryParams{
+		ID:           delivery.ID,
+		Status:       WebhookDeliverySucceeded,
+		ResponseCode: pgtype.Int4{Int32: 200, Valid: true},
+		DeliveredAt:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, WebhookDeliverySucceeded, updated.Status)
+	require.Equal(t, int32(1), updated.Attempts)
+	require.Equal(t, int32(200), updated.ResponseCode.Int32)
+	require.True(t, updated.DeliveredAt.Valid)
+
+	reset, err := testStore.ResetWebhookDelivery(context.Background(), delivery.ID)
+	require.NoError(t, err)
+	require.Equal(t, WebhookDeliveryPending, reset.Status)
+}
+
+func TestCreateAccountTx(t *testing.T) {
+	user := createRandomUser(t)
+
+	result, err := testStore.CreateAccountTx(context.Background(), CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			Owner:    user.Username,
+			Balance:  0,
+			Carrency: util.USD,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.Username, result.Account.Owner)
+	require.Equal(t, util.USD, result.Account.Carrency)
+	require.Zero(t, result.Account.Balance)
+}
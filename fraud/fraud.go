@@ -0,0 +1,140 @@
+package fraud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/util"
+	"github.com/google/uuid"
+)
+
+// Action what should happen with the evaluated transfer
+type Action string
+
+const (
+	ActionAllow  Action = "allow"
+	ActionReview Action = "review"
+	ActionBlock  Action = "block"
+)
+
+// Transfer facts about the transfer which is going to be executed
+type Transfer struct {
+	Username string
+	// SessionID of the login session the transfer is made from, nil when the token has no session
+	SessionID   uuid.UUID
+	FromAccount db.Account
+	ToAccount   db.Account
+	Amount      int64
+}
+
+// Signal contribution of one rule to the transfer score
+type Signal struct {
+	Rule   string `json:"rule"`
+	Score  int32  `json:"score"`
+	Reason string `json:"reason"`
+}
+
+// Decision result of the transfer evaluation stored in the fraud_decisions table
+type Decision struct {
+	ID      int64
+	Action  Action
+	Score   int32
+	Signals []Signal
+}
+
+// Rule evaluate one aspect of the transfer and return its score, zero score means nothing suspicious
+type Rule interface {
+	Name() string
+	Evaluate(ctx context.Context, transfer Transfer) (score int32, reason string, err error)
+}
+
+// Evaluator decide if the transfer can be executed
+type Evaluator interface {
+	Evaluate(ctx context.Context, transfer Transfer) (Decision, error)
+}
+
+// Engine sum scores of all rules and compare the total with review and block thresholds
+type Engine struct {
+	store       db.Store
+	rules       []Rule
+	reviewScore int32
+	blockScore  int32
+}
+
+// NewEngine create engine with the default rules configured from the application config
+func NewEngine(store db.Store, config util.Config) *Engine {
+	return NewEngineWithRules(store, config.FraudReviewScore, config.FraudBlockScore, DefaultRules(store, config)...)
+}
+
+// NewEngineWithRules create engine with custom rules, zero threshold disables the action
+func NewEngineWithRules(store db.Store, reviewScore, blockScore int32, rules ...Rule) *Engine {
+	return &Engine{
+		store:       store,
+		rules:       rules,
+		reviewScore: reviewScore,
+		blockScore:  blockScore,
+	}
+}
+
+// DefaultRules return all built-in rules
+func DefaultRules(store db.Store, config util.Config) []Rule {
+	return []Rule{
+		NewVelocityRule(store, config.FraudVelocityCount, config.FraudVelocityWindow),
+		NewAmountAnomalyRule(store),
+		NewFirstPayeeRule(store, config.FraudLargeAmount),
+		NewSessionAgeRule(store, config.FraudMinSessionAge),
+	}
+}
+
+// Evaluate run all rules against the transfer and persist the decision
+func (engine *Engine) Evaluate(ctx context.Context, transfer Transfer) (Decision, error) {
+	decision := Decision{
+		Action:  ActionAllow,
+		Signals: []Signal{},
+	}
+
+	for _, rule := range engine.rules {
+		score, reason, err := rule.Evaluate(ctx, transfer)
+		if err != nil {
+			return Decision{}, fmt.Errorf("fraud rule %s failed: %w", rule.Name(), err)
+		}
+		if score == 0 {
+			continue
+		}
+		decision.Score += score
+		decision.Signals = append(decision.Signals, Signal{
+			Rule:   rule.Name(),
+			Score:  score,
+			Reason: reason,
+		})
+	}
+
+	switch {
+	case engine.blockScore > 0 && decision.Score >= engine.blockScore:
+		decision.Action = ActionBlock
+	case engine.reviewScore > 0 && decision.Score >= engine.reviewScore:
+		decision.Action = ActionReview
+	}
+
+	signals, err := json.Marshal(decision.Signals)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed marshal fraud signals: %w", err)
+	}
+	record, err := engine.store.CreateFraudDecision(ctx, db.CreateFraudDecisionParams{
+		Username:      transfer.Username,
+		FromAccountID: transfer.FromAccount.ID,
+		ToAccountID:   transfer.ToAccount.ID,
+		Amount:        transfer.Amount,
+		Score:         decision.Score,
+		Action:        string(decision.Action),
+		Signals:       signals,
+	})
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to store fraud decision: %w", err)
+	}
+	decision.ID = record.ID
+
+	return decision, nil
+}
@@ -0,0 +1,283 @@
+package fraud
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// staticRule return the same score for every transfer
+type staticRule struct {
+	name  string
+	score int32
+}
+
+func (rule staticRule) Name() string {
+	return rule.name
+}
+
+func (rule staticRule) Evaluate(ctx context.Context, transfer Transfer) (int32, string, error) {
+	return rule.score, "static", nil
+}
+
+func randomTransfer() Transfer {
+	return Transfer{
+		Username: util.RandomOwner(),
+		FromAccount: db.Account{
+			ID:       util.RandomInt(1, 1000),
+			Owner:    util.RandomOwner(),
+			Carrency: util.USD,
+		},
+		ToAccount: db.Account{
+			ID:       util.RandomInt(1001, 2000),
+			Owner:    util.RandomOwner(),
+			Carrency: util.USD,
+		},
+		Amount: util.RandomInt(1, 1000),
+	}
+}
+
+func TestEngineEvaluate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		rules  []Rule
+		action Action
+		score  int32
+	}{
+		{
+			name:   "Allow",
+			rules:  []Rule{staticRule{"a", 0}, staticRule{"b", 20}},
+			action: ActionAllow,
+			score:  20,
+		}, {
+			name:   "Review",
+			rules:  []Rule{staticRule{"a", 30}, staticRule{"b", 20}},
+			action: ActionReview,
+			score:  50,
+		}, {
+			name:   "Block",
+			rules:  []Rule{staticRule{"a", 40}, staticRule{"b", 40}},
+			action: ActionBlock,
+			score:  80,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+
+			transfer := randomTransfer()
+			store.EXPECT().
+				CreateFraudDecision(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.CreateFraudDecisionParams) (db.FraudDecision, error) {
+					require.Equal(t, transfer.Username, arg.Username)
+					require.Equal(t, transfer.FromAccount.ID, arg.FromAccountID)
+					require.Equal(t, transfer.ToAccount.ID, arg.ToAccountID)
+					require.Equal(t, transfer.Amount, arg.Amount)
+					require.Equal(t, tc.score, arg.Score)
+					require.Equal(t, string(tc.action), arg.Action)
+
+					var signals []Signal
+					require.NoError(t, json.Unmarshal(arg.Signals, &signals))
+					for _, signal := range signals {
+						require.NotZero(t, signal.Score)
+					}
+					return db.FraudDecision{ID: 7}, nil
+				})
+
+			engine := NewEngineWithRules(store, 50, 80, tc.rules...)
+			decision, err := engine.Evaluate(context.Background(), transfer)
+			require.NoError(t, err)
+			require.Equal(t, int64(7), decision.ID)
+			require.Equal(t, tc.action, decision.Action)
+			require.Equal(t, tc.score, decision.Score)
+		})
+	}
+}
+
+func TestEngineRuleError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		GetAccountTransferStats(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.GetAccountTransferStatsRow{}, sql.ErrConnDone)
+	store.EXPECT().
+		CreateFraudDecision(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	engine := NewEngineWithRules(store, 50, 80, NewAmountAnomalyRule(store))
+	_, err := engine.Evaluate(context.Background(), randomTransfer())
+	require.ErrorIs(t, err, sql.ErrConnDone)
+}
+
+func TestVelocityRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	transfer := randomTransfer()
+
+	store.EXPECT().
+		CountTransfersFromAccountSince(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ context.Context, arg db.CountTransfersFromAccountSinceParams) (int64, error) {
+			require.Equal(t, transfer.FromAccount.ID, arg.FromAccountID.Int64)
+			require.WithinDuration(t, time.Now().Add(-10*time.Minute), arg.Since, time.Second)
+			return 5, nil
+		})
+
+	score, _, err := NewVelocityRule(store, 5, 10*time.Minute).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, VelocityScore, score)
+
+	score, _, err = NewVelocityRule(store, 6, 10*time.Minute).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Zero(t, score)
+}
+
+func TestAmountAnomalyRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	transfer := randomTransfer()
+	transfer.Amount = 1000
+
+	store.EXPECT().
+		GetAccountTransferStats(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.GetAccountTransferStatsRow{TransferCount: 10, AverageAmount: 100}, nil)
+	score, _, err := NewAmountAnomalyRule(store).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, AmountAnomalyScore, score)
+
+	// short history is not enough to flag the amount
+	store.EXPECT().
+		GetAccountTransferStats(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.GetAccountTransferStatsRow{TransferCount: 1, AverageAmount: 10}, nil)
+	score, _, err = NewAmountAnomalyRule(store).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Zero(t, score)
+}
+
+func TestFirstPayeeRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	transfer := randomTransfer()
+	transfer.Amount = 5000
+
+	store.EXPECT().
+		CountTransfersBetweenAccounts(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int64(0), nil)
+	score, _, err := NewFirstPayeeRule(store, 1000).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, FirstPayeeScore, score)
+
+	store.EXPECT().
+		CountTransfersBetweenAccounts(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int64(3), nil)
+	score, _, err = NewFirstPayeeRule(store, 1000).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Zero(t, score)
+
+	// transfer between own accounts is never flagged
+	transfer.ToAccount.Owner = transfer.FromAccount.Owner
+	score, _, err = NewFirstPayeeRule(store, 1000).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Zero(t, score)
+}
+
+func TestFirstPayeeRuleCountsOnlyPostedTransfers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	transfer := randomTransfer()
+	transfer.Amount = 5000
+
+	// an earlier rejected transfer to the payee must not make it known
+	store.EXPECT().
+		CountTransfersBetweenAccounts(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CountTransfersBetweenAccountsParams) (int64, error) {
+			require.Equal(t, db.TransferStatusPosted, arg.Status)
+			require.Equal(t, transfer.ToAccount.ID, arg.ToAccountID.Int64)
+			return 0, nil
+		})
+	score, _, err := NewFirstPayeeRule(store, 1000).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, FirstPayeeScore, score)
+}
+
+func TestSessionAgeRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	transfer := randomTransfer()
+	transfer.SessionID = uuid.New()
+	session := db.Session{
+		ID:        transfer.SessionID,
+		FamilyID:  uuid.New(),
+		CreatedAt: time.Now().Add(-time.Minute),
+	}
+
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(transfer.SessionID)).
+		Times(1).
+		Return(session, nil)
+	store.EXPECT().
+		GetSessionFamilyStartedAt(gomock.Any(), gomock.Eq(session.FamilyID)).
+		Times(1).
+		Return(session.CreatedAt, nil)
+	score, _, err := NewSessionAgeRule(store, 5*time.Minute).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, SessionAgeScore, score)
+
+	// the session was just rotated, but the login happened an hour ago
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(transfer.SessionID)).
+		Times(1).
+		Return(session, nil)
+	store.EXPECT().
+		GetSessionFamilyStartedAt(gomock.Any(), gomock.Eq(session.FamilyID)).
+		Times(1).
+		Return(time.Now().Add(-time.Hour), nil)
+	score, _, err = NewSessionAgeRule(store, 5*time.Minute).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Zero(t, score)
+
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(transfer.SessionID)).
+		Times(1).
+		Return(db.Session{}, db.ErrRecordNotFound)
+	score, _, err = NewSessionAgeRule(store, 5*time.Minute).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, SessionAgeScore, score)
+
+	// the token is not bound to a login session
+	transfer.SessionID = uuid.Nil
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Any()).
+		Times(0)
+	score, _, err = NewSessionAgeRule(store, 5*time.Minute).Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, SessionAgeScore, score)
+}
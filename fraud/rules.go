@@ -0,0 +1,191 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Scores of the built-in rules
+const (
+	VelocityScore      int32 = 40
+	AmountAnomalyScore int32 = 30
+	FirstPayeeScore    int32 = 30
+	SessionAgeScore    int32 = 20
+)
+
+const (
+	amountHistoryPeriod   = 90 * 24 * time.Hour
+	amountHistoryMinCount = 3
+	amountAnomalyFactor   = 5
+)
+
+// VelocityRule flag account which sent too many transfers in a short window
+type VelocityRule struct {
+	store  db.Store
+	limit  int64
+	window time.Duration
+}
+
+func NewVelocityRule(store db.Store, limit int64, window time.Duration) *VelocityRule {
+	return &VelocityRule{
+		store:  store,
+		limit:  limit,
+		window: window,
+	}
+}
+
+func (rule *VelocityRule) Name() string {
+	return "velocity"
+}
+
+func (rule *VelocityRule) Evaluate(ctx context.Context, transfer Transfer) (int32, string, error) {
+	if rule.limit <= 0 || rule.window <= 0 {
+		return 0, "", nil
+	}
+	count, err := rule.store.CountTransfersFromAccountSince(ctx, db.CountTransfersFromAccountSinceParams{
+		FromAccountID: pgtype.Int8{
+			Int64: transfer.FromAccount.ID,
+			Valid: true,
+		},
+		Since: time.Now().Add(-rule.window),
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	if count < rule.limit {
+		return 0, "", nil
+	}
+	return VelocityScore, fmt.Sprintf("%d transfers in the last %s", count, rule.window), nil
+}
+
+// AmountAnomalyRule flag amount which is much bigger than the usual transfers of the account
+type AmountAnomalyRule struct {
+	store db.Store
+}
+
+func NewAmountAnomalyRule(store db.Store) *AmountAnomalyRule {
+	return &AmountAnomalyRule{
+		store: store,
+	}
+}
+
+func (rule *AmountAnomalyRule) Name() string {
+	return "amount_anomaly"
+}
+
+func (rule *AmountAnomalyRule) Evaluate(ctx context.Context, transfer Transfer) (int32, string, error) {
+	stats, err := rule.store.GetAccountTransferStats(ctx, db.GetAccountTransferStatsParams{
+		FromAccountID: pgtype.Int8{
+			Int64: transfer.FromAccount.ID,
+			Valid: true,
+		},
+		Since:  time.Now().Add(-amountHistoryPeriod),
+		Status: db.TransferStatusPosted,
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	// not enough history to say what is usual for the account
+	if stats.TransferCount < amountHistoryMinCount || stats.AverageAmount <= 0 {
+		return 0, "", nil
+	}
+	if transfer.Amount <= stats.AverageAmount*amountAnomalyFactor {
+		return 0, "", nil
+	}
+	return AmountAnomalyScore, fmt.Sprintf("amount %d is more than %d times the average %d",
+		transfer.Amount, amountAnomalyFactor, stats.AverageAmount), nil
+}
+
+// FirstPayeeRule flag large amount sent to the account of another user for the first time
+type FirstPayeeRule struct {
+	store       db.Store
+	largeAmount int64
+}
+
+func NewFirstPayeeRule(store db.Store, largeAmount int64) *FirstPayeeRule {
+	return &FirstPayeeRule{
+		store:       store,
+		largeAmount: largeAmount,
+	}
+}
+
+func (rule *FirstPayeeRule) Name() string {
+	return "new_payee_large_amount"
+}
+
+func (rule *FirstPayeeRule) Evaluate(ctx context.Context, transfer Transfer) (int32, string, error) {
+	if rule.largeAmount <= 0 || transfer.Amount < rule.largeAmount {
+		return 0, "", nil
+	}
+	if transfer.FromAccount.Owner == transfer.ToAccount.Owner {
+		return 0, "", nil
+	}
+	count, err := rule.store.CountTransfersBetweenAccounts(ctx, db.CountTransfersBetweenAccountsParams{
+		FromAccountID: pgtype.Int8{
+			Int64: transfer.FromAccount.ID,
+			Valid: true,
+		},
+		ToAccountID: pgtype.Int8{
+			Int64: transfer.ToAccount.ID,
+			Valid: true,
+		},
+		// rejected or still reviewed transfers do not make the payee known
+		Status: db.TransferStatusPosted,
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	if count > 0 {
+		return 0, "", nil
+	}
+	return FirstPayeeScore, fmt.Sprintf("first transfer to account %d with amount %d", transfer.ToAccount.ID, transfer.Amount), nil
+}
+
+// SessionAgeRule flag transfer made right after login, typical for stolen credentials
+type SessionAgeRule struct {
+	store  db.Store
+	minAge time.Duration
+}
+
+func NewSessionAgeRule(store db.Store, minAge time.Duration) *SessionAgeRule {
+	return &SessionAgeRule{
+		store:  store,
+		minAge: minAge,
+	}
+}
+
+func (rule *SessionAgeRule) Name() string {
+	return "session_age"
+}
+
+func (rule *SessionAgeRule) Evaluate(ctx context.Context, transfer Transfer) (int32, string, error) {
+	if rule.minAge <= 0 {
+		return 0, "", nil
+	}
+	if transfer.SessionID == uuid.Nil {
+		return SessionAgeScore, "no login session", nil
+	}
+	session, err := rule.store.GetSession(ctx, transfer.SessionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return SessionAgeScore, "no login session", nil
+		}
+		return 0, "", err
+	}
+	// every refresh token rotation creates the new session, the age counts from the login
+	startedAt, err := rule.store.GetSessionFamilyStartedAt(ctx, session.FamilyID)
+	if err != nil {
+		return 0, "", err
+	}
+	age := time.Since(startedAt)
+	if age >= rule.minAge {
+		return 0, "", nil
+	}
+	return SessionAgeScore, fmt.Sprintf("session is %s old", age.Round(time.Second)), nil
+}
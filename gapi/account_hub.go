@@ -0,0 +1,138 @@
+package gapi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	accountSubscriberBuffer = 16
+	accountListenRetryDelay = time.Second
+)
+
+// AccountHub fan-out account events received from postgres to the WatchAccount streams
+type AccountHub struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[*AccountSubscriber]struct{}
+	closed      bool
+}
+
+// AccountSubscriber receive events of one account.
+// Events channel is closed when subscriber is too slow or hub is closed.
+type AccountSubscriber struct {
+	accountID int64
+	events    chan db.AccountEvent
+	lagged    bool
+}
+
+func NewAccountHub() *AccountHub {
+	return &AccountHub{
+		subscribers: make(map[int64]map[*AccountSubscriber]struct{}),
+	}
+}
+
+// Events return channel with account events of the subscriber
+func (sub *AccountSubscriber) Events() <-chan db.AccountEvent {
+	return sub.events
+}
+
+// Subscribe register new subscriber for the account events.
+// It returns false when hub is already closed.
+func (hub *AccountHub) Subscribe(accountID int64) (*AccountSubscriber, bool) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if hub.closed {
+		return nil, false
+	}
+	sub := &AccountSubscriber{
+		accountID: accountID,
+		events:    make(chan db.AccountEvent, accountSubscriberBuffer),
+	}
+	if hub.subscribers[accountID] == nil {
+		hub.subscribers[accountID] = make(map[*AccountSubscriber]struct{})
+	}
+	hub.subscribers[accountID][sub] = struct{}{}
+	return sub, true
+}
+
+// Unsubscribe remove subscriber from the hub, it is safe to call it more than once
+func (hub *AccountHub) Unsubscribe(sub *AccountSubscriber) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	hub.remove(sub)
+}
+
+// Lagged report if subscriber was removed because it did not read events in time
+func (hub *AccountHub) Lagged(sub *AccountSubscriber) bool {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	return sub.lagged
+}
+
+// Publish send event to every subscriber of the account without blocking.
+// Subscriber with the full buffer is dropped, so one slow client can not stall the others.
+func (hub *AccountHub) Publish(event db.AccountEvent) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for sub := range hub.subscribers[event.AccountID] {
+		select {
+		case sub.events <- event:
+		default:
+			sub.lagged = true
+			hub.remove(sub)
+		}
+	}
+}
+
+// Close disconnect all subscribers, new subscriptions are rejected after that
+func (hub *AccountHub) Close() {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	hub.closed = true
+	for _, subs := range hub.subscribers {
+		for sub := range subs {
+			hub.remove(sub)
+		}
+	}
+}
+
+// Run listen account events from the store until context is canceled.
+// Listening is restarted when the connection to postgres is lost.
+func (hub *AccountHub) Run(ctx context.Context, store db.Store) error {
+	for {
+		err := store.ListenAccountEvents(ctx, hub.Publish)
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Error().Err(err).Msg("account events listener stopped, restart it")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(accountListenRetryDelay):
+		}
+	}
+}
+
+// remove must be called with the hub lock held
+func (hub *AccountHub) remove(sub *AccountSubscriber) {
+	subs, ok := hub.subscribers[sub.accountID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(hub.subscribers, sub.accountID)
+	}
+	close(sub.events)
+}
@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	"testing"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountHubPublish(t *testing.T) {
+	hub := NewAccountHub()
+
+	sub1, ok := hub.Subscribe(1)
+	require.True(t, ok)
+	sub2, ok := hub.Subscribe(2)
+	require.True(t, ok)
+
+	event := db.AccountEvent{AccountID: 1, Balance: 100, EntryID: 10, EntryAmount: 100}
+	hub.Publish(event)
+
+	require.Equal(t, event, <-sub1.Events())
+	require.Empty(t, sub2.Events())
+}
+
+func TestAccountHubUnsubscribe(t *testing.T) {
+	hub := NewAccountHub()
+
+	sub, ok := hub.Subscribe(1)
+	require.True(t, ok)
+
+	hub.Unsubscribe(sub)
+	hub.Unsubscribe(sub)
+
+	_, ok = <-sub.Events()
+	require.False(t, ok)
+	require.False(t, hub.Lagged(sub))
+	require.Empty(t, hub.subscribers)
+
+	// publish without subscribers must not panic
+	hub.Publish(db.AccountEvent{AccountID: 1})
+}
+
+func TestAccountHubDropSlowSubscriber(t *testing.T) {
+	hub := NewAccountHub()
+
+	slow, ok := hub.Subscribe(1)
+	require.True(t, ok)
+
+	for i := 0; i <= accountSubscriberBuffer; i++ {
+		hub.Publish(db.AccountEvent{AccountID: 1, EntryID: int64(i)})
+	}
+
+	require.True(t, hub.Lagged(slow))
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	require.Equal(t, accountSubscriberBuffer, received)
+}
+
+func TestAccountHubClose(t *testing.T) {
+	hub := NewAccountHub()
+
+	sub, ok := hub.Subscribe(1)
+	require.True(t, ok)
+
+	hub.Close()
+
+	_, ok = <-sub.Events()
+	require.False(t, ok)
+	require.False(t, hub.Lagged(sub))
+
+	_, ok = hub.Subscribe(1)
+	require.False(t, ok)
+}
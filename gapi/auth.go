@@ -5,8 +5,14 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dubass83/simplebank/apikey"
+	"github.com/dubass83/simplebank/pb"
 	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 )
 
 const (
@@ -14,7 +20,145 @@ const (
 	authType            = "bearer"
 )
 
-func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+// apiKeyScopes scope required from API key by every RPC it may call,
+// API keys are rejected by all other RPCs
+var apiKeyScopes = map[string]string{
+	pb.SimpleBank_GetUser_FullMethodName:                   util.ScopeUsersRead,
+	pb.SimpleBank_GetAccount_FullMethodName:                util.ScopeAccountsRead,
+	pb.SimpleBank_ListAccounts_FullMethodName:              util.ScopeAccountsRead,
+	pb.SimpleBank_WatchAccount_FullMethodName:              util.ScopeAccountsRead,
+	pb.SimpleBank_CreateAccount_FullMethodName:             util.ScopeAccountsWrite,
+	pb.SimpleBank_DeleteAccount_FullMethodName:             util.ScopeAccountsWrite,
+	pb.SimpleBank_CreateTransfer_FullMethodName:            util.ScopeTransfersWrite,
+	pb.SimpleBank_CreateWebhookSubscription_FullMethodName: util.ScopeWebhooks,
+	pb.SimpleBank_ListWebhookSubscriptions_FullMethodName:  util.ScopeWebhooks,
+	pb.SimpleBank_DeleteWebhookSubscription_FullMethodName: util.ScopeWebhooks,
+	pb.SimpleBank_ListWebhookDeliveries_FullMethodName:     util.ScopeWebhooks,
+	pb.SimpleBank_ReplayWebhookDelivery_FullMethodName:     util.ScopeWebhooks,
+}
+
+// methodPolicy access rule of the RPC, RPCs missing from methodPolicies
+// are available to any authenticated caller without impersonation
+type methodPolicy struct {
+	// public RPC is called without authorization header
+	public bool
+	// permission required from the role of the caller
+	permission string
+	// impersonation RPC only reads data of the user, so the banker may call it
+	// with the impersonated token, impersonated tokens are rejected by all other RPCs
+	impersonation bool
+}
+
+var methodPolicies = map[string]methodPolicy{
+	pb.SimpleBank_CreateUser_FullMethodName:           {public: true},
+	pb.SimpleBank_LoginUser_FullMethodName:            {public: true},
+	pb.SimpleBank_VerifyTotpLogin_FullMethodName:      {public: true},
+	pb.SimpleBank_VerifyEmail_FullMethodName:          {public: true},
+	pb.SimpleBank_RenewAccessToken_FullMethodName:     {public: true},
+	pb.SimpleBank_Logout_FullMethodName:               {public: true},
+	pb.SimpleBank_RequestPasswordReset_FullMethodName: {public: true},
+	pb.SimpleBank_ResetPassword_FullMethodName:        {public: true},
+	pb.SimpleBank_RequestLoginLink_FullMethodName:     {public: true},
+	pb.SimpleBank_RedeemLoginLink_FullMethodName:      {public: true},
+	// grpcurl and other clients discover the API with the reflection service
+	reflectionv1.ServerReflection_ServerReflectionInfo_FullMethodName:      {public: true},
+	reflectionv1alpha.ServerReflection_ServerReflectionInfo_FullMethodName: {public: true},
+	pb.SimpleBank_ListPendingTransfers_FullMethodName:                      {permission: util.PermissionTransfersApprove},
+	pb.SimpleBank_UnlockUser_FullMethodName:                                {permission: util.PermissionUsersUnlock},
+	pb.SimpleBank_RevokeUserSessions_FullMethodName:                        {permission: util.PermissionSessionsRevokeAny},
+	pb.SimpleBank_AssignRole_FullMethodName:                                {permission: util.PermissionRolesAssign},
+	pb.SimpleBank_StartImpersonation_FullMethodName:                        {permission: util.PermissionUsersImpersonate},
+	pb.SimpleBank_SearchUsers_FullMethodName:                               {permission: util.PermissionUsersReadAny},
+	pb.SimpleBank_AdminGetAccount_FullMethodName:                           {permission: util.PermissionAccountsReadAny},
+	pb.SimpleBank_AdminListAccounts_FullMethodName:                         {permission: util.PermissionAccountsReadAny},
+	pb.SimpleBank_ApproveTransfer_FullMethodName:                           {permission: util.PermissionTransfersApprove},
+	pb.SimpleBank_RejectTransfer_FullMethodName:                            {permission: util.PermissionTransfersApprove},
+	pb.SimpleBank_GetUser_FullMethodName:                                   {impersonation: true},
+	pb.SimpleBank_GetAccount_FullMethodName:                                {impersonation: true},
+	pb.SimpleBank_ListAccounts_FullMethodName:                              {impersonation: true},
+	pb.SimpleBank_WatchAccount_FullMethodName:                              {impersonation: true},
+	pb.SimpleBank_ListMySessions_FullMethodName:                            {impersonation: true},
+	pb.SimpleBank_ListMyLoginEvents_FullMethodName:                         {impersonation: true},
+	pb.SimpleBank_ListApiKeys_FullMethodName:                               {impersonation: true},
+	pb.SimpleBank_ListWebhookSubscriptions_FullMethodName:                  {impersonation: true},
+	pb.SimpleBank_ListWebhookDeliveries_FullMethodName:                     {impersonation: true},
+}
+
+type payloadKey struct{}
+
+// AuthInterceptor authorize the caller by the policy of the method and
+// put its payload into the context of the handler
+func (server *Server) AuthInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp any, err error) {
+	ctx, err = server.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// AuthStreamInterceptor same as AuthInterceptor for streaming RPCs
+func (server *Server) AuthStreamInterceptor(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, err := server.authorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authServerStream{ServerStream: stream, ctx: ctx})
+}
+
+// authServerStream replace context of the stream with the authorized one
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+// authorize return context with payload of the caller, public methods
+// are passed without authentication
+func (server *Server) authorize(ctx context.Context, method string) (context.Context, error) {
+	policy := methodPolicies[method]
+	if policy.public {
+		return ctx, nil
+	}
+	payload, err := server.authenticate(ctx, method)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	if payload.IsImpersonated() {
+		if err := server.auditImpersonation(ctx, payload, method, policy); err != nil {
+			return nil, err
+		}
+	}
+	if policy.permission != "" {
+		if err := server.requirePermission(ctx, payload, policy.permission); err != nil {
+			return nil, err
+		}
+	}
+	return context.WithValue(ctx, payloadKey{}, payload), nil
+}
+
+// authPayload return payload of the caller put into context by authorize
+func authPayload(ctx context.Context) (*token.Payload, error) {
+	payload, ok := ctx.Value(payloadKey{}).(*token.Payload)
+	if !ok {
+		return nil, fmt.Errorf("call is not authorized")
+	}
+	return payload, nil
+}
+
+func (server *Server) authenticate(ctx context.Context, method string) (*token.Payload, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("cannot get metadata from incoming context")
@@ -23,14 +167,40 @@ func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error)
 	if len(values) == 0 {
 		return nil, fmt.Errorf("authorization token does not provided in the metadata")
 	}
-	authHeader := values[0]
-	fields := strings.Split(authHeader, " ")
-	if authType != strings.ToLower(fields[0]) {
+	fields := strings.Fields(values[0])
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid authorization header format")
+	}
+	switch strings.ToLower(fields[0]) {
+	case authType:
+	case apikey.AuthorizationType:
+		return server.authorizeApiKey(ctx, method, fields[1])
+	default:
 		return nil, fmt.Errorf("not supported authorization type: %s", strings.ToLower(fields[0]))
 	}
 	payload, err := server.tokenMaker.VerifyToken(fields[1])
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %s", err)
 	}
+	if payload.Purpose != "" {
+		return nil, fmt.Errorf("%s token can not be used for authorization", payload.Purpose)
+	}
+	// session blocked or password changed after the token was issued
+	if err := server.revocation.Check(ctx, payload); err != nil {
+		return nil, fmt.Errorf("revoked token: %s", err)
+	}
+	return payload, nil
+}
+
+// authorizeApiKey check the key and its scope for the called RPC
+func (server *Server) authorizeApiKey(ctx context.Context, method string, key string) (*token.Payload, error) {
+	payload, err := server.apiKeys.Authenticate(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api key: %s", err)
+	}
+	scope, ok := apiKeyScopes[method]
+	if !ok || !payload.HasScope(scope) {
+		return nil, fmt.Errorf("api key is not allowed to call %s", method)
+	}
 	return payload, nil
 }
@@ -0,0 +1,375 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/apikey"
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizeApiKey(t *testing.T) {
+	user, _ := randomUser()
+	secret, secretHash, err := apikey.NewSecret()
+	require.NoError(t, err)
+	apiKey := randomApiKey(user.Username)
+	apiKey.SecretHash = secretHash
+	apiKey.Scopes = []string{util.ScopeAccountsRead}
+
+	testCases := []struct {
+		name   string
+		method string
+		ok     bool
+	}{
+		{
+			name:   "ScopeGranted",
+			method: pb.SimpleBank_GetAccount_FullMethodName,
+			ok:     true,
+		},
+		{
+			name:   "ScopeMissing",
+			method: pb.SimpleBank_CreateTransfer_FullMethodName,
+		},
+		{
+			name:   "MethodNotAllowed",
+			method: pb.SimpleBank_RevokeAllOtherSessions_FullMethodName,
+		},
+		{
+			name:   "UnknownMethod",
+			method: "",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+				Times(1).
+				Return(apiKey, nil)
+			store.EXPECT().
+				GetUser(gomock.Any(), gomock.Eq(user.Username)).
+				Times(1).
+				Return(user, nil)
+
+			server := NewTestServer(t, store, nil)
+			ctx := BuildApiKeyContext(apikey.Format(apiKey.ID, secret))
+			ctx, err := server.authorize(ctx, tc.method)
+			if !tc.ok {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			payload, err := authPayload(ctx)
+			require.NoError(t, err)
+			require.Equal(t, user.Username, payload.Username)
+			require.Equal(t, apiKey.Scopes, payload.Scopes)
+		})
+	}
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	user, _ := randomUser()
+
+	testCases := []struct {
+		name         string
+		method       string
+		buildContext func(t *testing.T, tokenMaker token.Maker) context.Context
+		code         codes.Code
+		authorized   bool
+	}{
+		{
+			name:   "PublicWithoutToken",
+			method: pb.SimpleBank_LoginUser_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			code: codes.OK,
+		},
+		{
+			name:   "Authenticated",
+			method: pb.SimpleBank_GetUser_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			code:       codes.OK,
+			authorized: true,
+		},
+		{
+			name:   "NoToken",
+			method: pb.SimpleBank_GetUser_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "UnknownMethodNeedsToken",
+			method: "/pb.SimpleBank/NewMethod",
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "HeaderWithoutToken",
+			method: pb.SimpleBank_GetUser_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				md := metadata.MD{authorizationHeader: []string{"bearer"}}
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "ExpiredToken",
+			method: pb.SimpleBank_GetUser_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, -time.Minute)
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "RefreshToken",
+			method: pb.SimpleBank_GetUser_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute,
+					token.WithPurpose(token.PurposeRefresh))
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "PermissionDenied",
+			method: pb.SimpleBank_UnlockUser_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			code: codes.PermissionDenied,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			server := NewTestServer(t, mockdb.NewMockStore(ctrl), nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+
+			called := false
+			handler := func(ctx context.Context, req any) (any, error) {
+				called = true
+				payload, err := authPayload(ctx)
+				if tc.authorized {
+					require.NoError(t, err)
+					require.Equal(t, user.Username, payload.Username)
+				} else {
+					require.Error(t, err)
+				}
+				return nil, nil
+			}
+			_, err := server.AuthInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			require.Equal(t, tc.code, status.Code(err))
+			require.Equal(t, tc.code == codes.OK, called)
+		})
+	}
+}
+
+// contextStream stream carrying only the incoming context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *contextStream) Context() context.Context {
+	return stream.ctx
+}
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	user, _ := randomUser()
+
+	testCases := []struct {
+		name         string
+		method       string
+		buildContext func(t *testing.T, tokenMaker token.Maker) context.Context
+		code         codes.Code
+	}{
+		{
+			name:   "Reflection",
+			method: reflectionv1.ServerReflection_ServerReflectionInfo_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			code: codes.OK,
+		},
+		{
+			name:   "ReflectionV1Alpha",
+			method: reflectionv1alpha.ServerReflection_ServerReflectionInfo_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			code: codes.OK,
+		},
+		{
+			name:   "Authenticated",
+			method: pb.SimpleBank_WatchAccount_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			code: codes.OK,
+		},
+		{
+			name:   "NoToken",
+			method: pb.SimpleBank_WatchAccount_FullMethodName,
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			code: codes.Unauthenticated,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			server := NewTestServer(t, mockdb.NewMockStore(ctrl), nil)
+			stream := &contextStream{ctx: tc.buildContext(t, server.tokenMaker)}
+
+			called := false
+			handler := func(srv any, stream grpc.ServerStream) error {
+				called = true
+				return nil
+			}
+			err := server.AuthStreamInterceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: tc.method}, handler)
+			require.Equal(t, tc.code, status.Code(err))
+			require.Equal(t, tc.code == codes.OK, called)
+		})
+	}
+}
+
+func TestAuthInterceptorImpersonation(t *testing.T) {
+	user, _ := randomUser()
+	banker := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		method     string
+		buildStubs func(store *mockdb.MockStore)
+		code       codes.Code
+	}{
+		{
+			name:   "Allowed",
+			method: pb.SimpleBank_GetUser_FullMethodName,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImpersonationEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateImpersonationEventParams) (db.ImpersonationEvent, error) {
+						require.Equal(t, banker, arg.Actor)
+						require.Equal(t, user.Username, arg.Subject)
+						require.Equal(t, pb.SimpleBank_GetUser_FullMethodName, arg.Method)
+						require.True(t, arg.Allowed)
+						return db.ImpersonationEvent{}, nil
+					})
+			},
+			code: codes.OK,
+		},
+		{
+			name:   "MoneyMovement",
+			method: pb.SimpleBank_CreateTransfer_FullMethodName,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImpersonationEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateImpersonationEventParams) (db.ImpersonationEvent, error) {
+						require.Equal(t, pb.SimpleBank_CreateTransfer_FullMethodName, arg.Method)
+						require.False(t, arg.Allowed)
+						return db.ImpersonationEvent{}, nil
+					})
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			name:   "NotListedMethod",
+			method: pb.SimpleBank_CreateAccount_FullMethodName,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImpersonationEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateImpersonationEventParams) (db.ImpersonationEvent, error) {
+						require.Equal(t, pb.SimpleBank_CreateAccount_FullMethodName, arg.Method)
+						require.False(t, arg.Allowed)
+						return db.ImpersonationEvent{}, nil
+					})
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			name:   "AuditError",
+			method: pb.SimpleBank_GetUser_FullMethodName,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImpersonationEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImpersonationEvent{}, sql.ErrConnDone)
+			},
+			code: codes.Internal,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			ctx := BuildContext(t, server.tokenMaker, user.Username, user.Role, time.Minute, token.WithActor(banker))
+
+			called := false
+			handler := func(ctx context.Context, req any) (any, error) {
+				called = true
+				payload, err := authPayload(ctx)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, payload.Username)
+				require.Equal(t, banker, payload.Actor)
+				return nil, nil
+			}
+			_, err := server.AuthInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			require.Equal(t, tc.code, status.Code(err))
+			require.Equal(t, tc.code == codes.OK, called)
+		})
+	}
+}
+
+func TestMethodPoliciesPermissionsSupported(t *testing.T) {
+	for method, policy := range methodPolicies {
+		if policy.permission == "" {
+			continue
+		}
+		require.True(t, util.IfSupportedPermission(policy.permission), "%s requires unknown permission %s", method, policy.permission)
+	}
+}
@@ -3,17 +3,32 @@ package gapi
 import (
 	db "github.com/dubass83/simplebank/db/sqlc"
 	"github.com/dubass83/simplebank/pb"
+	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func convertUser(user db.User) *pb.User {
-	return &pb.User{
+	pbUser := &pb.User{
 		Username:         user.Username,
 		FullName:         user.FullName,
 		Email:            user.Email,
 		PaswordChangedAt: timestamppb.New(user.PasswordChangedAt),
 		CreatedAt:        timestamppb.New(user.CreatedAt),
+		TotpEnabled:      user.TotpEnabled,
+		PendingEmail:     user.PendingEmail.String,
+		IsEmailVerified:  user.IsEmailVerified,
 	}
+	if user.LockedUntil.Valid {
+		pbUser.LockedUntil = timestamppb.New(user.LockedUntil.Time)
+	}
+	return pbUser
+}
+
+func convertUsers(users []db.User) (pbUsers []*pb.User) {
+	for _, user := range users {
+		pbUsers = append(pbUsers, convertUser(user))
+	}
+	return
 }
 
 func convertAccount(account db.Account) *pb.Account {
@@ -38,13 +53,27 @@ func convertAccounts(accounts []db.Account) (pbAccs []*pb.Account) {
 }
 
 func convertTransfer(transfer db.Transfer) *pb.Transfer {
-	return &pb.Transfer{
+	pbTransfer := &pb.Transfer{
 		Id:            transfer.ID,
 		FromAccountId: transfer.FromAccountID.Int64,
 		ToAccountId:   transfer.ToAccountID.Int64,
 		Amount:        transfer.Amount,
 		CreatedAt:     timestamppb.New(transfer.CreatedAt),
+		Status:        transfer.Status,
+		InitiatedBy:   transfer.InitiatedBy.String,
+		ReviewedBy:    transfer.ReviewedBy.String,
+	}
+	if transfer.ReviewedAt.Valid {
+		pbTransfer.ReviewedAt = timestamppb.New(transfer.ReviewedAt.Time)
 	}
+	return pbTransfer
+}
+
+func convertTransfers(transfers []db.Transfer) (pbTransfers []*pb.Transfer) {
+	for _, transfer := range transfers {
+		pbTransfers = append(pbTransfers, convertTransfer(transfer))
+	}
+	return
 }
 
 func convertEntry(entry db.Entry) *pb.Entry {
@@ -65,3 +94,117 @@ func convertTransferTx(txResp db.TransferTxResult) *pb.CreateTransferTxResponse
 		ToEntry:     convertEntry(txResp.ToEntry),
 	}
 }
+
+func convertWebhookSubscription(subscription db.WebhookSubscription) *pb.WebhookSubscription {
+	return &pb.WebhookSubscription{
+		Id:         subscription.ID,
+		Owner:      subscription.Owner,
+		Url:        subscription.Url,
+		EventTypes: subscription.EventTypes,
+		IsActive:   subscription.IsActive,
+		CreatedAt:  timestamppb.New(subscription.CreatedAt),
+	}
+}
+
+func convertWebhookSubscriptions(subscriptions []db.WebhookSubscription) (pbSubscriptions []*pb.WebhookSubscription) {
+	for _, subscription := range subscriptions {
+		pbSubscriptions = append(pbSubscriptions, convertWebhookSubscription(subscription))
+	}
+	return
+}
+
+func convertApiKey(apiKey db.ApiKey) *pb.ApiKey {
+	return &pb.ApiKey{
+		Id:        apiKey.ID,
+		Owner:     apiKey.Owner,
+		Name:      apiKey.Name,
+		Scopes:    apiKey.Scopes,
+		IsRevoked: apiKey.IsRevoked,
+		ExpiredAt: timestamppb.New(apiKey.ExpiredAt),
+		CreatedAt: timestamppb.New(apiKey.CreatedAt),
+	}
+}
+
+func convertApiKeys(apiKeys []db.ApiKey) (pbApiKeys []*pb.ApiKey) {
+	for _, apiKey := range apiKeys {
+		pbApiKeys = append(pbApiKeys, convertApiKey(apiKey))
+	}
+	return
+}
+
+func convertWebhookDelivery(delivery db.WebhookDelivery) *pb.WebhookDelivery {
+	pbDelivery := &pb.WebhookDelivery{
+		Id:             delivery.ID,
+		SubscriptionId: delivery.SubscriptionID,
+		EventId:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseCode:   delivery.ResponseCode.Int32,
+		LastError:      delivery.LastError.String,
+		CreatedAt:      timestamppb.New(delivery.CreatedAt),
+	}
+	if delivery.DeliveredAt.Valid {
+		pbDelivery.DeliveredAt = timestamppb.New(delivery.DeliveredAt.Time)
+	}
+	return pbDelivery
+}
+
+func convertWebhookDeliveries(deliveries []db.WebhookDelivery) (pbDeliveries []*pb.WebhookDelivery) {
+	for _, delivery := range deliveries {
+		pbDeliveries = append(pbDeliveries, convertWebhookDelivery(delivery))
+	}
+	return
+}
+
+func convertAccountEvent(event db.AccountEvent) *pb.WatchAccountResponse {
+	return &pb.WatchAccountResponse{
+		Account: &pb.Account{
+			Id:       event.AccountID,
+			Owner:    event.Owner,
+			Balance:  event.Balance,
+			Carrency: event.Currency,
+		},
+		Entry: &pb.Entry{
+			Id:        event.EntryID,
+			AccountId: event.AccountID,
+			Amount:    event.EntryAmount,
+			CreatedAt: timestamppb.New(event.CreatedAt),
+		},
+	}
+}
+
+func convertSession(session db.Session, currentSessionID uuid.UUID) *pb.Session {
+	return &pb.Session{
+		Id:        session.ID.String(),
+		Username:  session.Username,
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		IsBloked:  session.IsBloked,
+		IsCurrent: session.ID == currentSessionID,
+		ExpiredAt: timestamppb.New(session.ExpiredAt),
+		CreatedAt: timestamppb.New(session.CreatedAt),
+	}
+}
+
+func convertSessions(sessions []db.Session, currentSessionID uuid.UUID) (pbSessions []*pb.Session) {
+	for _, session := range sessions {
+		pbSessions = append(pbSessions, convertSession(session, currentSessionID))
+	}
+	return
+}
+
+func convertLoginEvents(events []db.LoginEvent) (pbEvents []*pb.LoginEvent) {
+	for _, event := range events {
+		pbEvents = append(pbEvents, &pb.LoginEvent{
+			Id:        event.ID,
+			Username:  event.Username,
+			ClientIp:  event.ClientIp,
+			UserAgent: event.UserAgent,
+			Success:   event.Success,
+			Reason:    event.Reason,
+			CreatedAt: timestamppb.New(event.CreatedAt),
+		})
+	}
+	return
+}
@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// emailRequestLimit emails of one kind allowed for the address and for
+// the client ip in the window, zero max disables the limit
+type emailRequestLimit struct {
+	kind        string
+	maxEmails   int64
+	ipMaxEmails int64
+	window      time.Duration
+}
+
+func (srv *Server) loginLinkLimit() emailRequestLimit {
+	return emailRequestLimit{
+		kind:        "login",
+		maxEmails:   srv.config.LoginLinkMaxEmails,
+		ipMaxEmails: srv.config.LoginLinkIpMaxEmails,
+		window:      srv.config.LoginLinkWindow,
+	}
+}
+
+func (srv *Server) passwordResetLimit() emailRequestLimit {
+	return emailRequestLimit{
+		kind:        "reset",
+		maxEmails:   srv.config.PasswordResetMaxEmails,
+		ipMaxEmails: srv.config.PasswordResetIpMaxEmails,
+		window:      srv.config.PasswordResetWindow,
+	}
+}
+
+// emailRequestCounter one counter of the login_link_requests table, the keys
+// are prefixed with the kind, so every kind of email is counted on its own
+type emailRequestCounter struct {
+	key     string
+	maxSent int64
+}
+
+// limitEmailRequests count the request for the email and for the client ip, both
+// are counted before the email is looked up, so the limit does not tell which
+// emails are registered
+func (srv *Server) limitEmailRequests(ctx context.Context, limit emailRequestLimit, email string) error {
+	windowStart := time.Now().Add(-limit.window)
+	counters := []emailRequestCounter{
+		{key: limit.kind + ":email:" + strings.ToLower(email), maxSent: limit.maxEmails},
+	}
+	if clientIP := srv.extractMetadata(ctx).ClientIP; clientIP != "" {
+		counters = append(counters, emailRequestCounter{key: limit.kind + ":ip:" + clientIP, maxSent: limit.ipMaxEmails})
+	}
+
+	for _, counter := range counters {
+		if counter.maxSent <= 0 {
+			continue
+		}
+		sent, err := srv.store.AddLoginLinkRequest(ctx, db.AddLoginLinkRequestParams{
+			Key:         counter.key,
+			WindowStart: windowStart,
+		})
+		if err != nil {
+			return status.Errorf(codes.Internal, "cannot count email requests: %s", err)
+		}
+		if sent > counter.maxSent {
+			return status.Errorf(codes.ResourceExhausted, "too many emails requested, try again later")
+		}
+	}
+	return nil
+}
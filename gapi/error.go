@@ -29,6 +29,14 @@ func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) er
 	return statusDetails.Err()
 }
 
+// passwordViolations report every broken rule of the password policy
+func passwordViolations(field string, errs []error) (violations []*errdetails.BadRequest_FieldViolation) {
+	for _, err := range errs {
+		violations = append(violations, fieldViolation(field, err))
+	}
+	return
+}
+
 func unauthenticatedError(err error) error {
 	return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
 }
@@ -39,3 +47,15 @@ func cannotGetAccountError(err error) error {
 	}
 	return fmt.Errorf("cannot get Account: %s", err)
 }
+
+func reviewTransferError(err error) error {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return status.Errorf(codes.NotFound, "transfer not found: %s", err)
+	case errors.Is(err, db.ErrSelfReview):
+		return status.Errorf(codes.PermissionDenied, "%s", err)
+	case errors.Is(err, db.ErrTransferNotPending):
+		return status.Errorf(codes.FailedPrecondition, "%s", err)
+	}
+	return status.Errorf(codes.Internal, "cannot review Transfer: %s", err)
+}
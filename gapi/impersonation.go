@@ -0,0 +1,40 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/token"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recordImpersonationEvent add the call made with the impersonated token to the audit trail
+func (server *Server) recordImpersonationEvent(ctx context.Context, payload *token.Payload, method string, allowed bool, reason string) error {
+	_, err := server.store.CreateImpersonationEvent(ctx, db.CreateImpersonationEventParams{
+		Actor:   payload.Actor,
+		Subject: payload.Username,
+		TokenID: uuid.UUID(payload.ID),
+		Method:  method,
+		Allowed: allowed,
+		Reason:  reason,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot record impersonation event")
+	}
+	return nil
+}
+
+// auditImpersonation record every call made under impersonation, including
+// the rejected ones. The call is not served when it can not be recorded.
+func (server *Server) auditImpersonation(ctx context.Context, payload *token.Payload, method string, policy methodPolicy) error {
+	allowed := policy.impersonation
+	if err := server.recordImpersonationEvent(ctx, payload, method, allowed, ""); err != nil {
+		return err
+	}
+	if !allowed {
+		return status.Errorf(codes.PermissionDenied, "%s can not be called with impersonated token", method)
+	}
+	return nil
+}
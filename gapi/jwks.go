@@ -0,0 +1,31 @@
+package gapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dubass83/simplebank/token"
+)
+
+// JWKSPath where the public keys of the token maker are published
+const JWKSPath = "/.well-known/jwks.json"
+
+// JWKSHandler serve public keys which verify tokens of the maker, it responds
+// with 404 when tokens are signed with the symmetric secret
+func JWKSHandler(tokenMaker token.Maker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		provider, ok := tokenMaker.(token.KeySetProvider)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		json.NewEncoder(w).Encode(provider.JWKS())
+	})
+}
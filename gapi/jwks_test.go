@@ -0,0 +1,70 @@
+package gapi
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dubass83/simplebank/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ed25519Maker, err := token.NewEd25519Maker(key)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		method        string
+		tokenMaker    token.Maker
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			method:     http.MethodGet,
+			tokenMaker: ed25519Maker,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+				var set token.JWKSet
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &set))
+				require.Equal(t, ed25519Maker.(token.KeySetProvider).JWKS(), set)
+			},
+		}, {
+			name:   "SymmetricKey",
+			method: http.MethodGet,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:       "MethodNotAllowed",
+			method:     http.MethodPost,
+			tokenMaker: ed25519Maker,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			tokenMaker := tc.tokenMaker
+			if tokenMaker == nil {
+				tokenMaker = NewTestServer(t, nil, nil).tokenMaker
+			}
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tc.method, JWKSPath, nil)
+			JWKSHandler(tokenMaker).ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/worker"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Reasons of the login attempts recorded in the login_events table
+const (
+	loginReasonSuccess       = "success"
+	loginReasonUnknownUser   = "unknown_user"
+	loginReasonWrongPassword = "wrong_password"
+	loginReasonLocked        = "locked"
+	loginReasonThrottled     = "throttled"
+	loginReasonTotpRequired  = "totp_required"
+	loginReasonInvalidTotp   = "invalid_totp"
+)
+
+// recordLoginEvent add the login attempt to the audit trail of the username
+func (srv *Server) recordLoginEvent(ctx context.Context, username string, mtdt *Metadata, success bool, reason string) error {
+	_, err := srv.store.CreateLoginEvent(ctx, db.CreateLoginEventParams{
+		Username:  username,
+		ClientIp:  mtdt.ClientIP,
+		UserAgent: mtdt.UserAgent,
+		Success:   success,
+		Reason:    reason,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot record login event")
+	}
+	return nil
+}
+
+// recordLoginSuccess record the successful login and alert the user when
+// it comes from the user agent and IP which were not seen before. Behind the
+// gateway both come from the HTTP client, see extractMetadata.
+// The very first login of the user is not alerted.
+func (srv *Server) recordLoginSuccess(ctx context.Context, username string, mtdt *Metadata) error {
+	stats, err := srv.store.GetLoginDeviceStats(ctx, db.GetLoginDeviceStatsParams{
+		UserAgent: mtdt.UserAgent,
+		ClientIp:  mtdt.ClientIP,
+		Username:  username,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot get login devices")
+	}
+
+	err = srv.recordLoginEvent(ctx, username, mtdt, true, loginReasonSuccess)
+	if err != nil {
+		return err
+	}
+
+	if stats.Logins == 0 || stats.DeviceLogins > 0 {
+		return nil
+	}
+	err = srv.taskDestributor.DestributeTaskSendSecurityAlertEmail(
+		ctx,
+		&worker.PayloadSendSecurityAlertEmail{
+			Username:  username,
+			Alert:     worker.AlertNewDevice,
+			UserAgent: mtdt.UserAgent,
+			ClientIp:  mtdt.ClientIP,
+		},
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueCritical),
+	)
+	if err != nil {
+		log.Error().Err(err).Str("username", username).Msg("cannot enqueue new device alert")
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/worker"
+	mockwk "github.com/dubass83/simplebank/worker/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestRecordLoginSuccess(t *testing.T) {
+	user, _ := randomUser()
+	mtdt := &Metadata{UserAgent: "curl/8.0", ClientIP: "10.0.0.1"}
+
+	testCases := []struct {
+		name   string
+		stats  db.GetLoginDeviceStatsRow
+		alerts int
+	}{
+		{
+			name:   "FirstLogin",
+			stats:  db.GetLoginDeviceStatsRow{},
+			alerts: 0,
+		}, {
+			name:   "KnownDevice",
+			stats:  db.GetLoginDeviceStatsRow{Logins: 3, DeviceLogins: 1},
+			alerts: 0,
+		}, {
+			name:   "NewDevice",
+			stats:  db.GetLoginDeviceStatsRow{Logins: 3},
+			alerts: 1,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			taskDistributor := mockwk.NewMockTaskDistributor(ctrl)
+
+			store.EXPECT().
+				GetLoginDeviceStats(gomock.Any(), gomock.Eq(db.GetLoginDeviceStatsParams{
+					UserAgent: mtdt.UserAgent,
+					ClientIp:  mtdt.ClientIP,
+					Username:  user.Username,
+				})).
+				Times(1).
+				Return(tc.stats, nil)
+			store.EXPECT().
+				CreateLoginEvent(gomock.Any(), gomock.Eq(db.CreateLoginEventParams{
+					Username:  user.Username,
+					ClientIp:  mtdt.ClientIP,
+					UserAgent: mtdt.UserAgent,
+					Success:   true,
+					Reason:    loginReasonSuccess,
+				})).
+				Times(1)
+
+			payload := &worker.PayloadSendSecurityAlertEmail{
+				Username:  user.Username,
+				Alert:     worker.AlertNewDevice,
+				UserAgent: mtdt.UserAgent,
+				ClientIp:  mtdt.ClientIP,
+			}
+			taskDistributor.EXPECT().
+				DestributeTaskSendSecurityAlertEmail(gomock.Any(), gomock.Eq(payload), gomock.Any()).
+				Times(tc.alerts)
+
+			server := NewTestServer(t, store, taskDistributor)
+			err := server.recordLoginSuccess(context.Background(), user.Username, mtdt)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRecordLoginSuccessGateway(t *testing.T) {
+	user, _ := randomUser()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	taskDistributor := mockwk.NewMockTaskDistributor(ctrl)
+
+	// the device is the browser and the address the gateway saw,
+	// not the agent of the gateway and not the hops sent by the client
+	store.EXPECT().
+		GetLoginDeviceStats(gomock.Any(), gomock.Eq(db.GetLoginDeviceStatsParams{
+			UserAgent: "Mozilla/5.0",
+			ClientIp:  "203.0.113.7",
+			Username:  user.Username,
+		})).
+		Times(1).
+		Return(db.GetLoginDeviceStatsRow{Logins: 3}, nil)
+	store.EXPECT().
+		CreateLoginEvent(gomock.Any(), gomock.Any()).
+		Times(1)
+	payload := &worker.PayloadSendSecurityAlertEmail{
+		Username:  user.Username,
+		Alert:     worker.AlertNewDevice,
+		UserAgent: "Mozilla/5.0",
+		ClientIp:  "203.0.113.7",
+	}
+	taskDistributor.EXPECT().
+		DestributeTaskSendSecurityAlertEmail(gomock.Any(), gomock.Eq(payload), gomock.Any()).
+		Times(1)
+
+	md := metadata.MD{
+		GatewayUserAgentHeader: []string{"Mozilla/5.0"},
+		UserAgentHeader:        []string{"grpc-go/1.61.0"},
+		GatewayClientIP:        []string{"198.51.100.1, 203.0.113.7"},
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40000}})
+
+	server := NewTestServer(t, store, taskDistributor)
+	err := server.recordLoginSuccess(ctx, user.Username, server.extractMetadata(ctx))
+	require.NoError(t, err)
+}
@@ -0,0 +1,121 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// loginMaxDelay upper bound of the progressive delay before password check
+const loginMaxDelay = 5 * time.Second
+
+// invalidCredentialsError is returned for every failed login, so the caller
+// can not tell unknown usernames, wrong passwords and locked users apart
+func invalidCredentialsError() error {
+	return status.Errorf(codes.Unauthenticated, "invalid username or password")
+}
+
+// throttleLogin reject clients which failed too many logins and hold the
+// request for the delay growing with failed logins of the username.
+// Failed password and second factor checks are counted together, failures
+// of the client are counted by its host from extractMetadata without the port.
+// It returns the number of recent failed logins of the username.
+func (srv *Server) throttleLogin(ctx context.Context, username string, mtdt *Metadata) (int64, error) {
+	since := time.Now().Add(-srv.config.LoginAttemptWindow)
+
+	ipFailures, err := srv.store.CountFailedLoginsByClientIp(ctx, db.CountFailedLoginsByClientIpParams{
+		ClientIp: mtdt.ClientIP,
+		Since:    since,
+	})
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "cannot count failed logins")
+	}
+	if srv.config.LoginIpMaxAttempts > 0 && ipFailures >= srv.config.LoginIpMaxAttempts {
+		if err := srv.recordLoginEvent(ctx, username, mtdt, false, loginReasonThrottled); err != nil {
+			return 0, err
+		}
+		return 0, status.Errorf(codes.ResourceExhausted, "too many failed logins, try again later")
+	}
+
+	failures, err := srv.store.CountFailedLoginsByUsername(ctx, db.CountFailedLoginsByUsernameParams{
+		Username: username,
+		Since:    since,
+	})
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "cannot count failed logins")
+	}
+
+	select {
+	case <-time.After(srv.loginDelay(failures)):
+	case <-ctx.Done():
+		return 0, status.FromContextError(ctx.Err()).Err()
+	}
+	return failures, nil
+}
+
+// loginDelay double the base delay for every failed login of the username
+func (srv *Server) loginDelay(failures int64) time.Duration {
+	if failures <= 0 || srv.config.LoginBaseDelay <= 0 {
+		return 0
+	}
+	delay := srv.config.LoginBaseDelay
+	for i := int64(1); i < failures && delay < loginMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > loginMaxDelay {
+		return loginMaxDelay
+	}
+	return delay
+}
+
+// failLogin record the failed login and return the uniform error to the caller
+func (srv *Server) failLogin(ctx context.Context, username string, mtdt *Metadata, failures int64, reason string) error {
+	if err := srv.recordFailedLogin(ctx, username, mtdt, failures, reason); err != nil {
+		return err
+	}
+	return invalidCredentialsError()
+}
+
+// recordFailedLogin store the failed attempt and lock the existing user
+// after LoginMaxAttempts failures
+func (srv *Server) recordFailedLogin(ctx context.Context, username string, mtdt *Metadata, failures int64, reason string) error {
+	_, err := srv.store.CreateFailedLogin(ctx, db.CreateFailedLoginParams{
+		Username: username,
+		ClientIp: mtdt.ClientIP,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot record failed login")
+	}
+
+	if err := srv.recordLoginEvent(ctx, username, mtdt, false, reason); err != nil {
+		return err
+	}
+
+	userExists := reason != loginReasonUnknownUser
+	if !userExists || srv.config.LoginMaxAttempts <= 0 || failures+1 < srv.config.LoginMaxAttempts {
+		return nil
+	}
+
+	user, err := srv.store.LockUserTx(ctx, db.LockUserTxParams{
+		Username:    username,
+		LockedUntil: time.Now().Add(srv.config.LoginLockoutDuration),
+		UserAgent:   mtdt.UserAgent,
+		ClientIp:    mtdt.ClientIP,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot lock user")
+	}
+	log.Warn().Str("username", user.Username).Str("client_ip", mtdt.ClientIP).
+		Time("locked_until", user.LockedUntil.Time).Msg("user locked after failed logins")
+
+	return nil
+}
+
+// isUserLocked report whether logins of the user are temporarily blocked
+func isUserLocked(user db.User) bool {
+	return user.LockedUntil.Valid && time.Now().Before(user.LockedUntil.Time)
+}
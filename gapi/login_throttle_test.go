@@ -0,0 +1,79 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoginDelay(t *testing.T) {
+	server := NewTestServer(t, nil, nil)
+	server.config.LoginBaseDelay = 250 * time.Millisecond
+
+	require.Zero(t, server.loginDelay(0))
+	require.Equal(t, 250*time.Millisecond, server.loginDelay(1))
+	require.Equal(t, 500*time.Millisecond, server.loginDelay(2))
+	require.Equal(t, 2*time.Second, server.loginDelay(4))
+	require.Equal(t, loginMaxDelay, server.loginDelay(10))
+	require.Equal(t, loginMaxDelay, server.loginDelay(1000))
+
+	server.config.LoginBaseDelay = 0
+	require.Zero(t, server.loginDelay(10))
+}
+
+func TestThrottleLoginClientIPWithPorts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	clientIP := "203.0.113.7"
+
+	// the store counts failed logins recorded for the client ip
+	failed := map[string]int64{clientIP: testLoginIpMaxAttempts - 1}
+	store.EXPECT().
+		CountFailedLoginsByClientIp(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(ctx context.Context, arg db.CountFailedLoginsByClientIpParams) (int64, error) {
+			return failed[arg.ClientIp], nil
+		})
+	store.EXPECT().
+		CountFailedLoginsByUsername(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int64(0), nil)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.User{}, db.ErrRecordNotFound)
+	store.EXPECT().
+		CreateFailedLogin(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.CreateFailedLoginParams) (db.FailedLogin, error) {
+			failed[arg.ClientIp]++
+			return db.FailedLogin{Username: arg.Username, ClientIp: arg.ClientIp}, nil
+		})
+	store.EXPECT().
+		CreateLoginEvent(gomock.Any(), gomock.Any()).
+		Times(2).
+		Return(db.LoginEvent{}, nil)
+
+	server := NewTestServer(t, store, nil)
+	req := &pb.LoginUserRequest{Username: util.RandomOwner(), Password: util.RandomString(10)}
+	for i, code := range []codes.Code{codes.Unauthenticated, codes.ResourceExhausted} {
+		// every connection of the client comes from the new source port
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP(clientIP), Port: 40000 + i},
+		})
+		_, err := server.LoginUser(ctx, req)
+		require.Equal(t, code, status.Code(err))
+	}
+}
@@ -3,31 +3,72 @@ package gapi
 import (
 	"context"
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
 	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/fraud"
+	"github.com/dubass83/simplebank/refresh"
 	"github.com/dubass83/simplebank/token"
 	"github.com/dubass83/simplebank/util"
 	"github.com/dubass83/simplebank/worker"
+	gofrsuuid "github.com/gofrs/uuid/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
+const (
+	testTransferReviewAmount = 1000
+	testLoginMaxAttempts     = 5
+	testLoginIpMaxAttempts   = 20
+	testVerifyEmailResends   = 3
+	testLoginLinkMaxEmails   = 3
+	testLoginLinkIpMaxEmails = 20
+	testResetMaxEmails       = 3
+	testResetIpMaxEmails     = 20
+)
+
 func NewTestServer(t *testing.T, store db.Store, taskDistributor worker.TaskDistributor) *Server {
 	config := util.Config{
-		TokenString:   util.RandomString(32),
-		TokenDuration: time.Minute * 5,
+		TokenString:           util.RandomString(32),
+		TokenDuration:         time.Minute * 5,
+		RefreshTokenDuration:  time.Hour,
+		TotpChallengeDuration: time.Minute,
+		TransferReviewAmount:  testTransferReviewAmount,
+		LoginMaxAttempts:      testLoginMaxAttempts,
+		LoginIpMaxAttempts:    testLoginIpMaxAttempts,
+		LoginAttemptWindow:    time.Minute * 15,
+		LoginLockoutDuration:  time.Minute * 15,
+		VerifyEmailMaxResends: testVerifyEmailResends,
+		VerifyEmailWindow:     time.Hour,
+		LoginLinkMaxEmails:    testLoginLinkMaxEmails,
+		LoginLinkIpMaxEmails:  testLoginLinkIpMaxEmails,
+		LoginLinkWindow:       time.Hour,
+		ImpersonationDuration: time.Minute * 15,
+
+		PasswordResetMaxEmails:   testResetMaxEmails,
+		PasswordResetIpMaxEmails: testResetIpMaxEmails,
+		PasswordResetWindow:      time.Hour,
 	}
-	server, err := NewServer(config, store, taskDistributor)
+	tokenMaker, err := token.NewMaker(config)
+	require.NoError(t, err)
+	server, err := NewServer(config, store, tokenMaker, taskDistributor)
 	require.NoError(t, err)
+	server.fraudEvaluator = fraudStub{action: fraud.ActionAllow}
+	server.revocation = revocationStub{}
+	// rotator captures the checker, so it is built again with the stub
+	server.refreshTokens = refresh.NewRotator(store, tokenMaker, server.revocation, config.RefreshTokenDuration)
+	server.permissions = permissionStub{}
 
 	return server
 }
 
-func BuildContext(t *testing.T, tokenMaker token.Maker, username string, role string, duration time.Duration) context.Context {
+func BuildContext(t *testing.T, tokenMaker token.Maker, username string, role string, duration time.Duration, opts ...token.PayloadOption) context.Context {
 	ctx := context.Background()
-	token, _, err := tokenMaker.CreateToken(username, role, duration)
+	token, _, err := tokenMaker.CreateToken(username, role, duration, opts...)
 	require.NoError(t, err)
 	barierToken := fmt.Sprintf("%s %s", authType, token)
 	md := metadata.MD{
@@ -37,3 +78,62 @@ func BuildContext(t *testing.T, tokenMaker token.Maker, username string, role st
 	}
 	return metadata.NewIncomingContext(ctx, md)
 }
+
+// BuildSessionContext build context with access token bound to the session
+func BuildSessionContext(t *testing.T, tokenMaker token.Maker, session db.Session, role string, duration time.Duration) context.Context {
+	return BuildContext(t, tokenMaker, session.Username, role, duration, token.WithSessionID(gofrsuuid.UUID(session.ID)))
+}
+
+// BuildApiKeyContext build context of the call with API key
+func BuildApiKeyContext(key string) context.Context {
+	md := metadata.MD{
+		authorizationHeader: []string{
+			fmt.Sprintf("ApiKey %s", key),
+		},
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+// callUnary call the handler behind AuthInterceptor like the gRPC server does
+func callUnary[Req any, Res any](ctx context.Context, server *Server, method string, req Req, handler func(context.Context, Req) (Res, error)) (Res, error) {
+	info := &grpc.UnaryServerInfo{Server: server, FullMethod: method}
+	res, err := server.AuthInterceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+		return handler(ctx, req.(Req))
+	})
+	if err != nil {
+		var zero Res
+		return zero, err
+	}
+	return res.(Res), nil
+}
+
+// fraudStub return the same decision for every transfer
+type fraudStub struct {
+	action fraud.Action
+}
+
+func (stub fraudStub) Evaluate(ctx context.Context, transfer fraud.Transfer) (fraud.Decision, error) {
+	return fraud.Decision{Action: stub.action}, nil
+}
+
+// revocationStub return the same result for every token without store lookups
+type revocationStub struct {
+	err error
+}
+
+func (stub revocationStub) Check(ctx context.Context, payload *token.Payload) error {
+	return stub.err
+}
+
+func (stub revocationStub) ForgetSession(sessionID uuid.UUID) {}
+
+func (stub revocationStub) ForgetUser(username string) {}
+
+// permissionStub grant every permission except denied to bankers and none to other roles
+type permissionStub struct {
+	denied []string
+}
+
+func (stub permissionStub) HasPermission(ctx context.Context, payload *token.Payload, permission string) (bool, error) {
+	return payload.Role == util.BankerRole && !slices.Contains(stub.denied, permission), nil
+}
@@ -2,6 +2,8 @@ package gapi
 
 import (
 	"context"
+	"net"
+	"strings"
 
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
@@ -20,22 +22,45 @@ type Metadata struct {
 
 func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 	mtdt := &Metadata{}
+	md, _ := metadata.FromIncomingContext(ctx)
 
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
+	// grpc-go sets its own user agent on the gateway connection,
+	// the agent of the HTTP client comes in the gateway header
+	if userAgent := md.Get(GatewayUserAgentHeader); len(userAgent) > 0 {
+		mtdt.UserAgent = userAgent[0]
+	} else if userAgent := md.Get(UserAgentHeader); len(userAgent) > 0 {
+		mtdt.UserAgent = userAgent[0]
+	}
 
-		if userAgent := md.Get(GatewayUserAgentHeader); len(userAgent) > 0 {
-			mtdt.UserAgent = userAgent[0]
-		}
-		if userAgent := md.Get(UserAgentHeader); len(userAgent) > 0 {
-			mtdt.UserAgent = userAgent[0]
-		}
-		if clientIP := md.Get(GatewayClientIP); len(clientIP) > 0 {
-			mtdt.ClientIP = clientIP[0]
-		}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return mtdt
 	}
-	if p, ok := peer.FromContext(ctx); ok {
-		mtdt.ClientIP = p.Addr.String()
+	mtdt.ClientIP = hostOnly(p.Addr.String())
+	// the gateway connects over loopback and appends the address of the
+	// HTTP client to the forwarded chain, earlier hops are sent by the client
+	// and the header of any other peer is not trusted
+	if isLoopback(p.Addr) {
+		if forwarded := md.Get(GatewayClientIP); len(forwarded) > 0 {
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			if lastHop := strings.TrimSpace(hops[len(hops)-1]); lastHop != "" {
+				mtdt.ClientIP = hostOnly(lastHop)
+			}
+		}
 	}
 
 	return mtdt
 }
+
+// hostOnly drop the port, which changes with every connection of the client
+func hostOnly(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+func isLoopback(addr net.Addr) bool {
+	ip := net.ParseIP(hostOnly(addr.String()))
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,98 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestExtractMetadataClientIP(t *testing.T) {
+	forwarded := metadata.MD{GatewayClientIP: []string{"203.0.113.7"}}
+
+	testCases := []struct {
+		name     string
+		md       metadata.MD
+		peerAddr net.Addr
+		clientIP string
+	}{
+		{
+			name:     "GatewayOverLoopback",
+			md:       forwarded,
+			peerAddr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40000},
+			clientIP: "203.0.113.7",
+		},
+		{
+			name:     "HeaderFromRemotePeer",
+			md:       forwarded,
+			peerAddr: &net.TCPAddr{IP: net.IPv4(198, 51, 100, 1), Port: 40000},
+			clientIP: "198.51.100.1",
+		},
+		{
+			name:     "LoopbackWithoutHeader",
+			md:       metadata.MD{},
+			peerAddr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40000},
+			clientIP: "127.0.0.1",
+		},
+		{
+			name:     "SpoofedForwardedChain",
+			md:       metadata.MD{GatewayClientIP: []string{"10.0.0.1, 203.0.113.7"}},
+			peerAddr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40000},
+			clientIP: "203.0.113.7",
+		},
+		{
+			name:     "IPv6Peer",
+			md:       metadata.MD{},
+			peerAddr: &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 40000},
+			clientIP: "2001:db8::1",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tc.md)
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: tc.peerAddr})
+
+			server := &Server{}
+			require.Equal(t, tc.clientIP, server.extractMetadata(ctx).ClientIP)
+		})
+	}
+}
+
+func TestExtractMetadataUserAgent(t *testing.T) {
+	testCases := []struct {
+		name      string
+		md        metadata.MD
+		userAgent string
+	}{
+		{
+			name: "Gateway",
+			md: metadata.MD{
+				GatewayUserAgentHeader: []string{"Mozilla/5.0"},
+				UserAgentHeader:        []string{"grpc-go/1.61.0"},
+			},
+			userAgent: "Mozilla/5.0",
+		},
+		{
+			name:      "DirectGRPC",
+			md:        metadata.MD{UserAgentHeader: []string{"grpcurl/1.8.9 grpc-go/1.61.0"}},
+			userAgent: "grpcurl/1.8.9 grpc-go/1.61.0",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tc.md)
+
+			server := &Server{}
+			require.Equal(t, tc.userAgent, server.extractMetadata(ctx).UserAgent)
+		})
+	}
+}
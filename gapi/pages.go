@@ -0,0 +1,123 @@
+package gapi
+
+import (
+	"html/template"
+	"net/http"
+)
+
+const (
+	// ResetPasswordPagePath where the link from the password reset email points
+	ResetPasswordPagePath = "/reset_password"
+	// LoginLinkPagePath where the link from the login link email points
+	LoginLinkPagePath = "/login_link"
+)
+
+// resetPasswordPage ask for the new password and POST it with the code from
+// the link to the ResetPassword endpoint, opening the link changes nothing
+var resetPasswordPage = template.Must(template.New("reset_password").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Reset your Simple Bank password</title></head>
+<body>
+<form id="reset">
+  <input type="hidden" name="id" value="{{.ID}}">
+  <input type="hidden" name="secret_code" value="{{.SecretCode}}">
+  <label>New password <input type="password" name="new_password" required></label>
+  <button type="submit">Reset password</button>
+</form>
+<p id="result"></p>
+<script>
+document.getElementById("reset").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const form = event.target.elements;
+  const res = await fetch("/v1/reset_password", {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify({
+      id: form["id"].value,
+      secret_code: form["secret_code"].value,
+      new_password: form["new_password"].value,
+    }),
+  });
+  document.getElementById("result").textContent = res.ok ?
+    "Password is changed, sign in with the new password." :
+    "Password can not be changed, request a new link.";
+});
+</script>
+</body>
+</html>
+`))
+
+// loginLinkPage ask to confirm the sign in and POST the code from the link
+// to the RedeemLoginLink endpoint, mail scanners opening the link do not use it
+var loginLinkPage = template.Must(template.New("login_link").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Sign in to Simple Bank</title></head>
+<body>
+<form id="login">
+  <input type="hidden" name="id" value="{{.ID}}">
+  <input type="hidden" name="secret_code" value="{{.SecretCode}}">
+  <button type="submit">Sign in</button>
+</form>
+<p id="result"></p>
+<script>
+document.getElementById("login").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const form = event.target.elements;
+  const res = await fetch("/v1/redeem_login_link", {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify({
+      id: form["id"].value,
+      secret_code: form["secret_code"].value,
+    }),
+  });
+  if (!res.ok) {
+    document.getElementById("result").textContent = "Sign in failed, request a new link.";
+    return;
+  }
+  const login = await res.json();
+  sessionStorage.setItem("simplebank_login", JSON.stringify(login));
+  document.getElementById("result").textContent = login.totp_required ?
+    "Enter the code from your authenticator app to finish sign in." :
+    "You are signed in.";
+});
+</script>
+</body>
+</html>
+`))
+
+type linkPageData struct {
+	ID         string
+	SecretCode string
+}
+
+// ResetPasswordPageHandler serve the page opened from the password reset email
+func ResetPasswordPageHandler() http.Handler {
+	return linkPageHandler(resetPasswordPage)
+}
+
+// LoginLinkPageHandler serve the page opened from the login link email
+func LoginLinkPageHandler() http.Handler {
+	return linkPageHandler(loginLinkPage)
+}
+
+// linkPageHandler render the page for the link sent by email, the page only
+// reads the code from the query, the code is used by the POST it sends
+func linkPageHandler(page *template.Template) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		query := r.URL.Query()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		// the code in the url must not leak to other sites
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		page.Execute(w, linkPageData{
+			ID:         query.Get("id"),
+			SecretCode: query.Get("secret_code"),
+		})
+	})
+}
@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetPasswordPageHandler(t *testing.T) {
+	testCases := []struct {
+		name          string
+		method        string
+		target        string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			target: ResetPasswordPagePath + "?id=7&secret_code=abc",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "no-referrer", recorder.Header().Get("Referrer-Policy"))
+				require.Contains(t, recorder.Body.String(), `name="id" value="7"`)
+				require.Contains(t, recorder.Body.String(), `name="secret_code" value="abc"`)
+				require.Contains(t, recorder.Body.String(), `fetch("/v1/reset_password"`)
+			},
+		}, {
+			name:   "EscapedCode",
+			method: http.MethodGet,
+			target: ResetPasswordPagePath + `?id=7&secret_code=%22%3E%3Cscript%3E`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), `"><script>`)
+			},
+		}, {
+			name:   "MethodNotAllowed",
+			method: http.MethodPost,
+			target: ResetPasswordPagePath,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tc.method, tc.target, nil)
+			ResetPasswordPageHandler().ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLoginLinkPageHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, LoginLinkPagePath+"?id=7&secret_code=abc", nil)
+	LoginLinkPageHandler().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+	require.Contains(t, recorder.Body.String(), `name="id" value="7"`)
+	require.Contains(t, recorder.Body.String(), `name="secret_code" value="abc"`)
+	require.Contains(t, recorder.Body.String(), `fetch("/v1/redeem_login_link"`)
+
+	// opening the link never redeems it
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, LoginLinkPagePath, nil)
+	LoginLinkPageHandler().ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -0,0 +1,21 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dubass83/simplebank/token"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// requirePermission return PermissionDenied when the caller is not granted the permission
+func (server *Server) requirePermission(ctx context.Context, payload *token.Payload, permission string) error {
+	ok, err := server.permissions.HasPermission(ctx, payload, permission)
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot check permission: %s", err)
+	}
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "user: %s does not have permission: %s", payload.Username, permission)
+	}
+	return nil
+}
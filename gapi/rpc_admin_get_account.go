@@ -0,0 +1,39 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) AdminGetAccount(ctx context.Context, req *pb.AdminGetAccountRequest) (*pb.AdminGetAccountResponse, error) {
+	if err := val.ValidateAccountId(req.GetId()); err != nil {
+		violations := []*errdetails.BadRequest_FieldViolation{fieldViolation("id", err)}
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := srv.store.GetAccount(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get account: %s", err)
+	}
+
+	owner, err := srv.store.GetUser(ctx, account.Owner)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get account owner: %s", err)
+	}
+
+	rsp := &pb.AdminGetAccountResponse{
+		Account: convertAccount(account),
+		Owner:   convertUser(owner),
+	}
+	return rsp, nil
+}
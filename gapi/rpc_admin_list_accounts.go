@@ -0,0 +1,77 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) AdminListAccounts(ctx context.Context, req *pb.AdminListAccountsRequest) (*pb.AdminListAccountsResponse, error) {
+	if violations := validateAdminListAccountsRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	filter := db.CountAccountsParams{
+		Owner: pgtype.Text{
+			String: req.GetOwner(),
+			Valid:  req.GetOwner() != "",
+		},
+		Carrency: pgtype.Text{
+			String: req.GetCarrency(),
+			Valid:  req.GetCarrency() != "",
+		},
+	}
+
+	// one more row is fetched to know if there is the next page
+	accounts, err := srv.store.SearchAccounts(ctx, db.SearchAccountsParams{
+		Owner:    filter.Owner,
+		Carrency: filter.Carrency,
+		AfterID:  req.GetCursor(),
+		Limit:    req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot search accounts: %s", err)
+	}
+
+	total, err := srv.store.CountAccounts(ctx, filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot count accounts: %s", err)
+	}
+
+	rsp := &pb.AdminListAccountsResponse{
+		TotalCount: total,
+	}
+	if len(accounts) > int(req.GetPageSize()) {
+		accounts = accounts[:req.GetPageSize()]
+		rsp.NextCursor = accounts[len(accounts)-1].ID
+	}
+	rsp.Accounts = convertAccounts(accounts)
+	return rsp, nil
+}
+
+func validateAdminListAccountsRequest(req *pb.AdminListAccountsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetOwner() != "" {
+		if err := val.ValidateUsername(req.GetOwner()); err != nil {
+			violations = append(violations, fieldViolation("owner", err))
+		}
+	}
+	if req.GetCarrency() != "" {
+		if err := val.ValidateAccountCurrency(req.GetCarrency()); err != nil {
+			violations = append(violations, fieldViolation("carrency", err))
+		}
+	}
+	if req.GetCursor() < 0 {
+		violations = append(violations, fieldViolation("cursor", fmt.Errorf("must not be negative")))
+	}
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	return
+}
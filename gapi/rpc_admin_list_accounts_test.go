@@ -0,0 +1,177 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminListAccountsGAPI(t *testing.T) {
+	user, _ := randomUser()
+	banker, _ := randomUser()
+	banker.Role = util.BankerRole
+
+	n := 6
+	accounts := make([]db.Account, n)
+	for i := range accounts {
+		accounts[i] = db.Account{
+			ID:        int64(10 + i),
+			Owner:     user.Username,
+			Balance:   util.RandomMoney(),
+			Carrency:  util.USD,
+			CreatedAt: time.Now(),
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.AdminListAccountsRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.AdminListAccountsResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.AdminListAccountsRequest{Owner: user.Username, Carrency: util.USD, Cursor: 9, PageSize: int32(n - 1)},
+			buildStubs: func(store *mockdb.MockStore) {
+				filter := db.CountAccountsParams{
+					Owner:    pgtype.Text{String: user.Username, Valid: true},
+					Carrency: pgtype.Text{String: util.USD, Valid: true},
+				}
+				store.EXPECT().
+					SearchAccounts(gomock.Any(), gomock.Eq(db.SearchAccountsParams{
+						Owner:    filter.Owner,
+						Carrency: filter.Carrency,
+						AfterID:  9,
+						Limit:    int32(n),
+					})).
+					Times(1).
+					Return(accounts, nil)
+				store.EXPECT().
+					CountAccounts(gomock.Any(), gomock.Eq(filter)).
+					Times(1).
+					Return(int64(n), nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AdminListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetAccounts(), n-1)
+				require.Equal(t, accounts[n-2].ID, res.GetNextCursor())
+				require.Equal(t, int64(n), res.GetTotalCount())
+			},
+		}, {
+			name: "LastPage",
+			req:  &pb.AdminListAccountsRequest{PageSize: 10},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchAccounts(gomock.Any(), gomock.Eq(db.SearchAccountsParams{Limit: 11})).
+					Times(1).
+					Return(accounts, nil)
+				store.EXPECT().
+					CountAccounts(gomock.Any(), gomock.Eq(db.CountAccountsParams{})).
+					Times(1).
+					Return(int64(n), nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AdminListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetAccounts(), n)
+				require.Zero(t, res.GetNextCursor())
+			},
+		}, {
+			name: "NotBanker",
+			req:  &pb.AdminListAccountsRequest{PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchAccounts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AdminListAccountsResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		}, {
+			name: "InvalidCurrency",
+			req:  &pb.AdminListAccountsRequest{Carrency: "XYZ", PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchAccounts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AdminListAccountsResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		}, {
+			name: "NegativeCursor",
+			req:  &pb.AdminListAccountsRequest{Cursor: -1, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchAccounts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AdminListAccountsResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		}, {
+			name: "InternalError",
+			req:  &pb.AdminListAccountsRequest{PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchAccounts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(accounts, nil)
+				store.EXPECT().
+					CountAccounts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AdminListAccountsResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_AdminListAccounts_FullMethodName, tc.req, server.AdminListAccounts)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (srv *Server) ApproveTransfer(ctx context.Context, req *pb.ApproveTransferRequest) (*pb.ApproveTransferResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateAccountId(req.GetId()); err != nil {
+		violations := []*errdetails.BadRequest_FieldViolation{fieldViolation("id", err)}
+		return nil, invalidArgumentError(violations)
+	}
+
+	result, err := srv.store.ReviewTransferTx(ctx, db.ReviewTransferTxParams{
+		TransferID: req.GetId(),
+		ReviewedBy: payload.Username,
+		Approved:   true,
+	})
+	if err != nil {
+		return nil, reviewTransferError(err)
+	}
+
+	rsp := &pb.ApproveTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+	}
+	return rsp, nil
+}
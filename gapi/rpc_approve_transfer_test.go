@@ -0,0 +1,210 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestApproveTransferGAPI(t *testing.T) {
+	user, _ := randomUser()
+	banker, _ := randomUser()
+	banker.Role = util.BankerRole
+	transfer := randomPendingTransfer(user.Username)
+
+	posted := transfer
+	posted.Status = db.TransferStatusPosted
+	posted.ReviewedBy = pgtype.Text{String: banker.Username, Valid: true}
+	posted.ReviewedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ApproveTransferRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ApproveTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ApproveTransferRequest{Id: transfer.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ReviewTransferTxParams{
+					TransferID: transfer.ID,
+					ReviewedBy: banker.Username,
+					Approved:   true,
+				}
+				store.EXPECT().
+					ReviewTransferTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ReviewTransferTxResult{
+						TransferTxResult: db.TransferTxResult{Transfer: posted},
+					}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ApproveTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.TransferStatusPosted, res.GetTransfer().GetStatus())
+				require.Equal(t, banker.Username, res.GetTransfer().GetReviewedBy())
+				require.Equal(t, user.Username, res.GetTransfer().GetInitiatedBy())
+			},
+		}, {
+			name: "NotBanker",
+			req:  &pb.ApproveTransferRequest{Id: transfer.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReviewTransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ApproveTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name: "SelfReview",
+			req:  &pb.ApproveTransferRequest{Id: transfer.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReviewTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReviewTransferTxResult{}, db.ErrSelfReview)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ApproveTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name: "NotPending",
+			req:  &pb.ApproveTransferRequest{Id: transfer.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReviewTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReviewTransferTxResult{}, db.ErrTransferNotPending)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ApproveTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		}, {
+			name: "NotFound",
+			req:  &pb.ApproveTransferRequest{Id: transfer.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReviewTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReviewTransferTxResult{}, db.ErrRecordNotFound)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ApproveTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		}, {
+			name: "InternalError",
+			req:  &pb.ApproveTransferRequest{Id: transfer.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReviewTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReviewTransferTxResult{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ApproveTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		}, {
+			name: "InvalidID",
+			req:  &pb.ApproveTransferRequest{Id: 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReviewTransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ApproveTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_ApproveTransfer_FullMethodName, tc.req, server.ApproveTransfer)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func randomPendingTransfer(username string) db.Transfer {
+	return db.Transfer{
+		ID: util.RandomInt(1, 1000),
+		FromAccountID: pgtype.Int8{
+			Int64: util.RandomInt(1, 1000),
+			Valid: true,
+		},
+		ToAccountID: pgtype.Int8{
+			Int64: util.RandomInt(1, 1000),
+			Valid: true,
+		},
+		Amount:    util.RandomMoney(),
+		CreatedAt: time.Now(),
+		Status:    db.TransferStatusPendingReview,
+		InitiatedBy: pgtype.Text{
+			String: username,
+			Valid:  true,
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) AssignRole(ctx context.Context, req *pb.AssignRoleRequest) (*pb.AssignRoleResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if violations := validateAssignRoleRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	if req.GetUsername() == payload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "can not assign role to yourself")
+	}
+
+	role, err := srv.store.GetRole(ctx, req.GetRole())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			violations := []*errdetails.BadRequest_FieldViolation{fieldViolation("role", err)}
+			return nil, invalidArgumentError(violations)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get role: %s", err)
+	}
+	// grants edited in the db by hand must match the permissions the code checks
+	if err := val.ValidatePermissions(role.Permissions); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "role %s is misconfigured: %s", role.Name, err)
+	}
+	// the caller can not grant more than the caller holds
+	for _, permission := range role.Permissions {
+		granted, err := srv.permissions.HasPermission(ctx, payload, permission)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot check permission: %s", err)
+		}
+		if !granted {
+			return nil, status.Errorf(codes.PermissionDenied, "role %s grants permission %s which the caller does not hold", role.Name, permission)
+		}
+	}
+
+	// permissions are read from the current role, so the change applies
+	// to tokens issued before it as well
+	user, err := srv.store.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		Role:     req.GetRole(),
+		Username: req.GetUsername(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "cannot assign role: %s", err)
+	}
+
+	rsp := &pb.AssignRoleResponse{
+		User: convertUser(user),
+	}
+	return rsp, nil
+}
+
+func validateAssignRoleRequest(req *pb.AssignRoleRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+	if err := val.ValidateRoleName(req.GetRole()); err != nil {
+		violations = append(violations, fieldViolation("role", err))
+	}
+	return
+}
@@ -0,0 +1,251 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAssignRoleGAPI(t *testing.T) {
+	user, _ := randomUser()
+	banker, _ := randomUser()
+	banker.Role = util.BankerRole
+	role := db.Role{
+		Name:        util.BankerRole,
+		Permissions: util.Permissions,
+		CreatedAt:   time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.AssignRoleRequest
+		denied        []string
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.AssignRoleResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.AssignRoleRequest{Username: user.Username, Role: role.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				updated := user
+				updated.Role = role.Name
+				store.EXPECT().
+					GetRole(gomock.Any(), gomock.Eq(role.Name)).
+					Times(1).
+					Return(role, nil)
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Eq(db.UpdateUserRoleParams{
+						Role:     role.Name,
+						Username: user.Username,
+					})).
+					Times(1).
+					Return(updated, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AssignRoleResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, user.Username, res.GetUser().GetUsername())
+			},
+		}, {
+			name: "NotPermitted",
+			req:  &pb.AssignRoleRequest{Username: user.Username, Role: role.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AssignRoleResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name: "UnknownRole",
+			req:  &pb.AssignRoleRequest{Username: user.Username, Role: "auditor"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetRole(gomock.Any(), gomock.Eq("auditor")).
+					Times(1).
+					Return(db.Role{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AssignRoleResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "UnsupportedPermission",
+			req:  &pb.AssignRoleRequest{Username: user.Username, Role: "auditor"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetRole(gomock.Any(), gomock.Eq("auditor")).
+					Times(1).
+					Return(db.Role{Name: "auditor", Permissions: []string{"accounts:delete:any"}}, nil)
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AssignRoleResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		}, {
+			name: "AssignToSelf",
+			req:  &pb.AssignRoleRequest{Username: banker.Username, Role: role.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetRole(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AssignRoleResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		}, {
+			name:   "PermissionNotHeld",
+			req:    &pb.AssignRoleRequest{Username: user.Username, Role: role.Name},
+			denied: []string{util.PermissionUsersImpersonate},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetRole(gomock.Any(), gomock.Eq(role.Name)).
+					Times(1).
+					Return(role, nil)
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AssignRoleResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		}, {
+			name: "UserNotFound",
+			req:  &pb.AssignRoleRequest{Username: user.Username, Role: role.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetRole(gomock.Any(), gomock.Eq(role.Name)).
+					Times(1).
+					Return(role, nil)
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AssignRoleResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		}, {
+			name: "InternalError",
+			req:  &pb.AssignRoleRequest{Username: user.Username, Role: role.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetRole(gomock.Any(), gomock.Eq(role.Name)).
+					Times(1).
+					Return(role, nil)
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AssignRoleResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		}, {
+			name: "InvalidRole",
+			req:  &pb.AssignRoleRequest{Username: user.Username, Role: "Banker!"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetRole(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.AssignRoleResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "NoAuthorization",
+			req:  &pb.AssignRoleRequest{Username: user.Username, Role: role.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.AssignRoleResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := NewTestServer(t, store, nil)
+			server.permissions = permissionStub{denied: tc.denied}
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_AssignRole_FullMethodName, tc.req, server.AssignRole)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
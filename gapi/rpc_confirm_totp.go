@@ -0,0 +1,69 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/totp"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// totpRecoveryCodes number of one-time codes issued when the second factor is enabled
+const totpRecoveryCodes = 10
+
+func (srv *Server) ConfirmTotp(ctx context.Context, req *pb.ConfirmTotpRequest) (*pb.ConfirmTotpResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateTotpCode(req.GetTotpCode()); err != nil {
+		violations := []*errdetails.BadRequest_FieldViolation{fieldViolation("totp_code", err)}
+		return nil, invalidArgumentError(violations)
+	}
+
+	user, err := srv.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get user: %s", err)
+	}
+	if user.TotpEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+	if !user.TotpSecret.Valid {
+		return nil, status.Errorf(codes.FailedPrecondition, "totp is not enrolled, call EnrollTotp first")
+	}
+
+	if !totp.Validate(req.GetTotpCode(), user.TotpSecret.String, time.Now()) {
+		violations := []*errdetails.BadRequest_FieldViolation{fieldViolation("totp_code", fmt.Errorf("code does not match"))}
+		return nil, invalidArgumentError(violations)
+	}
+
+	recoveryCodes, err := totp.GenerateRecoveryCodes(totpRecoveryCodes)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot generate recovery codes: %s", err)
+	}
+	hashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashes = append(hashes, totp.HashRecoveryCode(code))
+	}
+
+	user, err = srv.store.EnableTotpTx(ctx, db.EnableTotpTxParams{
+		Username:           user.Username,
+		RecoveryCodeHashes: hashes,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot enable totp: %s", err)
+	}
+
+	rsp := &pb.ConfirmTotpResponse{
+		User:          convertUser(user),
+		RecoveryCodes: recoveryCodes,
+	}
+	return rsp, nil
+}
@@ -0,0 +1,155 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/totp"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConfirmTotpGAPI(t *testing.T) {
+	user, _ := randomUser()
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	user.TotpSecret = pgtype.Text{String: secret, Valid: true}
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		req           *pb.ConfirmTotpRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.ConfirmTotpResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ConfirmTotpRequest{TotpCode: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					EnableTotpTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.EnableTotpTxParams) (db.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Len(t, arg.RecoveryCodeHashes, totpRecoveryCodes)
+						enabled := user
+						enabled.TotpEnabled = true
+						return enabled, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.ConfirmTotpResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetUser().GetTotpEnabled())
+				require.Len(t, res.GetRecoveryCodes(), totpRecoveryCodes)
+			},
+		}, {
+			name: "WrongCode",
+			req:  &pb.ConfirmTotpRequest{TotpCode: wrongTotpCode(code)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					EnableTotpTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ConfirmTotpResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "NotEnrolled",
+			req:  &pb.ConfirmTotpRequest{TotpCode: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				notEnrolled := user
+				notEnrolled.TotpSecret = pgtype.Text{}
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(notEnrolled, nil)
+				store.EXPECT().
+					EnableTotpTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ConfirmTotpResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		}, {
+			name: "InvalidCodeFormat",
+			req:  &pb.ConfirmTotpRequest{TotpCode: "12ab"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ConfirmTotpResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "InternalError",
+			req:  &pb.ConfirmTotpRequest{TotpCode: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					EnableTotpTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.ConfirmTotpResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			ctx := BuildContext(t, server.tokenMaker, user.Username, user.Role, time.Minute)
+			res, err := callUnary(ctx, server, pb.SimpleBank_ConfirmTotp_FullMethodName, tc.req, server.ConfirmTotp)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+// wrongTotpCode return well formed code which differs from the valid one
+func wrongTotpCode(code string) string {
+	last := (code[len(code)-1]-'0'+5)%10 + '0'
+	return code[:len(code)-1] + string(last)
+}
@@ -12,7 +12,7 @@ import (
 )
 
 func (srv *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
-	payload, err := srv.authorizeUser(ctx)
+	payload, err := authPayload(ctx)
 	if err != nil {
 		return nil, unauthenticatedError(err)
 	}
@@ -27,7 +27,7 @@ func (srv *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountReque
 		Balance:  0,
 	}
 
-	Account, err := srv.store.CreateAccount(ctx, arg)
+	result, err := srv.store.CreateAccountTx(ctx, db.CreateAccountTxParams{CreateAccountParams: arg})
 	if err != nil {
 		if db.ErrorCode(err) == db.UniqueViolation {
 			return nil, status.Errorf(
@@ -39,7 +39,7 @@ func (srv *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountReque
 	}
 
 	rsp := &pb.CreateAccountResponse{
-		Account: convertAccount(Account),
+		Account: convertAccount(result.Account),
 	}
 
 	return rsp, nil
@@ -42,14 +42,16 @@ func TestCreateAccountGAPI(t *testing.T) {
 					Carrency: currency,
 				}
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(arg)).
+					CreateAccountTx(gomock.Any(), gomock.Eq(db.CreateAccountTxParams{CreateAccountParams: arg})).
 					Times(1).
-					Return(db.Account{
-						ID:        1,
-						Owner:     user.Username,
-						Balance:   initBalance,
-						Carrency:  currency,
-						CreatedAt: time.Now(),
+					Return(db.CreateAccountTxResult{
+						Account: db.Account{
+							ID:        1,
+							Owner:     user.Username,
+							Balance:   initBalance,
+							Carrency:  currency,
+							CreatedAt: time.Now(),
+						},
 					}, nil)
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
@@ -76,9 +78,9 @@ func TestCreateAccountGAPI(t *testing.T) {
 					Carrency: currency,
 				}
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(arg)).
+					CreateAccountTx(gomock.Any(), gomock.Eq(db.CreateAccountTxParams{CreateAccountParams: arg})).
 					Times(1).
-					Return(db.Account{}, sql.ErrConnDone)
+					Return(db.CreateAccountTxResult{}, sql.ErrConnDone)
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
 				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
@@ -97,7 +99,7 @@ func TestCreateAccountGAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
+					CreateAccountTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
@@ -117,7 +119,7 @@ func TestCreateAccountGAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
+					CreateAccountTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
@@ -142,9 +144,9 @@ func TestCreateAccountGAPI(t *testing.T) {
 					Carrency: currency,
 				}
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(arg)).
+					CreateAccountTx(gomock.Any(), gomock.Eq(db.CreateAccountTxParams{CreateAccountParams: arg})).
 					Times(1).
-					Return(db.Account{}, db.ErrUniqueViolation)
+					Return(db.CreateAccountTxResult{}, db.ErrUniqueViolation)
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
 				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
@@ -173,7 +175,7 @@ func TestCreateAccountGAPI(t *testing.T) {
 			server := NewTestServer(t, store, nil)
 			// create context
 			ctx := tc.buildContext(t, server.tokenMaker)
-			res, err := server.CreateAccount(ctx, tc.req)
+			res, err := callUnary(ctx, server, pb.SimpleBank_CreateAccount_FullMethodName, tc.req, server.CreateAccount)
 			// compare results
 			tc.checkResponse(t, res, err)
 		})
@@ -0,0 +1,61 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dubass83/simplebank/apikey"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) CreateApiKey(ctx context.Context, req *pb.CreateApiKeyRequest) (*pb.CreateApiKeyResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if violations := validateCreateApiKeyRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	secret, secretHash, err := apikey.NewSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot generate api key secret: %s", err)
+	}
+
+	arg := db.CreateApiKeyParams{
+		Owner:      payload.Username,
+		Name:       req.GetName(),
+		SecretHash: secretHash,
+		Scopes:     req.GetScopes(),
+		ExpiredAt:  req.GetExpiredAt().AsTime(),
+	}
+
+	apiKey, err := srv.store.CreateApiKey(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create api key: %s", err)
+	}
+
+	rsp := &pb.CreateApiKeyResponse{
+		ApiKey: convertApiKey(apiKey),
+		Key:    apikey.Format(apiKey.ID, secret),
+	}
+	return rsp, nil
+}
+
+func validateCreateApiKeyRequest(req *pb.CreateApiKeyRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateApiKeyName(req.GetName()); err != nil {
+		violations = append(violations, fieldViolation("name", err))
+	}
+	if err := val.ValidateApiKeyScopes(req.GetScopes()); err != nil {
+		violations = append(violations, fieldViolation("scopes", err))
+	}
+	if err := val.ValidateApiKeyExpiredAt(req.GetExpiredAt().AsTime()); err != nil {
+		violations = append(violations, fieldViolation("expired_at", err))
+	}
+	return
+}
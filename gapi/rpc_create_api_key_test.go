@@ -0,0 +1,234 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/apikey"
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCreateApiKeyGAPI(t *testing.T) {
+	user, _ := randomUser()
+	apiKey := randomApiKey(user.Username)
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateApiKeyRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.CreateApiKeyResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.CreateApiKeyRequest{
+				Name:      apiKey.Name,
+				Scopes:    apiKey.Scopes,
+				ExpiredAt: timestamppb.New(apiKey.ExpiredAt),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateApiKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateApiKeyParams) (db.ApiKey, error) {
+						require.Equal(t, user.Username, arg.Owner)
+						require.Equal(t, apiKey.Name, arg.Name)
+						require.Equal(t, apiKey.Scopes, arg.Scopes)
+						require.WithinDuration(t, apiKey.ExpiredAt, arg.ExpiredAt, time.Second)
+						apiKey := apiKey
+						apiKey.SecretHash = arg.SecretHash
+						return apiKey, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateApiKeyResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Equal(t, apiKey.ID, res.GetApiKey().GetId())
+				require.Equal(t, apiKey.Scopes, res.GetApiKey().GetScopes())
+
+				id, secret, err := apikey.Parse(res.GetKey())
+				require.NoError(t, err)
+				require.Equal(t, apiKey.ID, id)
+				require.NotEmpty(t, secret)
+			},
+		}, {
+			name: "InternalError",
+			req: &pb.CreateApiKeyRequest{
+				Name:      apiKey.Name,
+				Scopes:    apiKey.Scopes,
+				ExpiredAt: timestamppb.New(apiKey.ExpiredAt),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateApiKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApiKey{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateApiKeyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		}, {
+			name: "InvalidScope",
+			req: &pb.CreateApiKeyRequest{
+				Name:      apiKey.Name,
+				Scopes:    []string{"sessions:revoke"},
+				ExpiredAt: timestamppb.New(apiKey.ExpiredAt),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateApiKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateApiKeyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "NoScopes",
+			req: &pb.CreateApiKeyRequest{
+				Name:      apiKey.Name,
+				ExpiredAt: timestamppb.New(apiKey.ExpiredAt),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateApiKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateApiKeyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "ExpiredInPast",
+			req: &pb.CreateApiKeyRequest{
+				Name:      apiKey.Name,
+				Scopes:    apiKey.Scopes,
+				ExpiredAt: timestamppb.New(time.Now().Add(-time.Hour)),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateApiKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateApiKeyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "NoAuthorization",
+			req: &pb.CreateApiKeyRequest{
+				Name:      apiKey.Name,
+				Scopes:    apiKey.Scopes,
+				ExpiredAt: timestamppb.New(apiKey.ExpiredAt),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateApiKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateApiKeyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "CalledWithApiKey",
+			req: &pb.CreateApiKeyRequest{
+				Name:      apiKey.Name,
+				Scopes:    apiKey.Scopes,
+				ExpiredAt: timestamppb.New(apiKey.ExpiredAt),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				callerKey := apiKey
+				callerKey.SecretHash = util.HashSecretCode("secret")
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(callerKey.ID)).
+					Times(1).
+					Return(callerKey, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateApiKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildApiKeyContext(apikey.Format(apiKey.ID, "secret"))
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateApiKeyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+				require.Contains(t, st.Message(), "not allowed")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_CreateApiKey_FullMethodName, tc.req, server.CreateApiKey)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func randomApiKey(owner string) db.ApiKey {
+	return db.ApiKey{
+		ID:         util.RandomInt(1, 1000),
+		Owner:      owner,
+		Name:       util.RandomString(6),
+		SecretHash: util.HashSecretCode(util.RandomString(32)),
+		Scopes:     []string{util.ScopeAccountsRead, util.ScopeTransfersWrite},
+		ExpiredAt:  time.Now().Add(30 * 24 * time.Hour),
+		CreatedAt:  time.Now(),
+	}
+}
@@ -5,15 +5,17 @@ import (
 	"fmt"
 
 	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/fraud"
 	"github.com/dubass83/simplebank/pb"
 	"github.com/dubass83/simplebank/val"
+	"github.com/google/uuid"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 func (srv *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferTxRequest) (*pb.CreateTransferTxResponse, error) {
-	payload, err := srv.authorizeUser(ctx)
+	payload, err := authPayload(ctx)
 	if err != nil {
 		return nil, unauthenticatedError(err)
 	}
@@ -46,10 +48,43 @@ func (srv *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferTxR
 		return nil, fmt.Errorf("user allowed to transfer money only from his account")
 	}
 
+	if err := srv.checkTransferEmailVerified(ctx, payload.Username, req.GetAmount()); err != nil {
+		return nil, err
+	}
+
+	if err := srv.checkTransferTotp(ctx, payload.Username, req); err != nil {
+		return nil, err
+	}
+
+	decision, err := srv.fraudEvaluator.Evaluate(ctx, fraud.Transfer{
+		Username:    payload.Username,
+		SessionID:   uuid.UUID(payload.SessionID),
+		FromAccount: fromAccount,
+		ToAccount:   toAccount,
+		Amount:      req.GetAmount(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot evaluate Transfer: %s", err)
+	}
+	if decision.Action == fraud.ActionBlock {
+		return nil, status.Errorf(codes.PermissionDenied, "transfer is blocked by fraud check")
+	}
+
 	arg := db.TransferTxParams{
-		FromAccountID: req.GetFromAccountId(),
-		ToAccountID:   req.GetToAccountId(),
-		Ammount:       req.Amount,
+		FromAccountID:   req.GetFromAccountId(),
+		ToAccountID:     req.GetToAccountId(),
+		Ammount:         req.Amount,
+		FraudDecisionID: decision.ID,
+		InitiatedBy:     payload.Username,
+	}
+
+	// flagged and large transfers wait for the banker review
+	if decision.Action == fraud.ActionReview || srv.transferNeedsReview(req.GetAmount()) {
+		transfer, err := srv.store.HoldTransferTx(ctx, arg)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot create Transfer: %s", err)
+		}
+		return &pb.CreateTransferTxResponse{Transfer: convertTransfer(transfer)}, nil
 	}
 
 	txResp, err := srv.store.TransferTx(ctx, arg)
@@ -62,6 +97,50 @@ func (srv *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferTxR
 	return rsp, nil
 }
 
+func (srv *Server) transferNeedsReview(amount int64) bool {
+	return srv.config.TransferReviewAmount > 0 && amount > srv.config.TransferReviewAmount
+}
+
+// checkTransferEmailVerified allow only small transfers until the user verify the email
+func (srv *Server) checkTransferEmailVerified(ctx context.Context, username string, amount int64) error {
+	if srv.config.UnverifiedMaxTransfer <= 0 || amount <= srv.config.UnverifiedMaxTransfer {
+		return nil
+	}
+
+	user, err := srv.store.GetUser(ctx, username)
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot get user: %s", err)
+	}
+	if !user.IsEmailVerified {
+		return status.Errorf(codes.PermissionDenied, "verify your email to transfer more than %d", srv.config.UnverifiedMaxTransfer)
+	}
+	return nil
+}
+
+// checkTransferTotp require fresh TOTP code for large transfers of users with two-factor authentication
+func (srv *Server) checkTransferTotp(ctx context.Context, username string, req *pb.CreateTransferTxRequest) error {
+	if srv.config.TotpTransferAmount <= 0 || req.GetAmount() <= srv.config.TotpTransferAmount {
+		return nil
+	}
+
+	user, err := srv.store.GetUser(ctx, username)
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot get user: %s", err)
+	}
+	if !user.TotpEnabled {
+		return nil
+	}
+
+	ok, err := srv.useTotpCode(ctx, user, req.GetTotpCode())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "fresh totp code is required for transfer above %d", srv.config.TotpTransferAmount)
+	}
+	return nil
+}
+
 func validateCreateTransferRequest(req *pb.CreateTransferTxRequest) (violations []*errdetails.BadRequest_FieldViolation) {
 	if err := val.ValidateAccountId(req.GetFromAccountId()); err != nil {
 		violations = append(violations, fieldViolation("FromAccountId", err))
@@ -9,8 +9,10 @@ import (
 
 	mockdb "github.com/dubass83/simplebank/db/mock"
 	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/fraud"
 	"github.com/dubass83/simplebank/pb"
 	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/totp"
 	"github.com/dubass83/simplebank/util"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -47,6 +49,7 @@ func TestCreateTransferTxGAPI(t *testing.T) {
 	testCases := []struct {
 		name          string
 		req           *pb.CreateTransferTxRequest
+		fraudAction   fraud.Action
 		buildStubs    func(store *mockdb.MockStore)
 		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
 		checkResponse func(t *testing.T, resp *pb.CreateTransferTxResponse, err error)
@@ -73,6 +76,7 @@ func TestCreateTransferTxGAPI(t *testing.T) {
 					FromAccountID: fromAccount.ID,
 					ToAccountID:   toAccount.ID,
 					Ammount:       10,
+					InitiatedBy:   user1.Username,
 				}
 				transfer := db.Transfer{
 					ID: 1,
@@ -123,6 +127,106 @@ func TestCreateTransferTxGAPI(t *testing.T) {
 				require.NoError(t, err)
 				require.NotNil(t, res)
 			},
+		}, {
+			name: "FraudBlocked",
+			req: &pb.CreateTransferTxRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        10,
+			},
+			fraudAction: fraud.ActionBlock,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+					Times(1).
+					Return(fromAccount, nil)
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+					Times(1).
+					Return(toAccount, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferTxResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name: "FraudReview",
+			req: &pb.CreateTransferTxRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        10,
+			},
+			fraudAction: fraud.ActionReview,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+					Times(1).
+					Return(fromAccount, nil)
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+					Times(1).
+					Return(toAccount, nil)
+				arg := db.TransferTxParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Ammount:       10,
+					InitiatedBy:   user1.Username,
+				}
+				store.EXPECT().
+					HoldTransferTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.Transfer{ID: 1, Amount: 10, Status: db.TransferStatusPendingReview}, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferTxResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.TransferStatusPendingReview, res.GetTransfer().GetStatus())
+				require.Nil(t, res.GetFromEntry())
+			},
+		}, {
+			name: "LargeAmountReview",
+			req: &pb.CreateTransferTxRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        testTransferReviewAmount + 1,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+					Times(1).
+					Return(fromAccount, nil)
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+					Times(1).
+					Return(toAccount, nil)
+				store.EXPECT().
+					HoldTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Transfer{ID: 1, Amount: testTransferReviewAmount + 1, Status: db.TransferStatusPendingReview}, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferTxResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.TransferStatusPendingReview, res.GetTransfer().GetStatus())
+			},
 		}, {
 			name: "Account1NotFound",
 			req: &pb.CreateTransferTxRequest{
@@ -203,6 +307,7 @@ func TestCreateTransferTxGAPI(t *testing.T) {
 					FromAccountID: fromAccount.ID,
 					ToAccountID:   toAccount.ID,
 					Ammount:       10,
+					InitiatedBy:   user1.Username,
 				}
 				store.EXPECT().
 					TransferTx(gomock.Any(), gomock.Eq(arg)).
@@ -326,12 +431,263 @@ func TestCreateTransferTxGAPI(t *testing.T) {
 			tc.buildStubs(store)
 			// start test server and run gRPC function
 			server := NewTestServer(t, store, nil)
+			if tc.fraudAction != "" {
+				server.fraudEvaluator = fraudStub{action: tc.fraudAction}
+			}
 			// create context
 			ctx := tc.buildContext(t, server.tokenMaker)
-			res, err := server.CreateTransfer(ctx, tc.req)
+			res, err := callUnary(ctx, server, pb.SimpleBank_CreateTransfer_FullMethodName, tc.req, server.CreateTransfer)
 			// compare results
 			tc.checkResponse(t, res, err)
 		})
 
 	}
 }
+
+func TestCreateTransferTotpGAPI(t *testing.T) {
+	user1, code := randomTotpUser(t)
+	user2, _ := randomUser()
+	fromAccount := db.Account{ID: 1, Owner: user1.Username, Balance: 1000, Carrency: util.UAH}
+	toAccount := db.Account{ID: 2, Owner: user2.Username, Balance: 1000, Carrency: util.UAH}
+	const totpTransferAmount = 50
+
+	testCases := []struct {
+		name          string
+		amount        int64
+		totpCode      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *pb.CreateTransferTxResponse, err error)
+	}{
+		{
+			name:     "OK",
+			amount:   100,
+			totpCode: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user1.Username)).
+					Times(1).
+					Return(user1, nil)
+				store.EXPECT().
+					UseTotpStep(gomock.Any(), gomock.Eq(db.UseTotpStepParams{
+						Username: user1.Username,
+						Step:     totp.Step(time.Now()),
+					})).
+					Times(1).
+					Return(int64(1), nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *pb.CreateTransferTxResponse, err error) {
+				require.NoError(t, err)
+			},
+		}, {
+			name:     "ReplayedTotpCode",
+			amount:   100,
+			totpCode: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user1.Username)).
+					Times(1).
+					Return(user1, nil)
+				store.EXPECT().
+					UseTotpStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *pb.CreateTransferTxResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		}, {
+			name:   "MissingTotpCode",
+			amount: 100,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user1.Username)).
+					Times(1).
+					Return(user1, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *pb.CreateTransferTxResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name:   "TotpDisabled",
+			amount: 100,
+			buildStubs: func(store *mockdb.MockStore) {
+				disabled := user1
+				disabled.TotpEnabled = false
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user1.Username)).
+					Times(1).
+					Return(disabled, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *pb.CreateTransferTxResponse, err error) {
+				require.NoError(t, err)
+			},
+		}, {
+			name:   "BelowThreshold",
+			amount: totpTransferAmount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *pb.CreateTransferTxResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+				AnyTimes().
+				Return(fromAccount, nil)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+				AnyTimes().
+				Return(toAccount, nil)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			server.config.TotpTransferAmount = totpTransferAmount
+
+			ctx := BuildContext(t, server.tokenMaker, user1.Username, user1.Role, time.Minute)
+			req := &pb.CreateTransferTxRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        tc.amount,
+				TotpCode:      tc.totpCode,
+			}
+			res, err := callUnary(ctx, server, pb.SimpleBank_CreateTransfer_FullMethodName, req, server.CreateTransfer)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestCreateTransferUnverifiedEmailGAPI(t *testing.T) {
+	user1, _ := randomUser()
+	user2, _ := randomUser()
+	fromAccount := db.Account{ID: 1, Owner: user1.Username, Balance: 1000, Carrency: util.UAH}
+	toAccount := db.Account{ID: 2, Owner: user2.Username, Balance: 1000, Carrency: util.UAH}
+	const unverifiedMaxTransfer = 50
+
+	testCases := []struct {
+		name          string
+		amount        int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *pb.CreateTransferTxResponse, err error)
+	}{
+		{
+			name:   "Verified",
+			amount: 100,
+			buildStubs: func(store *mockdb.MockStore) {
+				verified := user1
+				verified.IsEmailVerified = true
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user1.Username)).
+					Times(1).
+					Return(verified, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *pb.CreateTransferTxResponse, err error) {
+				require.NoError(t, err)
+			},
+		}, {
+			name:   "Unverified",
+			amount: 100,
+			buildStubs: func(store *mockdb.MockStore) {
+				unverified := user1
+				unverified.IsEmailVerified = false
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user1.Username)).
+					Times(1).
+					Return(unverified, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *pb.CreateTransferTxResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name:   "BelowThreshold",
+			amount: unverifiedMaxTransfer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *pb.CreateTransferTxResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).
+				AnyTimes().
+				Return(fromAccount, nil)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).
+				AnyTimes().
+				Return(toAccount, nil)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			server.config.UnverifiedMaxTransfer = unverifiedMaxTransfer
+
+			ctx := BuildContext(t, server.tokenMaker, user1.Username, user1.Role, time.Minute)
+			req := &pb.CreateTransferTxRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        tc.amount,
+			}
+			res, err := callUnary(ctx, server, pb.SimpleBank_CreateTransfer_FullMethodName, req, server.CreateTransfer)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
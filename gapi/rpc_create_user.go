@@ -2,21 +2,18 @@ package gapi
 
 import (
 	"context"
-	"time"
 
 	db "github.com/dubass83/simplebank/db/sqlc"
 	"github.com/dubass83/simplebank/pb"
 	"github.com/dubass83/simplebank/util"
 	"github.com/dubass83/simplebank/val"
-	"github.com/dubass83/simplebank/worker"
-	"github.com/hibiken/asynq"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 func (srv *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	if violation := validateCreateUserRequest(req); violation != nil {
+	if violation := validateCreateUserRequest(req, srv.passwordPolicy); violation != nil {
 		return nil, invalidArgumentError(violation)
 	}
 	hash, err := util.HashPassword(req.GetPassword())
@@ -31,18 +28,6 @@ func (srv *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*
 			FullName:       req.GetFullName(),
 			Email:          req.GetEmail(),
 		},
-		AfterFunc: func(user db.User) error {
-
-			payload := &worker.PayloadSendVerifyEmail{
-				Username: user.Username,
-			}
-			opts := []asynq.Option{
-				asynq.MaxRetry(10),
-				asynq.ProcessIn(10 * time.Second),
-				asynq.Queue(worker.QueueCritical),
-			}
-			return srv.taskDestributor.DestributeTaskSendVerifyEmail(ctx, payload, opts...)
-		},
 	}
 	// log.Info().Msg(">> start creating user")
 	// time.Sleep(time.Second * 10)
@@ -62,7 +47,7 @@ func (srv *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*
 	return rsp, nil
 }
 
-func validateCreateUserRequest(req *pb.CreateUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+func validateCreateUserRequest(req *pb.CreateUserRequest, policy val.PasswordPolicy) (violations []*errdetails.BadRequest_FieldViolation) {
 	if err := val.ValidateUsername(req.GetUsername()); err != nil {
 		violations = append(violations, fieldViolation("username", err))
 	}
@@ -72,8 +57,6 @@ func validateCreateUserRequest(req *pb.CreateUserRequest) (violations []*errdeta
 	if err := val.ValidateEmail(req.GetEmail()); err != nil {
 		violations = append(violations, fieldViolation("email", err))
 	}
-	if err := val.ValidatePassword(req.GetPassword()); err != nil {
-		violations = append(violations, fieldViolation("password", err))
-	}
+	violations = append(violations, passwordViolations("password", policy.Validate(req.GetPassword(), req.GetUsername(), req.GetEmail()))...)
 	return
 }
@@ -2,19 +2,25 @@ package gapi
 
 import (
 	"context"
+	"crypto/sha1"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	mockdb "github.com/dubass83/simplebank/db/mock"
 	db "github.com/dubass83/simplebank/db/sqlc"
 	"github.com/dubass83/simplebank/pb"
 	"github.com/dubass83/simplebank/util"
-	"github.com/dubass83/simplebank/worker"
+	"github.com/dubass83/simplebank/val"
 	mockwk "github.com/dubass83/simplebank/worker/mock"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -22,7 +28,6 @@ import (
 type eqCreateUserTxParamMatcher struct {
 	arg      db.CreateUserTxParams
 	password string
-	user     db.User
 }
 
 func (expected eqCreateUserTxParamMatcher) Matches(x any) bool {
@@ -36,19 +41,15 @@ func (expected eqCreateUserTxParamMatcher) Matches(x any) bool {
 	}
 	expected.arg.HashedPassword = actualArg.HashedPassword
 
-	if !reflect.DeepEqual(expected.arg.CreateUserParams, actualArg.CreateUserParams) {
-		return false
-	}
-	err = actualArg.AfterFunc(expected.user)
-	return err == nil
+	return reflect.DeepEqual(expected.arg.CreateUserParams, actualArg.CreateUserParams)
 }
 
 func (e eqCreateUserTxParamMatcher) String() string {
 	return fmt.Sprintf("is argument %v and password %s", e.arg, e.password)
 }
 
-func eqCreateUserTxParam(arg db.CreateUserTxParams, pass string, user db.User) gomock.Matcher {
-	return eqCreateUserTxParamMatcher{arg, pass, user}
+func eqCreateUserTxParam(arg db.CreateUserTxParams, pass string) gomock.Matcher {
+	return eqCreateUserTxParamMatcher{arg, pass}
 }
 
 func TestCreateUserGAPI(t *testing.T) {
@@ -75,21 +76,15 @@ func TestCreateUserGAPI(t *testing.T) {
 						FullName: user.FullName,
 						Email:    user.Email,
 					},
-					// AfterFunc: func(user db.User) error {
-					// },
 				}
 				store.EXPECT().
-					CreateUserTx(gomock.Any(), eqCreateUserTxParam(arg, password, user)).
+					CreateUserTx(gomock.Any(), eqCreateUserTxParam(arg, password)).
 					Times(1).
 					Return(db.CreateUserTxResult{User: user}, nil)
 
-				payload := &worker.PayloadSendVerifyEmail{
-					Username: user.Username,
-				}
 				taskDistrebutor.EXPECT().
-					DestributeTaskSendVerifyEmail(gomock.Any(), payload, gomock.Any()).
-					Times(1).
-					Return(nil)
+					DestributeTaskSendVerifyEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, res *pb.CreateUserResponse, err error) {
 				require.NoError(t, err)
@@ -242,3 +237,83 @@ func randomUser() (db.User, string) {
 
 	return user, password
 }
+
+func TestCreateUserPasswordPolicyGAPI(t *testing.T) {
+	user, _ := randomUser()
+	breachedPassword := "Tr0ub4dor&3"
+	sum := sha1.Sum([]byte(breachedPassword))
+	breachedFile := filepath.Join(t.TempDir(), "breached.txt")
+	err := os.WriteFile(breachedFile, []byte(strings.ToUpper(hex.EncodeToString(sum[:]))+":42\n"), 0600)
+	require.NoError(t, err)
+	breached, err := val.LoadBreachedList(breachedFile)
+	require.NoError(t, err)
+	t.Cleanup(func() { breached.Close() })
+
+	testCases := []struct {
+		name       string
+		password   string
+		violations int
+	}{
+		{
+			name:       "Strong",
+			password:   "correct-Horse-battery-5taple",
+			violations: 0,
+		},
+		{
+			name:       "ContainsUsername",
+			password:   "Kettle-" + user.Username + "-drum-42-zebra",
+			violations: 1,
+		},
+		{
+			// one class, too easy to guess and contains username
+			name:       "Weak",
+			password:   user.Username + "aaaa",
+			violations: 3,
+		},
+		{
+			name:       "Breached",
+			password:   breachedPassword,
+			violations: 1,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			if tc.violations == 0 {
+				store.EXPECT().
+					CreateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateUserTxResult{User: user}, nil)
+			}
+
+			server := NewTestServer(t, store, nil)
+			server.passwordPolicy = val.PasswordPolicy{MinClasses: 3, MinEntropy: 40, Breached: breached}
+			_, err := server.CreateUser(context.Background(), &pb.CreateUserRequest{
+				Username: user.Username,
+				Password: tc.password,
+				FullName: user.FullName,
+				Email:    user.Email,
+			})
+			if tc.violations == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			require.Equal(t, codes.InvalidArgument, st.Code())
+			require.Len(t, st.Details(), 1)
+			badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+			require.True(t, ok)
+			require.Len(t, badRequest.GetFieldViolations(), tc.violations)
+			for _, violation := range badRequest.GetFieldViolations() {
+				require.Equal(t, "password", violation.GetField())
+			}
+		})
+	}
+}
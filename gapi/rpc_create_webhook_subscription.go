@@ -0,0 +1,65 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/util"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) CreateWebhookSubscription(ctx context.Context, req *pb.CreateWebhookSubscriptionRequest) (*pb.CreateWebhookSubscriptionResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if violations := validateCreateWebhookSubscriptionRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	secret := req.GetSecret()
+	if req.Secret == nil {
+		secret, err = util.RandomSecret(32)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot generate webhook secret: %s", err)
+		}
+	}
+
+	arg := db.CreateWebhookSubscriptionParams{
+		Owner:      payload.Username,
+		Url:        req.GetUrl(),
+		EventTypes: req.GetEventTypes(),
+		Secret:     secret,
+	}
+
+	subscription, err := srv.store.CreateWebhookSubscription(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create webhook subscription: %s", err)
+	}
+
+	rsp := &pb.CreateWebhookSubscriptionResponse{
+		Subscription: convertWebhookSubscription(subscription),
+		Secret:       subscription.Secret,
+	}
+	return rsp, nil
+}
+
+func validateCreateWebhookSubscriptionRequest(req *pb.CreateWebhookSubscriptionRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateWebhookURL(req.GetUrl()); err != nil {
+		violations = append(violations, fieldViolation("url", err))
+	}
+	if err := val.ValidateWebhookEventTypes(req.GetEventTypes()); err != nil {
+		violations = append(violations, fieldViolation("event_types", err))
+	}
+	if req.Secret != nil {
+		if err := val.ValidateWebhookSecret(req.GetSecret()); err != nil {
+			violations = append(violations, fieldViolation("secret", err))
+		}
+	}
+	return
+}
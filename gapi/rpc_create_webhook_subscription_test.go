@@ -0,0 +1,218 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreateWebhookSubscriptionGAPI(t *testing.T) {
+	user, _ := randomUser()
+	subscription := randomWebhookSubscription(user.Username)
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateWebhookSubscriptionRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.CreateWebhookSubscriptionResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.CreateWebhookSubscriptionRequest{
+				Url:        subscription.Url,
+				EventTypes: subscription.EventTypes,
+				Secret:     proto.String(subscription.Secret),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateWebhookSubscriptionParams{
+					Owner:      user.Username,
+					Url:        subscription.Url,
+					EventTypes: subscription.EventTypes,
+					Secret:     subscription.Secret,
+				}
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(subscription, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateWebhookSubscriptionResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Equal(t, subscription.ID, res.GetSubscription().GetId())
+				require.Equal(t, subscription.Url, res.GetSubscription().GetUrl())
+				require.Equal(t, subscription.EventTypes, res.GetSubscription().GetEventTypes())
+				require.Equal(t, subscription.Secret, res.GetSecret())
+			},
+		}, {
+			name: "GeneratedSecret",
+			req: &pb.CreateWebhookSubscriptionRequest{
+				Url:        subscription.Url,
+				EventTypes: subscription.EventTypes,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+						require.Len(t, arg.Secret, 64)
+						subscription := subscription
+						subscription.Secret = arg.Secret
+						return subscription, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateWebhookSubscriptionResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Len(t, res.GetSecret(), 64)
+			},
+		}, {
+			name: "InternalError",
+			req: &pb.CreateWebhookSubscriptionRequest{
+				Url:        subscription.Url,
+				EventTypes: subscription.EventTypes,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.WebhookSubscription{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateWebhookSubscriptionResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		}, {
+			name: "InvalidURL",
+			req: &pb.CreateWebhookSubscriptionRequest{
+				Url:        "ftp://example.com/hook",
+				EventTypes: subscription.EventTypes,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateWebhookSubscriptionResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "InvalidEventType",
+			req: &pb.CreateWebhookSubscriptionRequest{
+				Url:        subscription.Url,
+				EventTypes: []string{db.EventUserCreated},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateWebhookSubscriptionResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "ShortSecret",
+			req: &pb.CreateWebhookSubscriptionRequest{
+				Url:        subscription.Url,
+				EventTypes: subscription.EventTypes,
+				Secret:     proto.String("short"),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateWebhookSubscriptionResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "NoAuthorization",
+			req: &pb.CreateWebhookSubscriptionRequest{
+				Url:        subscription.Url,
+				EventTypes: subscription.EventTypes,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateWebhookSubscriptionResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_CreateWebhookSubscription_FullMethodName, tc.req, server.CreateWebhookSubscription)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func randomWebhookSubscription(owner string) db.WebhookSubscription {
+	return db.WebhookSubscription{
+		ID:         util.RandomInt(1, 1000),
+		Owner:      owner,
+		Url:        "https://example.com/hooks/" + util.RandomString(6),
+		EventTypes: []string{db.EventTransferCreated, db.EventAccountCreated},
+		Secret:     util.RandomString(32),
+		IsActive:   true,
+		CreatedAt:  time.Now(),
+	}
+}
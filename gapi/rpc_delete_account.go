@@ -14,7 +14,7 @@ import (
 )
 
 func (srv *Server) DeleteAccount(ctx context.Context, req *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
-	payload, err := srv.authorizeUser(ctx)
+	payload, err := authPayload(ctx)
 	if err != nil {
 		return nil, unauthenticatedError(err)
 	}
@@ -207,7 +207,7 @@ func TestDeleteAccount(t *testing.T) {
 			tc.buildStubs(store)
 			server := NewTestServer(t, store, nil)
 			ctx := tc.buildContext(t, server.tokenMaker)
-			res, err := server.DeleteAccount(ctx, tc.req)
+			res, err := callUnary(ctx, server, pb.SimpleBank_DeleteAccount_FullMethodName, tc.req, server.DeleteAccount)
 			tc.checkResponce(t, res, err)
 		})
 	}
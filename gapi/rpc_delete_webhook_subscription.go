@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) DeleteWebhookSubscription(ctx context.Context, req *pb.DeleteWebhookSubscriptionRequest) (*pb.DeleteWebhookSubscriptionResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if violations := validateDeleteWebhookSubscriptionRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	subscription, err := srv.store.GetWebhookSubscription(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "webhook subscription not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get webhook subscription: %s", err)
+	}
+
+	if payload.Username != subscription.Owner {
+		return nil, status.Errorf(
+			codes.PermissionDenied,
+			"user: %s not allowed to delete webhook subscription ID: %d",
+			payload.Username,
+			req.GetId())
+	}
+
+	_, err = srv.store.DeactivateWebhookSubscription(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot delete webhook subscription: %s", err)
+	}
+
+	rsp := &pb.DeleteWebhookSubscriptionResponse{
+		SubscriptionWasDeleted: fmt.Sprint(req.GetId()),
+	}
+	return rsp, nil
+}
+
+func validateDeleteWebhookSubscriptionRequest(req *pb.DeleteWebhookSubscriptionRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateAccountId(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return
+}
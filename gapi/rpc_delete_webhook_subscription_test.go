@@ -0,0 +1,155 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeleteWebhookSubscriptionGAPI(t *testing.T) {
+	user, _ := randomUser()
+	otherUser, _ := randomUser()
+	subscription := randomWebhookSubscription(user.Username)
+
+	testCases := []struct {
+		name          string
+		req           *pb.DeleteWebhookSubscriptionRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.DeleteWebhookSubscriptionResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.DeleteWebhookSubscriptionRequest{Id: subscription.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(subscription, nil)
+
+				store.EXPECT().
+					DeactivateWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(subscription, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.DeleteWebhookSubscriptionResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Equal(t, fmt.Sprint(subscription.ID), res.GetSubscriptionWasDeleted())
+			},
+		}, {
+			name: "NotFound",
+			req:  &pb.DeleteWebhookSubscriptionRequest{Id: subscription.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(db.WebhookSubscription{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					DeactivateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.DeleteWebhookSubscriptionResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		}, {
+			name: "NotOwner",
+			req:  &pb.DeleteWebhookSubscriptionRequest{Id: subscription.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(subscription, nil)
+
+				store.EXPECT().
+					DeactivateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, otherUser.Username, otherUser.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.DeleteWebhookSubscriptionResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name: "InternalError",
+			req:  &pb.DeleteWebhookSubscriptionRequest{Id: subscription.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(subscription, nil)
+
+				store.EXPECT().
+					DeactivateWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(db.WebhookSubscription{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.DeleteWebhookSubscriptionResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		}, {
+			name: "InvalidID",
+			req:  &pb.DeleteWebhookSubscriptionRequest{Id: 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.DeleteWebhookSubscriptionResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_DeleteWebhookSubscription_FullMethodName, tc.req, server.DeleteWebhookSubscription)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
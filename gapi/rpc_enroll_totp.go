@@ -0,0 +1,52 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/totp"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// totpIssuer name of the account shown by authenticator apps
+const totpIssuer = "SimpleBank"
+
+func (srv *Server) EnrollTotp(ctx context.Context, req *pb.EnrollTotpRequest) (*pb.EnrollTotpResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	user, err := srv.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get user: %s", err)
+	}
+	if user.TotpEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot generate totp secret: %s", err)
+	}
+
+	_, err = srv.store.SetUserTotpSecret(ctx, db.SetUserTotpSecretParams{
+		Username: user.Username,
+		TotpSecret: pgtype.Text{
+			String: secret,
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot save totp secret: %s", err)
+	}
+
+	rsp := &pb.EnrollTotpResponse{
+		Secret:     secret,
+		OtpauthUri: totp.URI(totpIssuer, user.Username, secret),
+	}
+	return rsp, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	db "github.com/dubass83/simplebank/db/sqlc"
 	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/util"
 	"github.com/dubass83/simplebank/val"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
@@ -13,7 +14,7 @@ import (
 )
 
 func (srv *Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
-	payload, err := srv.authorizeUser(ctx)
+	payload, err := authPayload(ctx)
 	if err != nil {
 		return nil, unauthenticatedError(err)
 	}
@@ -31,11 +32,17 @@ func (srv *Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*
 	}
 
 	if payload.Username != Account.Owner {
-		return nil, status.Errorf(
-			codes.Unauthenticated,
-			"user: %s not allouwd to get info for account ID: %d",
-			payload.Username,
-			req.GetId())
+		ok, err := srv.permissions.HasPermission(ctx, payload, util.PermissionAccountsReadAny)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot check permission: %s", err)
+		}
+		if !ok {
+			return nil, status.Errorf(
+				codes.Unauthenticated,
+				"user: %s not allouwd to get info for account ID: %d",
+				payload.Username,
+				req.GetId())
+		}
 	}
 
 	rsp := &pb.GetAccountResponse{
@@ -142,7 +142,7 @@ func TestGetAccount(t *testing.T) {
 			tc.buildStubs(store)
 			server := NewTestServer(t, store, nil)
 			ctx := tc.buildContext(t, server.tokenMaker)
-			res, err := server.GetAccount(ctx, tc.req)
+			res, err := callUnary(ctx, server, pb.SimpleBank_GetAccount_FullMethodName, tc.req, server.GetAccount)
 			tc.checkResponce(t, res, err)
 		})
 	}
@@ -14,7 +14,7 @@ import (
 )
 
 func (srv *Server) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
-	payload, err := srv.authorizeUser(ctx)
+	payload, err := authPayload(ctx)
 	if err != nil {
 		return nil, unauthenticatedError(err)
 	}
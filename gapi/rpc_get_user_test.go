@@ -169,7 +169,7 @@ func TestGetUser(t *testing.T) {
 			tc.buildStubs(store)
 			server := NewTestServer(t, store, nil)
 			ctx := tc.buildContext(t, server.tokenMaker)
-			res, err := server.GetUser(ctx, tc.req)
+			res, err := callUnary(ctx, server, pb.SimpleBank_GetUser_FullMethodName, tc.req, server.GetUser)
 			tc.checkResponce(t, res, err)
 		})
 	}
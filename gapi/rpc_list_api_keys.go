@@ -0,0 +1,26 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dubass83/simplebank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) ListApiKeys(ctx context.Context, req *pb.ListApiKeysRequest) (*pb.ListApiKeysResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	apiKeys, err := srv.store.ListApiKeys(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get list of api keys: %s", err)
+	}
+
+	rsp := &pb.ListApiKeysResponse{
+		ApiKeys: convertApiKeys(apiKeys),
+	}
+	return rsp, nil
+}
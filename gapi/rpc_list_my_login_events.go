@@ -0,0 +1,47 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) ListMyLoginEvents(ctx context.Context, req *pb.ListMyLoginEventsRequest) (*pb.ListMyLoginEventsResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if violations := validateListMyLoginEventsRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	events, err := srv.store.ListLoginEvents(ctx, db.ListLoginEventsParams{
+		Username: payload.Username,
+		Limit:    req.GetPageSize(),
+		Offset:   (req.GetPageNumber() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get list of login events: %s", err)
+	}
+
+	rsp := &pb.ListMyLoginEventsResponse{
+		Events: convertLoginEvents(events),
+	}
+	return rsp, nil
+}
+
+func validateListMyLoginEventsRequest(req *pb.ListMyLoginEventsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidatePageNumber(req.GetPageNumber()); err != nil {
+		violations = append(violations, fieldViolation("page_number", err))
+	}
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	return
+}
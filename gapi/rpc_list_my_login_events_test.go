@@ -0,0 +1,128 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListMyLoginEventsGAPI(t *testing.T) {
+	user, _ := randomUser()
+	events := []db.LoginEvent{
+		{ID: 2, Username: user.Username, Success: true, Reason: loginReasonSuccess, CreatedAt: time.Now()},
+		{ID: 1, Username: user.Username, Success: false, Reason: loginReasonWrongPassword, CreatedAt: time.Now()},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListMyLoginEventsRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ListMyLoginEventsResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ListMyLoginEventsRequest{PageNumber: 2, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListLoginEventsParams{
+					Username: user.Username,
+					Limit:    5,
+					Offset:   5,
+				}
+				store.EXPECT().
+					ListLoginEvents(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(events, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListMyLoginEventsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetEvents(), 2)
+				require.Equal(t, int64(2), res.GetEvents()[0].GetId())
+				require.True(t, res.GetEvents()[0].GetSuccess())
+				require.Equal(t, loginReasonWrongPassword, res.GetEvents()[1].GetReason())
+			},
+		}, {
+			name: "InternalError",
+			req:  &pb.ListMyLoginEventsRequest{PageNumber: 1, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListLoginEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListMyLoginEventsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		}, {
+			name: "BadPageSize",
+			req:  &pb.ListMyLoginEventsRequest{PageNumber: 1, PageSize: int32(util.RandomInt(1000, 2000))},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListLoginEvents(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListMyLoginEventsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "Unauthenticated",
+			req:  &pb.ListMyLoginEventsRequest{PageNumber: 1, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListLoginEvents(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.ListMyLoginEventsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_ListMyLoginEvents_FullMethodName, tc.req, server.ListMyLoginEvents)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dubass83/simplebank/pb"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) ListMySessions(ctx context.Context, req *pb.ListMySessionsRequest) (*pb.ListMySessionsResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	sessions, err := srv.store.ListActiveSessions(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get list of sessions: %s", err)
+	}
+
+	rsp := &pb.ListMySessionsResponse{
+		Sessions: convertSessions(sessions, uuid.UUID(payload.SessionID)),
+	}
+	return rsp, nil
+}
@@ -0,0 +1,136 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/revocation"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListMySessionsGAPI(t *testing.T) {
+	user, _ := randomUser()
+	current := randomUserSession(user.Username)
+	other := randomUserSession(user.Username)
+
+	testCases := []struct {
+		name          string
+		revoked       error
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ListMySessionsResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListActiveSessions(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return([]db.Session{current, other}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildSessionContext(t, tokenMaker, current, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListMySessionsResponse, err error) {
+				require.NoError(t, err)
+				sessions := res.GetSessions()
+				require.Len(t, sessions, 2)
+				require.Equal(t, current.ID.String(), sessions[0].GetId())
+				require.True(t, sessions[0].GetIsCurrent())
+				require.Equal(t, other.ID.String(), sessions[1].GetId())
+				require.False(t, sessions[1].GetIsCurrent())
+			},
+		}, {
+			name:    "RevokedSession",
+			revoked: revocation.ErrSessionRevoked,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListActiveSessions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildSessionContext(t, tokenMaker, current, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListMySessionsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "NoAuthorization",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListActiveSessions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.ListMySessionsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListActiveSessions(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return([]db.Session{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListMySessionsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			server.revocation = revocationStub{err: tc.revoked}
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_ListMySessions_FullMethodName, &pb.ListMySessionsRequest{}, server.ListMySessions)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func randomUserSession(username string) db.Session {
+	return db.Session{
+		ID:           uuid.New(),
+		Username:     username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    "test-agent",
+		ClientIp:     "127.0.0.1",
+		ExpiredAt:    time.Now().Add(time.Hour),
+		CreatedAt:    time.Now(),
+	}
+}
@@ -0,0 +1,43 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) ListPendingTransfers(ctx context.Context, req *pb.ListPendingTransfersRequest) (*pb.ListPendingTransfersResponse, error) {
+	if violations := validateListPendingTransfersRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.ListPendingTransfersParams{
+		Limit:  req.GetPageSize(),
+		Offset: (req.GetPageNumber() - 1) * req.GetPageSize(),
+	}
+
+	transfers, err := srv.store.ListPendingTransfers(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get list of pending transfers: %s", err)
+	}
+
+	rsp := &pb.ListPendingTransfersResponse{
+		Transfers: convertTransfers(transfers),
+	}
+	return rsp, nil
+}
+
+func validateListPendingTransfersRequest(req *pb.ListPendingTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidatePageNumber(req.GetPageNumber()); err != nil {
+		violations = append(violations, fieldViolation("page_number", err))
+	}
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	return
+}
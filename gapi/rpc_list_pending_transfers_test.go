@@ -0,0 +1,131 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListPendingTransfersGAPI(t *testing.T) {
+	user, _ := randomUser()
+	banker, _ := randomUser()
+	banker.Role = util.BankerRole
+
+	transfers := []db.Transfer{}
+	for i := 0; i < 5; i++ {
+		transfers = append(transfers, randomPendingTransfer(user.Username))
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListPendingTransfersRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ListPendingTransfersResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ListPendingTransfersRequest{PageNumber: 2, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListPendingTransfersParams{
+					Limit:  5,
+					Offset: 5,
+				}
+				store.EXPECT().
+					ListPendingTransfers(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(transfers, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListPendingTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetTransfers(), len(transfers))
+				for _, transfer := range res.GetTransfers() {
+					require.Equal(t, db.TransferStatusPendingReview, transfer.GetStatus())
+				}
+			},
+		}, {
+			name: "NotBanker",
+			req:  &pb.ListPendingTransfersRequest{PageNumber: 1, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPendingTransfers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListPendingTransfersResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name: "InvalidPageSize",
+			req:  &pb.ListPendingTransfersRequest{PageNumber: 1, PageSize: 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPendingTransfers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListPendingTransfersResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "InternalError",
+			req:  &pb.ListPendingTransfersRequest{PageNumber: 1, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPendingTransfers(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Transfer{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListPendingTransfersResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_ListPendingTransfers_FullMethodName, tc.req, server.ListPendingTransfers)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
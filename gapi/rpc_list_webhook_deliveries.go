@@ -0,0 +1,69 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if violations := validateListWebhookDeliveriesRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	subscription, err := srv.store.GetWebhookSubscription(ctx, req.GetSubscriptionId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "webhook subscription not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get webhook subscription: %s", err)
+	}
+
+	if payload.Username != subscription.Owner {
+		return nil, status.Errorf(
+			codes.PermissionDenied,
+			"user: %s not allowed to see deliveries of webhook subscription ID: %d",
+			payload.Username,
+			req.GetSubscriptionId())
+	}
+
+	arg := db.ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Limit:          req.GetPageSize(),
+		Offset:         (req.GetPageNumber() - 1) * req.GetPageSize(),
+	}
+
+	deliveries, err := srv.store.ListWebhookDeliveries(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get list of webhook deliveries: %s", err)
+	}
+
+	rsp := &pb.ListWebhookDeliveriesResponse{
+		Deliveries: convertWebhookDeliveries(deliveries),
+	}
+	return rsp, nil
+}
+
+func validateListWebhookDeliveriesRequest(req *pb.ListWebhookDeliveriesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateAccountId(req.GetSubscriptionId()); err != nil {
+		violations = append(violations, fieldViolation("subscription_id", err))
+	}
+	if err := val.ValidatePageNumber(req.GetPageNumber()); err != nil {
+		violations = append(violations, fieldViolation("page_number", err))
+	}
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	return
+}
@@ -0,0 +1,133 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListWebhookDeliveriesGAPI(t *testing.T) {
+	user, _ := randomUser()
+	otherUser, _ := randomUser()
+	subscription := randomWebhookSubscription(user.Username)
+
+	n := 5
+	deliveries := make([]db.WebhookDelivery, n)
+	for i := range deliveries {
+		deliveries[i] = randomWebhookDelivery(subscription.ID)
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListWebhookDeliveriesRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ListWebhookDeliveriesResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.ListWebhookDeliveriesRequest{
+				SubscriptionId: subscription.ID,
+				PageNumber:     2,
+				PageSize:       int32(n),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(subscription, nil)
+
+				arg := db.ListWebhookDeliveriesParams{
+					SubscriptionID: subscription.ID,
+					Limit:          int32(n),
+					Offset:         int32(n),
+				}
+				store.EXPECT().
+					ListWebhookDeliveries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(deliveries, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListWebhookDeliveriesResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetDeliveries(), n)
+				for i, delivery := range res.GetDeliveries() {
+					require.Equal(t, deliveries[i].ID, delivery.GetId())
+					require.Equal(t, deliveries[i].Status, delivery.GetStatus())
+				}
+			},
+		}, {
+			name: "NotOwner",
+			req: &pb.ListWebhookDeliveriesRequest{
+				SubscriptionId: subscription.ID,
+				PageNumber:     1,
+				PageSize:       int32(n),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(subscription, nil)
+				store.EXPECT().
+					ListWebhookDeliveries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, otherUser.Username, otherUser.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListWebhookDeliveriesResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name: "InvalidPageSize",
+			req: &pb.ListWebhookDeliveriesRequest{
+				SubscriptionId: subscription.ID,
+				PageNumber:     1,
+				PageSize:       100,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListWebhookDeliveriesResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_ListWebhookDeliveries_FullMethodName, tc.req, server.ListWebhookDeliveries)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
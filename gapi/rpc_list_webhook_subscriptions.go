@@ -0,0 +1,26 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dubass83/simplebank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) ListWebhookSubscriptions(ctx context.Context, req *pb.ListWebhookSubscriptionsRequest) (*pb.ListWebhookSubscriptionsResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	subscriptions, err := srv.store.ListWebhookSubscriptions(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get list of webhook subscriptions: %s", err)
+	}
+
+	rsp := &pb.ListWebhookSubscriptionsResponse{
+		Subscriptions: convertWebhookSubscriptions(subscriptions),
+	}
+	return rsp, nil
+}
@@ -6,9 +6,12 @@ import (
 
 	db "github.com/dubass83/simplebank/db/sqlc"
 	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
 	"github.com/dubass83/simplebank/util"
 	"github.com/dubass83/simplebank/val"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -19,39 +22,111 @@ func (srv *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb
 	if violations := validateLoginUserRequest(req); violations != nil {
 		return nil, invalidArgumentError(violations)
 	}
+	mtdt := srv.extractMetadata(ctx)
+
+	failures, err := srv.throttleLogin(ctx, req.GetUsername(), mtdt)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := srv.store.GetUser(ctx, req.GetUsername())
 	if err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
-			return nil, status.Errorf(codes.NotFound, "user not found")
+			util.CheckDummyPassword(req.GetPassword())
+			return nil, srv.failLogin(ctx, req.GetUsername(), mtdt, failures, loginReasonUnknownUser)
 		}
 		return nil, status.Errorf(codes.Internal, "cannot get user")
 	}
 
+	// password of the locked user is not checked until the lockout ends
+	if isUserLocked(user) {
+		if err := srv.recordLoginEvent(ctx, user.Username, mtdt, false, loginReasonLocked); err != nil {
+			return nil, err
+		}
+		return nil, invalidCredentialsError()
+	}
+
 	err = util.CheckPassword(req.GetPassword(), user.HashedPassword)
 	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "password doesnot match")
+		return nil, srv.failLogin(ctx, user.Username, mtdt, failures, loginReasonWrongPassword)
 	}
 
-	accessToken, accessPayload, err := srv.tokenMaker.CreateToken(req.GetUsername(), user.Role, srv.config.TokenDuration)
+	// the hash of the old algorithm or cost is upgraded while the password is known
+	if util.NeedsRehash(user.HashedPassword) {
+		user = srv.rehashPassword(ctx, user, req.GetPassword())
+	}
+
+	if failures > 0 {
+		err = srv.store.DeleteFailedLogins(ctx, user.Username)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot reset failed logins")
+		}
+	}
+
+	// the second factor is checked by VerifyTotpLogin before tokens are issued
+	if user.TotpEnabled {
+		if err := srv.recordLoginEvent(ctx, user.Username, mtdt, false, loginReasonTotpRequired); err != nil {
+			return nil, err
+		}
+		return srv.totpChallenge(ctx, user)
+	}
+
+	return srv.createLoginSession(ctx, user)
+}
+
+// rehashPassword store the password hashed with the current parameters,
+// the login does not fail if the hash can not be upgraded
+func (srv *Server) rehashPassword(ctx context.Context, user db.User, password string) db.User {
+	hash, err := util.HashPassword(password)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed  create access  token")
+		log.Error().Err(err).Str("username", user.Username).Msg("cannot rehash password")
+		return user
 	}
+	// password_changed_at is kept, so issued tokens stay valid
+	updated, err := srv.store.UpdateUser(ctx, db.UpdateUserParams{
+		Username: user.Username,
+		HashedPassword: pgtype.Text{
+			String: hash,
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Str("username", user.Username).Msg("cannot store rehashed password")
+		return user
+	}
+	return updated
+}
 
-	refreshToken, refreshPayload, err := srv.tokenMaker.CreateToken(req.GetUsername(), user.Role, srv.config.RefreshTokenDuration)
+// createLoginSession issue access and refresh tokens for the authenticated user
+func (srv *Server) createLoginSession(ctx context.Context, user db.User) (*pb.LoginUserResponse, error) {
+	refreshToken, refreshPayload, err := srv.tokenMaker.CreateToken(user.Username, user.Role, srv.config.RefreshTokenDuration,
+		token.WithPurpose(token.PurposeRefresh))
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed create refresh token")
 	}
 
+	// id of the refresh token is the id of the session
+	accessToken, accessPayload, err := srv.tokenMaker.CreateToken(user.Username, user.Role, srv.config.TokenDuration,
+		token.WithSessionID(refreshPayload.ID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed  create access  token")
+	}
+
 	mtdt := srv.extractMetadata(ctx)
 
+	if err := srv.recordLoginSuccess(ctx, user.Username, mtdt); err != nil {
+		return nil, err
+	}
+
 	session, err := srv.store.CreateSession(ctx, db.CreateSessionParams{
 		ID:           uuid.UUID(refreshPayload.ID),
-		Username:     req.GetUsername(),
+		Username:     user.Username,
 		RefreshToken: refreshToken,
 		UserAgent:    mtdt.UserAgent,
 		ClientIp:     mtdt.ClientIP,
 		IsBloked:     false,
 		ExpiredAt:    refreshPayload.ExpiredAt,
+		FamilyID:     uuid.UUID(refreshPayload.ID),
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed create session")
@@ -68,6 +143,32 @@ func (srv *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb
 	return rsp, nil
 }
 
+// totpChallenge return short-lived token which can be exchanged only once by VerifyTotpLogin
+func (srv *Server) totpChallenge(ctx context.Context, user db.User) (*pb.LoginUserResponse, error) {
+	challengeToken, challengePayload, err := srv.tokenMaker.CreateToken(user.Username, user.Role, srv.config.TotpChallengeDuration,
+		token.WithPurpose(token.PurposeTotpChallenge))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed create challenge token")
+	}
+
+	_, err = srv.store.CreateTotpChallenge(ctx, db.CreateTotpChallengeParams{
+		ID:        uuid.UUID(challengePayload.ID),
+		Username:  user.Username,
+		ExpiredAt: challengePayload.ExpiredAt,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed create challenge")
+	}
+
+	rsp := &pb.LoginUserResponse{
+		TotpRequired:        true,
+		ChallengeToken:      challengeToken,
+		ChallengeTokenExpAt: timestamppb.New(challengePayload.ExpiredAt),
+		User:                convertUser(user),
+	}
+	return rsp, nil
+}
+
 func validateLoginUserRequest(req *pb.LoginUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
 	if err := val.ValidateUsername(req.GetUsername()); err != nil {
 		violations = append(violations, fieldViolation("username", err))
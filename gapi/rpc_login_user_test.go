@@ -13,7 +13,9 @@ import (
 	mockwk "github.com/dubass83/simplebank/worker/mock"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -38,6 +40,9 @@ func TestLoginUserGAPI(t *testing.T) {
 				Password: password,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginSuccess(store, user.Username)
+
+				expectLoginThrottle(store, 0, 0)
 
 				store.EXPECT().
 					GetUser(gomock.Any(), user.Username).
@@ -65,6 +70,78 @@ func TestLoginUserGAPI(t *testing.T) {
 				require.Equal(t, user.FullName, res.User.FullName)
 				require.Equal(t, sesionId.String(), res.SessionId)
 			},
+		}, {
+			name: "RehashLegacyPassword",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginSuccess(store, user.Username)
+
+				expectLoginThrottle(store, 0, 0)
+
+				legacyHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+				require.NoError(t, err)
+				legacyUser := user
+				legacyUser.HashedPassword = string(legacyHash)
+				store.EXPECT().
+					GetUser(gomock.Any(), user.Username).
+					Times(1).
+					Return(legacyUser, nil)
+
+				store.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserParams) (db.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.True(t, arg.HashedPassword.Valid)
+						require.False(t, util.NeedsRehash(arg.HashedPassword.String))
+						require.NoError(t, util.CheckPassword(password, arg.HashedPassword.String))
+						require.False(t, arg.PasswordChangedAt.Valid)
+						return user, nil
+					})
+
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{ID: sesionId, Username: user.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, user.Username, res.User.Username)
+			},
+		}, {
+			name: "TotpRequired",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginEvent(store, user.Username, false, loginReasonTotpRequired)
+
+				expectLoginThrottle(store, 0, 0)
+
+				totpUser := user
+				totpUser.TotpEnabled = true
+				store.EXPECT().
+					GetUser(gomock.Any(), user.Username).
+					Times(1).
+					Return(totpUser, nil)
+				expectCreateTotpChallenge(store, user.Username)
+
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetTotpRequired())
+				require.NotEmpty(t, res.GetChallengeToken())
+				require.Empty(t, res.GetAccessToken())
+				require.Empty(t, res.GetRefreshToken())
+				require.Empty(t, res.GetSessionId())
+			},
 		}, {
 			name: "InternalErrorGetUser",
 			req: &pb.LoginUserRequest{
@@ -72,6 +149,7 @@ func TestLoginUserGAPI(t *testing.T) {
 				Password: password,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginThrottle(store, 0, 0)
 
 				store.EXPECT().
 					GetUser(gomock.Any(), user.Username).
@@ -96,6 +174,9 @@ func TestLoginUserGAPI(t *testing.T) {
 				Password: password,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginSuccess(store, user.Username)
+
+				expectLoginThrottle(store, 0, 0)
 
 				store.EXPECT().
 					GetUser(gomock.Any(), user.Username).
@@ -114,6 +195,212 @@ func TestLoginUserGAPI(t *testing.T) {
 				require.True(t, ok)
 				require.Equal(t, st.Code(), codes.Internal)
 			},
+		}, {
+			name: "OKResetFailedLogins",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginSuccess(store, user.Username)
+
+				expectLoginThrottle(store, 0, 2)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), user.Username).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					DeleteFailedLogins(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(nil)
+
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{ID: sesionId, Username: user.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, sesionId.String(), res.SessionId)
+			},
+		}, {
+			name: "UserNotFound",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginEvent(store, user.Username, false, loginReasonUnknownUser)
+
+				expectLoginThrottle(store, 0, 0)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), user.Username).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					CreateFailedLogin(gomock.Any(), gomock.Eq(db.CreateFailedLoginParams{Username: user.Username})).
+					Times(1).
+					Return(db.FailedLogin{}, nil)
+
+				store.EXPECT().
+					LockUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				requireInvalidCredentials(t, res, err)
+			},
+		}, {
+			name: "WrongPassword",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: util.RandomString(10),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginEvent(store, user.Username, false, loginReasonWrongPassword)
+
+				expectLoginThrottle(store, 0, 1)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), user.Username).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateFailedLogin(gomock.Any(), gomock.Eq(db.CreateFailedLoginParams{Username: user.Username})).
+					Times(1).
+					Return(db.FailedLogin{}, nil)
+
+				store.EXPECT().
+					LockUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				requireInvalidCredentials(t, res, err)
+			},
+		}, {
+			name: "WrongPasswordLockUser",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: util.RandomString(10),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginEvent(store, user.Username, false, loginReasonWrongPassword)
+
+				expectLoginThrottle(store, 0, testLoginMaxAttempts-1)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), user.Username).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateFailedLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.FailedLogin{}, nil)
+
+				lockedUser := user
+				lockedUser.LockedUntil = pgtype.Timestamptz{Time: time.Now().Add(15 * time.Minute), Valid: true}
+				store.EXPECT().
+					LockUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.LockUserTxParams) (db.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.WithinDuration(t, time.Now().Add(15*time.Minute), arg.LockedUntil, time.Second)
+						return lockedUser, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				requireInvalidCredentials(t, res, err)
+			},
+		}, {
+			name: "LockedUser",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginEvent(store, user.Username, false, loginReasonLocked)
+
+				expectLoginThrottle(store, 0, 0)
+
+				lockedUser := user
+				lockedUser.LockedUntil = pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true}
+				store.EXPECT().
+					GetUser(gomock.Any(), user.Username).
+					Times(1).
+					Return(lockedUser, nil)
+
+				store.EXPECT().
+					CreateFailedLogin(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				requireInvalidCredentials(t, res, err)
+			},
+		}, {
+			name: "LockExpired",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginSuccess(store, user.Username)
+
+				expectLoginThrottle(store, 0, 0)
+
+				lockedUser := user
+				lockedUser.LockedUntil = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+				store.EXPECT().
+					GetUser(gomock.Any(), user.Username).
+					Times(1).
+					Return(lockedUser, nil)
+
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{ID: sesionId, Username: user.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+			},
+		}, {
+			name: "TooManyFailedLoginsFromClientIp",
+			req: &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginEvent(store, user.Username, false, loginReasonThrottled)
+
+				store.EXPECT().
+					CountFailedLoginsByClientIp(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(testLoginIpMaxAttempts), nil)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.ResourceExhausted, st.Code())
+			},
 		}, {
 			name: "BadInputUsername",
 			req: &pb.LoginUserRequest{
@@ -191,3 +478,56 @@ func TestLoginUserGAPI(t *testing.T) {
 
 	}
 }
+
+// expectLoginThrottle stub the counters of recent failed logins
+func expectLoginThrottle(store *mockdb.MockStore, ipFailures, failures int64) {
+	store.EXPECT().
+		CountFailedLoginsByClientIp(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(ipFailures, nil)
+
+	store.EXPECT().
+		CountFailedLoginsByUsername(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(failures, nil)
+}
+
+// expectCreateTotpChallenge stub the record of the challenge token returned by login
+func expectCreateTotpChallenge(store *mockdb.MockStore, username string) {
+	store.EXPECT().
+		CreateTotpChallenge(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.CreateTotpChallengeParams) (db.TotpChallenge, error) {
+			return db.TotpChallenge{ID: arg.ID, Username: username, ExpiredAt: arg.ExpiredAt}, nil
+		})
+}
+
+// expectLoginEvent stub the record of the login attempt
+func expectLoginEvent(store *mockdb.MockStore, username string, success bool, reason string) {
+	store.EXPECT().
+		CreateLoginEvent(gomock.Any(), gomock.Eq(db.CreateLoginEventParams{
+			Username: username,
+			Success:  success,
+			Reason:   reason,
+		})).
+		Times(1).
+		Return(db.LoginEvent{Username: username, Success: success, Reason: reason}, nil)
+}
+
+// expectLoginSuccess stub the record of the successful login from the known device
+func expectLoginSuccess(store *mockdb.MockStore, username string) {
+	store.EXPECT().
+		GetLoginDeviceStats(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.GetLoginDeviceStatsRow{Logins: 1, DeviceLogins: 1}, nil)
+	expectLoginEvent(store, username, true, loginReasonSuccess)
+}
+
+func requireInvalidCredentials(t *testing.T, res *pb.LoginUserResponse, err error) {
+	require.Error(t, err)
+	require.Nil(t, res)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Unauthenticated, st.Code())
+	require.Equal(t, "invalid username or password", st.Message())
+}
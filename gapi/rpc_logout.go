@@ -0,0 +1,34 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	if err := val.ValidateRefreshToken(req.GetRefreshToken()); err != nil {
+		violations := []*errdetails.BadRequest_FieldViolation{fieldViolation("refresh_token", err)}
+		return nil, invalidArgumentError(violations)
+	}
+
+	_, session, err := srv.refreshTokens.Verify(ctx, req.GetRefreshToken())
+	if err != nil {
+		return nil, refreshError(err)
+	}
+
+	session, err = srv.store.BlockSession(ctx, session.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot block session: %s", err)
+	}
+	srv.revocation.ForgetSession(session.ID)
+
+	rsp := &pb.LogoutResponse{
+		SessionId: session.ID.String(),
+	}
+	return rsp, nil
+}
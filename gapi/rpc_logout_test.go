@@ -0,0 +1,101 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogoutGAPI(t *testing.T) {
+	user, _ := randomUser()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(t *testing.T, res *pb.LogoutResponse, session db.Session, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+
+				blocked := session
+				blocked.IsBloked = true
+				store.EXPECT().
+					BlockSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(blocked, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutResponse, session db.Session, err error) {
+				require.NoError(t, err)
+				require.Equal(t, session.ID.String(), res.GetSessionId())
+			},
+		}, {
+			name: "AlreadyLoggedOut",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				session.IsBloked = true
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					BlockSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutResponse, session db.Session, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					BlockSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(db.Session{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutResponse, session db.Session, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			server := NewTestServer(t, store, nil)
+			refreshToken, session := randomSession(t, server.tokenMaker, user)
+			tc.buildStubs(store, session)
+
+			res, err := server.Logout(context.Background(), &pb.LogoutRequest{
+				RefreshToken: refreshToken,
+			})
+			tc.checkResponse(t, res, session, err)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/util"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RedeemLoginLink exchange the single-use code from the email for the session,
+// the same way LoginUser does it for the password
+func (srv *Server) RedeemLoginLink(ctx context.Context, req *pb.RedeemLoginLinkRequest) (*pb.LoginUserResponse, error) {
+	if violations := validateRedeemLoginLinkRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	link, err := srv.store.UseLoginLink(ctx, db.UseLoginLinkParams{
+		ID:             req.GetId(),
+		SecretCodeHash: util.HashSecretCode(req.GetSecretCode()),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.Unauthenticated, "login link is invalid, used or expired")
+		}
+		return nil, status.Errorf(codes.Internal, "cannot use login link: %s", err)
+	}
+
+	user, err := srv.store.GetUser(ctx, link.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get user: %s", err)
+	}
+
+	mtdt := srv.extractMetadata(ctx)
+	if isUserLocked(user) {
+		if err := srv.recordLoginEvent(ctx, user.Username, mtdt, false, loginReasonLocked); err != nil {
+			return nil, err
+		}
+		return nil, invalidCredentialsError()
+	}
+
+	// the link replaces the password, not the second factor
+	if user.TotpEnabled {
+		if err := srv.recordLoginEvent(ctx, user.Username, mtdt, false, loginReasonTotpRequired); err != nil {
+			return nil, err
+		}
+		return srv.totpChallenge(ctx, user)
+	}
+
+	return srv.createLoginSession(ctx, user)
+}
+
+func validateRedeemLoginLinkRequest(req *pb.RedeemLoginLinkRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateVerifyEmailID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	if err := val.ValidateVerifyEmailSecretCode(req.GetSecretCode()); err != nil {
+		violations = append(violations, fieldViolation("secret_code", err))
+	}
+	return
+}
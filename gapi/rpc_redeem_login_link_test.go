@@ -0,0 +1,171 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRedeemLoginLinkGAPI(t *testing.T) {
+	user, _ := randomUser()
+	totpUser, _ := randomTotpUser(t)
+	id := util.RandomInt(1, 100)
+	secretCode := util.RandomString(32)
+	arg := db.UseLoginLinkParams{
+		ID:             id,
+		SecretCodeHash: util.HashSecretCode(secretCode),
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.RedeemLoginLinkRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.LoginUserResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.RedeemLoginLinkRequest{Id: id, SecretCode: secretCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UseLoginLink(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.LoginLink{ID: id, Username: user.Username, IsUsed: true}, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				expectLoginSuccess(store, user.Username)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{ID: uuid.New(), Username: user.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+				require.NotEmpty(t, res.GetRefreshToken())
+				require.Equal(t, user.Username, res.GetUser().GetUsername())
+			},
+		}, {
+			name: "TotpRequired",
+			req:  &pb.RedeemLoginLinkRequest{Id: id, SecretCode: secretCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UseLoginLink(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.LoginLink{ID: id, Username: totpUser.Username, IsUsed: true}, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(totpUser.Username)).
+					Times(1).
+					Return(totpUser, nil)
+				expectLoginEvent(store, totpUser.Username, false, loginReasonTotpRequired)
+				expectCreateTotpChallenge(store, totpUser.Username)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetTotpRequired())
+				require.NotEmpty(t, res.GetChallengeToken())
+				require.Empty(t, res.GetAccessToken())
+			},
+		}, {
+			name: "LockedUser",
+			req:  &pb.RedeemLoginLinkRequest{Id: id, SecretCode: secretCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				locked := user
+				locked.LockedUntil = pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true}
+				store.EXPECT().
+					UseLoginLink(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.LoginLink{ID: id, Username: user.Username, IsUsed: true}, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(locked, nil)
+				expectLoginEvent(store, user.Username, false, loginReasonLocked)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				requireInvalidCredentials(t, res, err)
+			},
+		}, {
+			name: "InvalidLink",
+			req:  &pb.RedeemLoginLinkRequest{Id: id, SecretCode: secretCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UseLoginLink(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.LoginLink{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "InternalError",
+			req:  &pb.RedeemLoginLinkRequest{Id: id, SecretCode: secretCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UseLoginLink(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.LoginLink{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		}, {
+			name: "BadSecretCode",
+			req:  &pb.RedeemLoginLinkRequest{Id: id, SecretCode: "qwerty"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UseLoginLink(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			res, err := callUnary(context.Background(), server, pb.SimpleBank_RedeemLoginLink_FullMethodName, tc.req, server.RedeemLoginLink)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRejectTransferGAPI(t *testing.T) {
+	user, _ := randomUser()
+	banker, _ := randomUser()
+	banker.Role = util.BankerRole
+	transfer := randomPendingTransfer(user.Username)
+
+	rejected := transfer
+	rejected.Status = db.TransferStatusRejected
+	rejected.ReviewedBy = pgtype.Text{String: banker.Username, Valid: true}
+
+	testCases := []struct {
+		name          string
+		req           *pb.RejectTransferRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.RejectTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.RejectTransferRequest{Id: transfer.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ReviewTransferTxParams{
+					TransferID: transfer.ID,
+					ReviewedBy: banker.Username,
+					Approved:   false,
+				}
+				store.EXPECT().
+					ReviewTransferTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ReviewTransferTxResult{
+						TransferTxResult: db.TransferTxResult{Transfer: rejected},
+					}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RejectTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.TransferStatusRejected, res.GetTransfer().GetStatus())
+				require.Equal(t, banker.Username, res.GetTransfer().GetReviewedBy())
+			},
+		}, {
+			name: "NotBanker",
+			req:  &pb.RejectTransferRequest{Id: transfer.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReviewTransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RejectTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name: "SelfReview",
+			req:  &pb.RejectTransferRequest{Id: transfer.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReviewTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReviewTransferTxResult{}, db.ErrSelfReview)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RejectTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name: "Unauthenticated",
+			req:  &pb.RejectTransferRequest{Id: transfer.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReviewTransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.RejectTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_RejectTransfer_FullMethodName, tc.req, server.RejectTransfer)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
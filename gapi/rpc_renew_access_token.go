@@ -0,0 +1,47 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/val"
+	gofrsuuid "github.com/gofrs/uuid/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (srv *Server) RenewAccessToken(ctx context.Context, req *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	if err := val.ValidateRefreshToken(req.GetRefreshToken()); err != nil {
+		violations := []*errdetails.BadRequest_FieldViolation{fieldViolation("refresh_token", err)}
+		return nil, invalidArgumentError(violations)
+	}
+
+	payload, session, err := srv.refreshTokens.Verify(ctx, req.GetRefreshToken())
+	if err != nil {
+		return nil, refreshError(err)
+	}
+
+	mtdt := srv.extractMetadata(ctx)
+	refreshToken, session, err := srv.refreshTokens.Rotate(ctx, session, payload.Role, mtdt.UserAgent, mtdt.ClientIP)
+	if err != nil {
+		return nil, refreshError(err)
+	}
+
+	accessToken, accessPayload, err := srv.tokenMaker.CreateToken(session.Username, payload.Role, srv.config.TokenDuration,
+		token.WithSessionID(gofrsuuid.UUID(session.ID)))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed create access token")
+	}
+
+	rsp := &pb.RenewAccessTokenResponse{
+		SessionId:         session.ID.String(),
+		AccessToken:       accessToken,
+		AccessTokenExpAt:  timestamppb.New(accessPayload.ExpiredAt),
+		RefreshToken:      refreshToken,
+		RefreshTokenExpAt: timestamppb.New(session.ExpiredAt),
+	}
+	return rsp, nil
+}
@@ -0,0 +1,275 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRenewAccessTokenGAPI(t *testing.T) {
+	user, _ := randomUser()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(t *testing.T, res *pb.RenewAccessTokenResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+
+				newSession := session
+				newSession.ID = uuid.New()
+				newSession.ExpiredAt = time.Now().Add(time.Hour)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), eqRotateSessionTxParams(session)).
+					Times(1).
+					Return(newSession, nil)
+				store.EXPECT().
+					RevokeSessionFamilyTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+				require.NotEmpty(t, res.GetRefreshToken())
+				require.NotEmpty(t, res.GetSessionId())
+				require.WithinDuration(t, time.Now().Add(5*time.Minute), res.GetAccessTokenExpAt().AsTime(), time.Second)
+				require.WithinDuration(t, time.Now().Add(time.Hour), res.GetRefreshTokenExpAt().AsTime(), time.Second)
+			},
+		}, {
+			name: "ReusedRotatedToken",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				session.IsBloked = true
+				session.RotatedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					RevokeSessionFamilyTx(gomock.Any(), gomock.Eq(session)).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "ConcurrentRotation",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, db.ErrRefreshTokenReused)
+				store.EXPECT().
+					RevokeSessionFamilyTx(gomock.Any(), gomock.Eq(session)).
+					Times(1).
+					Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "BlokedSession",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				session.IsBloked = true
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "MismatchedToken",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				session.RefreshToken = "other"
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "ExpiredSession",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				session.ExpiredAt = time.Now().Add(-time.Minute)
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "SessionNotFound",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(db.Session{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		}, {
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(db.Session{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			server := NewTestServer(t, store, nil)
+			refreshToken, session := randomSession(t, server.tokenMaker, user)
+			tc.buildStubs(store, session)
+
+			res, err := server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{
+				RefreshToken: refreshToken,
+			})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestRenewAccessTokenWithAccessToken(t *testing.T) {
+	user, _ := randomUser()
+
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+	store := mockdb.NewMockStore(storeCtrl)
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	server := NewTestServer(t, store, nil)
+	accessToken, _, err := server.tokenMaker.CreateToken(user.Username, user.Role, time.Minute)
+	require.NoError(t, err)
+
+	_, err = server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{
+		RefreshToken: accessToken,
+	})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRenewAccessTokenInvalidToken(t *testing.T) {
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+	store := mockdb.NewMockStore(storeCtrl)
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	server := NewTestServer(t, store, nil)
+	_, err := server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{
+		RefreshToken: "invalid",
+	})
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Unauthenticated, st.Code())
+}
+
+// randomSession create refresh token and the session issued with it
+func randomSession(t *testing.T, tokenMaker token.Maker, user db.User) (string, db.Session) {
+	refreshToken, payload, err := tokenMaker.CreateToken(user.Username, user.Role, time.Hour,
+		token.WithPurpose(token.PurposeRefresh))
+	require.NoError(t, err)
+
+	session := db.Session{
+		ID:           uuid.UUID(payload.ID),
+		Username:     user.Username,
+		RefreshToken: refreshToken,
+		IsBloked:     false,
+		ExpiredAt:    payload.ExpiredAt,
+		CreatedAt:    payload.IssuedAt,
+		FamilyID:     uuid.UUID(payload.ID),
+	}
+	return refreshToken, session
+}
+
+type eqRotateSessionTxParamsMatcher struct {
+	session db.Session
+}
+
+func (expected eqRotateSessionTxParamsMatcher) Matches(x any) bool {
+	arg, ok := x.(db.RotateSessionTxParams)
+	if !ok {
+		return false
+	}
+	return arg.SessionID == expected.session.ID &&
+		arg.ID != expected.session.ID &&
+		arg.Username == expected.session.Username &&
+		arg.RefreshToken != expected.session.RefreshToken
+}
+
+func (expected eqRotateSessionTxParamsMatcher) String() string {
+	return fmt.Sprintf("rotates session %s", expected.session.ID)
+}
+
+// eqRotateSessionTxParams match rotation of the session into the new one with fresh refresh token
+func eqRotateSessionTxParams(session db.Session) gomock.Matcher {
+	return eqRotateSessionTxParamsMatcher{session}
+}
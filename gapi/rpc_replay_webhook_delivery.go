@@ -0,0 +1,87 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"github.com/dubass83/simplebank/worker"
+	"github.com/hibiken/asynq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) ReplayWebhookDelivery(ctx context.Context, req *pb.ReplayWebhookDeliveryRequest) (*pb.ReplayWebhookDeliveryResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if violations := validateReplayWebhookDeliveryRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	delivery, err := srv.store.GetWebhookDelivery(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "webhook delivery not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get webhook delivery: %s", err)
+	}
+
+	subscription, err := srv.store.GetWebhookSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get webhook subscription: %s", err)
+	}
+
+	if payload.Username != subscription.Owner {
+		return nil, status.Errorf(
+			codes.PermissionDenied,
+			"user: %s not allowed to replay webhook delivery ID: %d",
+			payload.Username,
+			req.GetId())
+	}
+	if !subscription.IsActive {
+		return nil, status.Errorf(codes.FailedPrecondition, "webhook subscription ID: %d is deleted", subscription.ID)
+	}
+
+	delivery, err = srv.store.ResetWebhookDelivery(ctx, delivery.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot reset webhook delivery: %s", err)
+	}
+
+	err = srv.taskDestributor.DestributeTaskDeliverWebhook(
+		ctx,
+		&worker.PayloadDeliverWebhook{DeliveryID: delivery.ID},
+		asynq.MaxRetry(worker.WebhookMaxRetry),
+		asynq.Queue(worker.QueueDefault),
+		// replays of the same attempt share the task id, so repeated requests
+		// are delivered once until the queued replay runs
+		asynq.TaskID(replayWebhookTaskID(delivery)),
+	)
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil, status.Errorf(codes.Internal, "cannot enqueue webhook delivery: %s", err)
+	}
+
+	rsp := &pb.ReplayWebhookDeliveryResponse{
+		Delivery: convertWebhookDelivery(delivery),
+	}
+	return rsp, nil
+}
+
+// replayWebhookTaskID differs from the task id of the first delivery and
+// changes after every attempt, so the delivery can be replayed again later
+func replayWebhookTaskID(delivery db.WebhookDelivery) string {
+	return fmt.Sprintf("webhook_delivery:%d:replay:%d", delivery.ID, delivery.Attempts)
+}
+
+func validateReplayWebhookDeliveryRequest(req *pb.ReplayWebhookDeliveryRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateAccountId(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return
+}
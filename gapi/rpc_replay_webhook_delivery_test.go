@@ -0,0 +1,271 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/dubass83/simplebank/worker"
+	mockwk "github.com/dubass83/simplebank/worker/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReplayWebhookDeliveryGAPI(t *testing.T) {
+	user, _ := randomUser()
+	otherUser, _ := randomUser()
+	subscription := randomWebhookSubscription(user.Username)
+	delivery := randomWebhookDelivery(subscription.ID)
+
+	testCases := []struct {
+		name          string
+		req           *pb.ReplayWebhookDeliveryRequest
+		buildStubs    func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ReplayWebhookDeliveryResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ReplayWebhookDeliveryRequest{Id: delivery.ID},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().
+					GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(delivery, nil)
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(subscription, nil)
+
+				pending := delivery
+				pending.Status = db.WebhookDeliveryPending
+				store.EXPECT().
+					ResetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(pending, nil)
+
+				taskDistributor.EXPECT().
+					DestributeTaskDeliverWebhook(
+						gomock.Any(),
+						gomock.Eq(&worker.PayloadDeliverWebhook{DeliveryID: delivery.ID}),
+						hasTaskID(fmt.Sprintf("webhook_delivery:%d:replay:%d", delivery.ID, delivery.Attempts)),
+					).
+					Times(1).
+					Return(nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReplayWebhookDeliveryResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Equal(t, delivery.ID, res.GetDelivery().GetId())
+				require.Equal(t, db.WebhookDeliveryPending, res.GetDelivery().GetStatus())
+			},
+		}, {
+			name: "NotFound",
+			req:  &pb.ReplayWebhookDeliveryRequest{Id: delivery.ID},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().
+					GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(db.WebhookDelivery{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ResetWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(0)
+				taskDistributor.EXPECT().
+					DestributeTaskDeliverWebhook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReplayWebhookDeliveryResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		}, {
+			name: "NotOwner",
+			req:  &pb.ReplayWebhookDeliveryRequest{Id: delivery.ID},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().
+					GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(delivery, nil)
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(subscription, nil)
+				store.EXPECT().
+					ResetWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(0)
+				taskDistributor.EXPECT().
+					DestributeTaskDeliverWebhook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, otherUser.Username, otherUser.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReplayWebhookDeliveryResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name: "InactiveSubscription",
+			req:  &pb.ReplayWebhookDeliveryRequest{Id: delivery.ID},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				inactive := subscription
+				inactive.IsActive = false
+				store.EXPECT().
+					GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(delivery, nil)
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(inactive, nil)
+				store.EXPECT().
+					ResetWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(0)
+				taskDistributor.EXPECT().
+					DestributeTaskDeliverWebhook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReplayWebhookDeliveryResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		}, {
+			name: "AlreadyQueued",
+			req:  &pb.ReplayWebhookDeliveryRequest{Id: delivery.ID},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().
+					GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(delivery, nil)
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(subscription, nil)
+				store.EXPECT().
+					ResetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(delivery, nil)
+				// the same replay was already enqueued, it is not delivered twice
+				taskDistributor.EXPECT().
+					DestributeTaskDeliverWebhook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(fmt.Errorf("failed to enqueue task: %w", asynq.ErrTaskIDConflict))
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReplayWebhookDeliveryResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, delivery.ID, res.GetDelivery().GetId())
+			},
+		}, {
+			name: "DistributeError",
+			req:  &pb.ReplayWebhookDeliveryRequest{Id: delivery.ID},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().
+					GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(delivery, nil)
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).
+					Times(1).
+					Return(subscription, nil)
+				store.EXPECT().
+					ResetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(delivery, nil)
+				taskDistributor.EXPECT().
+					DestributeTaskDeliverWebhook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReplayWebhookDeliveryResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			taskDistributor := mockwk.NewMockTaskDistributor(ctrl)
+			tc.buildStubs(store, taskDistributor)
+			server := NewTestServer(t, store, taskDistributor)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_ReplayWebhookDelivery_FullMethodName, tc.req, server.ReplayWebhookDelivery)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+type taskIDMatcher string
+
+func (id taskIDMatcher) Matches(x any) bool {
+	opts, ok := x.([]asynq.Option)
+	if !ok {
+		return false
+	}
+	for _, opt := range opts {
+		if opt.Type() == asynq.TaskIDOpt && opt.Value() == string(id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (id taskIDMatcher) String() string {
+	return fmt.Sprintf("has task id %s", string(id))
+}
+
+// hasTaskID match the options of the enqueued task by its task id
+func hasTaskID(id string) gomock.Matcher {
+	return taskIDMatcher(id)
+}
+
+func randomWebhookDelivery(subscriptionID int64) db.WebhookDelivery {
+	return db.WebhookDelivery{
+		ID:             util.RandomInt(1, 1000),
+		SubscriptionID: subscriptionID,
+		EventID:        util.RandomInt(1, 1000),
+		EventType:      db.EventTransferCreated,
+		Payload:        []byte(`{"transfer_id":1}`),
+		Status:         db.WebhookDeliveryFailed,
+		Attempts:       9,
+		CreatedAt:      time.Now(),
+	}
+}
@@ -0,0 +1,57 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"github.com/dubass83/simplebank/worker"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// RequestLoginLink always report success, whether the email belongs to
+// a user or not, so the response can not be used to enumerate accounts.
+// The link is sent only to verified emails, the unverified one may be a typo
+// of somebody else address.
+func (srv *Server) RequestLoginLink(ctx context.Context, req *pb.RequestLoginLinkRequest) (*pb.RequestLoginLinkResponse, error) {
+	if violations := validateRequestLoginLinkRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if err := srv.limitEmailRequests(ctx, srv.loginLinkLimit(), req.GetEmail()); err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.RequestLoginLinkResponse{IsRequested: true}
+
+	user, err := srv.store.GetUserByEmail(ctx, req.GetEmail())
+	if err != nil {
+		log.Info().Err(err).Msg("cannot find user for login link")
+		return rsp, nil
+	}
+	if !user.IsEmailVerified {
+		log.Info().Str("username", user.Username).Msg("login link is not sent to unverified email")
+		return rsp, nil
+	}
+
+	err = srv.taskDestributor.DestributeTaskSendLoginLinkEmail(
+		ctx,
+		&worker.PayloadSendLoginLinkEmail{Username: user.Username},
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueCritical),
+	)
+	if err != nil {
+		log.Error().Err(err).Str("username", user.Username).Msg("cannot enqueue login link email")
+	}
+
+	return rsp, nil
+}
+
+func validateRequestLoginLinkRequest(req *pb.RequestLoginLinkRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateEmail(req.GetEmail()); err != nil {
+		violations = append(violations, fieldViolation("email", err))
+	}
+	return
+}
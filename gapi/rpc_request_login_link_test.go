@@ -0,0 +1,188 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/util"
+	"github.com/dubass83/simplebank/worker"
+	mockwk "github.com/dubass83/simplebank/worker/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// expectLoginLinkRequest count the login link request for the key
+func expectLoginLinkRequest(store *mockdb.MockStore, key string, sent int64) {
+	store.EXPECT().
+		AddLoginLinkRequest(gomock.Any(), loginLinkRequestKey(key)).
+		Times(1).
+		Return(sent, nil)
+}
+
+type loginLinkRequestKey string
+
+func (key loginLinkRequestKey) Matches(x any) bool {
+	arg, ok := x.(db.AddLoginLinkRequestParams)
+	return ok && arg.Key == string(key) && time.Since(arg.WindowStart) > 0
+}
+
+func (key loginLinkRequestKey) String() string {
+	return fmt.Sprintf("counts login link requests for %s", string(key))
+}
+
+func TestRequestLoginLinkGAPI(t *testing.T) {
+	user, _ := randomUser()
+	user.IsEmailVerified = true
+
+	testCases := []struct {
+		name          string
+		req           *pb.RequestLoginLinkRequest
+		buildStubs    func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor)
+		checkResponse func(t *testing.T, res *pb.RequestLoginLinkResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.RequestLoginLinkRequest{Email: user.Email},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				expectLoginLinkRequest(store, "login:email:"+strings.ToLower(user.Email), 1)
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+
+				payload := &worker.PayloadSendLoginLinkEmail{Username: user.Username}
+				taskDistributor.EXPECT().
+					DestributeTaskSendLoginLinkEmail(gomock.Any(), gomock.Eq(payload), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestLoginLinkResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsRequested())
+			},
+		}, {
+			name: "UnknownEmail",
+			req:  &pb.RequestLoginLinkRequest{Email: user.Email},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				expectLoginLinkRequest(store, "login:email:"+strings.ToLower(user.Email), 1)
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+
+				taskDistributor.EXPECT().
+					DestributeTaskSendLoginLinkEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestLoginLinkResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsRequested())
+			},
+		}, {
+			name: "UnverifiedEmail",
+			req:  &pb.RequestLoginLinkRequest{Email: user.Email},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				expectLoginLinkRequest(store, "login:email:"+strings.ToLower(user.Email), 1)
+				unverified := user
+				unverified.IsEmailVerified = false
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(unverified, nil)
+
+				taskDistributor.EXPECT().
+					DestributeTaskSendLoginLinkEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestLoginLinkResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsRequested())
+			},
+		}, {
+			name: "TooManyForEmail",
+			req:  &pb.RequestLoginLinkRequest{Email: user.Email},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				expectLoginLinkRequest(store, "login:email:"+strings.ToLower(user.Email), testLoginLinkMaxEmails+1)
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(0)
+				taskDistributor.EXPECT().
+					DestributeTaskSendLoginLinkEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestLoginLinkResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.ResourceExhausted, st.Code())
+			},
+		}, {
+			name: "BadEmail",
+			req:  &pb.RequestLoginLinkRequest{Email: "bad at email.com"},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestLoginLinkResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			taskCtrl := gomock.NewController(t)
+			defer taskCtrl.Finish()
+			taskDistributor := mockwk.NewMockTaskDistributor(taskCtrl)
+
+			tc.buildStubs(store, taskDistributor)
+
+			server := NewTestServer(t, store, taskDistributor)
+			res, err := callUnary(context.Background(), server, pb.SimpleBank_RequestLoginLink_FullMethodName, tc.req, server.RequestLoginLink)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestRequestLoginLinkIpLimitGAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	taskDistributor := mockwk.NewMockTaskDistributor(ctrl)
+
+	email := util.RandomEmail()
+	expectLoginLinkRequest(store, "login:email:"+strings.ToLower(email), 1)
+	// the port of the peer is not a part of the key
+	expectLoginLinkRequest(store, "login:ip:203.0.113.7", testLoginLinkIpMaxEmails+1)
+	store.EXPECT().
+		GetUserByEmail(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	server := NewTestServer(t, store, taskDistributor)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 51234},
+	})
+	_, err := callUnary(ctx, server, pb.SimpleBank_RequestLoginLink_FullMethodName,
+		&pb.RequestLoginLinkRequest{Email: email}, server.RequestLoginLink)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
@@ -0,0 +1,51 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"github.com/dubass83/simplebank/worker"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// RequestPasswordReset always report success, whether the email belongs to
+// a user or not, so the response can not be used to enumerate accounts
+func (srv *Server) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	if violations := validateRequestPasswordResetRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if err := srv.limitEmailRequests(ctx, srv.passwordResetLimit(), req.GetEmail()); err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.RequestPasswordResetResponse{IsRequested: true}
+
+	user, err := srv.store.GetUserByEmail(ctx, req.GetEmail())
+	if err != nil {
+		log.Info().Err(err).Msg("cannot find user for password reset")
+		return rsp, nil
+	}
+
+	err = srv.taskDestributor.DestributeTaskSendPasswordResetEmail(
+		ctx,
+		&worker.PayloadSendPasswordResetEmail{Username: user.Username},
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueCritical),
+	)
+	if err != nil {
+		log.Error().Err(err).Str("username", user.Username).Msg("cannot enqueue password reset email")
+	}
+
+	return rsp, nil
+}
+
+func validateRequestPasswordResetRequest(req *pb.RequestPasswordResetRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateEmail(req.GetEmail()); err != nil {
+		violations = append(violations, fieldViolation("email", err))
+	}
+	return
+}
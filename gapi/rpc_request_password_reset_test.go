@@ -0,0 +1,208 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/util"
+	"github.com/dubass83/simplebank/worker"
+	mockwk "github.com/dubass83/simplebank/worker/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestPasswordResetGAPI(t *testing.T) {
+	user, _ := randomUser()
+
+	testCases := []struct {
+		name          string
+		req           *pb.RequestPasswordResetRequest
+		buildStubs    func(store *mockdb.MockStore, taskDistrebutor *mockwk.MockTaskDistributor)
+		checkResponse func(t *testing.T, resp *pb.RequestPasswordResetResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.RequestPasswordResetRequest{
+				Email: user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistrebutor *mockwk.MockTaskDistributor) {
+				expectLoginLinkRequest(store, "reset:email:"+strings.ToLower(user.Email), 1)
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+
+				payload := &worker.PayloadSendPasswordResetEmail{Username: user.Username}
+				taskDistrebutor.EXPECT().
+					DestributeTaskSendPasswordResetEmail(gomock.Any(), gomock.Eq(payload), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsRequested())
+			},
+		}, {
+			name: "UnknownEmail",
+			req: &pb.RequestPasswordResetRequest{
+				Email: user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistrebutor *mockwk.MockTaskDistributor) {
+				expectLoginLinkRequest(store, "reset:email:"+strings.ToLower(user.Email), 1)
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+
+				taskDistrebutor.EXPECT().
+					DestributeTaskSendPasswordResetEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsRequested())
+			},
+		}, {
+			name: "InternalError",
+			req: &pb.RequestPasswordResetRequest{
+				Email: user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistrebutor *mockwk.MockTaskDistributor) {
+				expectLoginLinkRequest(store, "reset:email:"+strings.ToLower(user.Email), 1)
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+
+				taskDistrebutor.EXPECT().
+					DestributeTaskSendPasswordResetEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsRequested())
+			},
+		}, {
+			name: "EnqueueError",
+			req: &pb.RequestPasswordResetRequest{
+				Email: user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistrebutor *mockwk.MockTaskDistributor) {
+				expectLoginLinkRequest(store, "reset:email:"+strings.ToLower(user.Email), 1)
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+
+				taskDistrebutor.EXPECT().
+					DestributeTaskSendPasswordResetEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(fmt.Errorf("redis is down"))
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsRequested())
+			},
+		}, {
+			name: "TooManyForEmail",
+			req: &pb.RequestPasswordResetRequest{
+				Email: user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistrebutor *mockwk.MockTaskDistributor) {
+				expectLoginLinkRequest(store, "reset:email:"+strings.ToLower(user.Email), testResetMaxEmails+1)
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				taskDistrebutor.EXPECT().
+					DestributeTaskSendPasswordResetEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.Error(t, err)
+				status, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.ResourceExhausted, status.Code())
+			},
+		}, {
+			name: "BadInputEmail",
+			req: &pb.RequestPasswordResetRequest{
+				Email: "someATexampleDotCom",
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistrebutor *mockwk.MockTaskDistributor) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				taskDistrebutor.EXPECT().
+					DestributeTaskSendPasswordResetEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.Error(t, err)
+				status, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, status.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+
+			taskCtrl := gomock.NewController(t)
+			defer taskCtrl.Finish()
+
+			taskDistributor := mockwk.NewMockTaskDistributor(taskCtrl)
+
+			// build stubs
+			tc.buildStubs(store, taskDistributor)
+
+			// start test server and run gRPC function
+			server := NewTestServer(t, store, taskDistributor)
+			res, err := server.RequestPasswordReset(context.Background(), tc.req)
+
+			tc.checkResponse(t, res, err)
+		})
+
+	}
+}
+
+func TestRequestPasswordResetIpLimitGAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	taskDistributor := mockwk.NewMockTaskDistributor(ctrl)
+
+	email := util.RandomEmail()
+	expectLoginLinkRequest(store, "reset:email:"+strings.ToLower(email), 1)
+	expectLoginLinkRequest(store, "reset:ip:203.0.113.7", testResetIpMaxEmails+1)
+	store.EXPECT().
+		GetUserByEmail(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	server := NewTestServer(t, store, taskDistributor)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 51234},
+	})
+	_, err := callUnary(ctx, server, pb.SimpleBank_RequestPasswordReset_FullMethodName,
+		&pb.RequestPasswordResetRequest{Email: email}, server.RequestPasswordReset)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
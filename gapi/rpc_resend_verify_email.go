@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/worker"
+	"github.com/hibiken/asynq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ResendVerifyEmail send new verification link to the pending email of the user
+// or to the active one while it is not verified
+func (srv *Server) ResendVerifyEmail(ctx context.Context, req *pb.ResendVerifyEmailRequest) (*pb.ResendVerifyEmailResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	user, err := srv.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get user: %s", err)
+	}
+	if user.IsEmailVerified && !user.PendingEmail.Valid {
+		return nil, status.Errorf(codes.FailedPrecondition, "email is already verified")
+	}
+
+	// the request is counted before the task is enqueued, so concurrent
+	// requests can not all pass the limit before the worker sends the emails
+	sent, err := srv.store.AddVerifyEmailResend(ctx, db.AddVerifyEmailResendParams{
+		Username:    user.Username,
+		WindowStart: time.Now().Add(-srv.config.VerifyEmailWindow),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot count verify emails: %s", err)
+	}
+	if srv.config.VerifyEmailMaxResends > 0 && sent > srv.config.VerifyEmailMaxResends {
+		return nil, status.Errorf(codes.ResourceExhausted, "too many verify emails, try again later")
+	}
+
+	err = srv.taskDestributor.DestributeTaskSendVerifyEmail(
+		ctx,
+		&worker.PayloadSendVerifyEmail{
+			Username: user.Username,
+			Email:    user.PendingEmail.String,
+		},
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueCritical),
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot enqueue verify email: %s", err)
+	}
+
+	return &pb.ResendVerifyEmailResponse{IsSent: true}, nil
+}
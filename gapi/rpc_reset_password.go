@@ -0,0 +1,79 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/util"
+	"github.com/dubass83/simplebank/val"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	if violations := validateResetPasswordRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// find the user of the code without using it, so the password is checked
+	// against the user and a rejected password does not burn the code
+	reset, err := srv.store.GetActivePasswordReset(ctx, db.GetActivePasswordResetParams{
+		ID:             req.GetId(),
+		SecretCodeHash: util.HashSecretCode(req.GetSecretCode()),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.InvalidArgument, "reset code is invalid, used or expired")
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get reset code: %s", err)
+	}
+	user, err := srv.store.GetUser(ctx, reset.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get user: %s", err)
+	}
+	errs := srv.passwordPolicy.Validate(req.GetNewPassword(), user.Username, user.Email)
+	if violations := passwordViolations("new_password", errs); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	hashedPassword, err := util.HashPassword(req.GetNewPassword())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
+	}
+
+	result, err := srv.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		ID:             req.GetId(),
+		SecretCodeHash: util.HashSecretCode(req.GetSecretCode()),
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.InvalidArgument, "reset code is invalid, used or expired")
+		}
+		return nil, status.Errorf(codes.Internal, "cannot reset password: %s", err)
+	}
+	srv.revocation.ForgetUser(result.User.Username)
+	log.Info().Str("username", result.User.Username).
+		Int64("revoked_sessions", result.RevokedSessions).Msg("password reset")
+
+	rsp := &pb.ResetPasswordResponse{IsReset: true}
+	return rsp, nil
+}
+
+func validateResetPasswordRequest(req *pb.ResetPasswordRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateVerifyEmailID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	if err := val.ValidateVerifyEmailSecretCode(req.GetSecretCode()); err != nil {
+		violations = append(violations, fieldViolation("secret_code", err))
+	}
+	// rules about the user are checked after the code is found
+	if err := val.ValidatePassword(req.GetNewPassword()); err != nil {
+		violations = append(violations, fieldViolation("new_password", err))
+	}
+	return
+}
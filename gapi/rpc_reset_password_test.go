@@ -0,0 +1,256 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type eqResetPasswordTxParamsMatcher struct {
+	arg      db.ResetPasswordTxParams
+	password string
+}
+
+func (expected eqResetPasswordTxParamsMatcher) Matches(x any) bool {
+	actualArg, ok := x.(db.ResetPasswordTxParams)
+	if !ok {
+		return false
+	}
+	if err := util.CheckPassword(expected.password, actualArg.HashedPassword); err != nil {
+		return false
+	}
+	return expected.arg.ID == actualArg.ID &&
+		expected.arg.SecretCodeHash == actualArg.SecretCodeHash
+}
+
+func (e eqResetPasswordTxParamsMatcher) String() string {
+	return fmt.Sprintf("is argument %v and password %s", e.arg, e.password)
+}
+
+func eqResetPasswordTxParams(arg db.ResetPasswordTxParams, pass string) gomock.Matcher {
+	return eqResetPasswordTxParamsMatcher{arg, pass}
+}
+
+// expectActivePasswordReset find the reset code of the user before it is used
+func expectActivePasswordReset(store *mockdb.MockStore, id int64, secretCode string, user db.User) {
+	store.EXPECT().
+		GetActivePasswordReset(gomock.Any(), gomock.Eq(db.GetActivePasswordResetParams{
+			ID:             id,
+			SecretCodeHash: util.HashSecretCode(secretCode),
+		})).
+		Times(1).
+		Return(db.PasswordReset{ID: id, Username: user.Username}, nil)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(user, nil)
+}
+
+func TestResetPasswordGAPI(t *testing.T) {
+	user, _ := randomUser()
+	id := util.RandomInt(1, 100)
+	secretCode := util.RandomString(32)
+	newPassword := util.RandomString(10)
+
+	testCases := []struct {
+		name          string
+		req           *pb.ResetPasswordRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *pb.ResetPasswordResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.ResetPasswordRequest{
+				Id:          id,
+				SecretCode:  secretCode,
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectActivePasswordReset(store, id, secretCode, user)
+				arg := db.ResetPasswordTxParams{
+					ID:             id,
+					SecretCodeHash: util.HashSecretCode(secretCode),
+				}
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), eqResetPasswordTxParams(arg, newPassword)).
+					Times(1).
+					Return(db.ResetPasswordTxResult{User: user, RevokedSessions: 2}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsReset())
+			},
+		}, {
+			name: "InvalidCode",
+			req: &pb.ResetPasswordRequest{
+				Id:          id,
+				SecretCode:  secretCode,
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetActivePasswordReset(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PasswordReset{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Error(t, err)
+				status, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, status.Code())
+			},
+		}, {
+			name: "InternalError",
+			req: &pb.ResetPasswordRequest{
+				Id:          id,
+				SecretCode:  secretCode,
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectActivePasswordReset(store, id, secretCode, user)
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ResetPasswordTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Error(t, err)
+				status, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, status.Code())
+			},
+		}, {
+			name: "CodeUsedConcurrently",
+			req: &pb.ResetPasswordRequest{
+				Id:          id,
+				SecretCode:  secretCode,
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectActivePasswordReset(store, id, secretCode, user)
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ResetPasswordTxResult{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Error(t, err)
+				status, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, status.Code())
+			},
+		}, {
+			name: "PasswordContainsUsername",
+			req: &pb.ResetPasswordRequest{
+				Id:          id,
+				SecretCode:  secretCode,
+				NewPassword: "Kettle-" + user.Username + "-drum-42",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectActivePasswordReset(store, id, secretCode, user)
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Error(t, err)
+				status, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, status.Code())
+			},
+		}, {
+			name: "GetResetError",
+			req: &pb.ResetPasswordRequest{
+				Id:          id,
+				SecretCode:  secretCode,
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetActivePasswordReset(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PasswordReset{}, sql.ErrConnDone)
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Error(t, err)
+				status, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, status.Code())
+			},
+		}, {
+			name: "BadInputPassword",
+			req: &pb.ResetPasswordRequest{
+				Id:          id,
+				SecretCode:  secretCode,
+				NewPassword: "short",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Error(t, err)
+				status, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, status.Code())
+			},
+		}, {
+			name: "BadInputSecretCode",
+			req: &pb.ResetPasswordRequest{
+				Id:          id,
+				SecretCode:  "code",
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Error(t, err)
+				status, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, status.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+
+			// build stubs
+			tc.buildStubs(store)
+
+			// start test server and run gRPC function
+			server := NewTestServer(t, store, nil)
+			res, err := server.ResetPassword(context.Background(), tc.req)
+
+			tc.checkResponse(t, res, err)
+		})
+
+	}
+}
@@ -0,0 +1,37 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) RevokeAllOtherSessions(ctx context.Context, req *pb.RevokeAllOtherSessionsRequest) (*pb.RevokeAllOtherSessionsResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	sessionID := uuid.UUID(payload.SessionID)
+	if sessionID == uuid.Nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "access token is not bound to a session, login again")
+	}
+
+	revoked, err := srv.store.BlockOtherSessions(ctx, db.BlockOtherSessionsParams{
+		Username: payload.Username,
+		ID:       sessionID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot block sessions: %s", err)
+	}
+	srv.revocation.ForgetUser(payload.Username)
+
+	rsp := &pb.RevokeAllOtherSessionsResponse{
+		RevokedSessions: revoked,
+	}
+	return rsp, nil
+}
@@ -0,0 +1,100 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRevokeAllOtherSessionsGAPI(t *testing.T) {
+	user, _ := randomUser()
+	current := randomUserSession(user.Username)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.RevokeAllOtherSessionsResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.BlockOtherSessionsParams{
+					Username: user.Username,
+					ID:       current.ID,
+				}
+				store.EXPECT().
+					BlockOtherSessions(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(int64(3), nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildSessionContext(t, tokenMaker, current, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeAllOtherSessionsResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(3), res.GetRevokedSessions())
+			},
+		}, {
+			name: "NoSessionInToken",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockOtherSessions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeAllOtherSessionsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		}, {
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockOtherSessions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildSessionContext(t, tokenMaker, current, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeAllOtherSessionsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_RevokeAllOtherSessions_FullMethodName, &pb.RevokeAllOtherSessionsRequest{}, server.RevokeAllOtherSessions)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) RevokeApiKey(ctx context.Context, req *pb.RevokeApiKeyRequest) (*pb.RevokeApiKeyResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if violations := validateRevokeApiKeyRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	apiKey, err := srv.store.GetApiKey(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "api key not found")
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get api key: %s", err)
+	}
+
+	if apiKey.Owner != payload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "user: %s not allowed to revoke api key of another user", payload.Username)
+	}
+
+	apiKey, err = srv.store.RevokeApiKey(ctx, apiKey.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot revoke api key: %s", err)
+	}
+
+	rsp := &pb.RevokeApiKeyResponse{
+		ApiKey: convertApiKey(apiKey),
+	}
+	return rsp, nil
+}
+
+func validateRevokeApiKeyRequest(req *pb.RevokeApiKeyRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateAccountId(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return
+}
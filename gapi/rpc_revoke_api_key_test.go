@@ -0,0 +1,170 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRevokeApiKeyGAPI(t *testing.T) {
+	user, _ := randomUser()
+	other, _ := randomUser()
+	apiKey := randomApiKey(user.Username)
+
+	testCases := []struct {
+		name          string
+		req           *pb.RevokeApiKeyRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.RevokeApiKeyResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.RevokeApiKeyRequest{Id: apiKey.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(apiKey, nil)
+
+				revoked := apiKey
+				revoked.IsRevoked = true
+				store.EXPECT().
+					RevokeApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(revoked, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeApiKeyResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, apiKey.ID, res.GetApiKey().GetId())
+				require.True(t, res.GetApiKey().GetIsRevoked())
+			},
+		}, {
+			name: "InvalidID",
+			req:  &pb.RevokeApiKeyRequest{Id: 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeApiKeyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		}, {
+			name: "NotFound",
+			req:  &pb.RevokeApiKeyRequest{Id: apiKey.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(db.ApiKey{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					RevokeApiKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeApiKeyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		}, {
+			name: "OtherOwner",
+			req:  &pb.RevokeApiKeyRequest{Id: apiKey.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(apiKey, nil)
+				store.EXPECT().
+					RevokeApiKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, other.Username, other.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeApiKeyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		}, {
+			name: "InternalError",
+			req:  &pb.RevokeApiKeyRequest{Id: apiKey.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(apiKey, nil)
+				store.EXPECT().
+					RevokeApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(db.ApiKey{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeApiKeyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		}, {
+			name: "NoAuthorization",
+			req:  &pb.RevokeApiKeyRequest{Id: apiKey.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeApiKeyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_RevokeApiKey_FullMethodName, tc.req, server.RevokeApiKey)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
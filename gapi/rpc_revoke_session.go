@@ -0,0 +1,49 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	sessionID, err := uuid.Parse(req.GetSessionId())
+	if err != nil {
+		violations := []*errdetails.BadRequest_FieldViolation{fieldViolation("session_id", err)}
+		return nil, invalidArgumentError(violations)
+	}
+
+	session, err := srv.store.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "session not found")
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get session: %s", err)
+	}
+
+	if session.Username != payload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "user: %s not allowed to revoke session of another user", payload.Username)
+	}
+
+	session, err = srv.store.BlockSession(ctx, session.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot block session: %s", err)
+	}
+	srv.revocation.ForgetSession(session.ID)
+
+	rsp := &pb.RevokeSessionResponse{
+		Session: convertSession(session, uuid.UUID(payload.SessionID)),
+	}
+	return rsp, nil
+}
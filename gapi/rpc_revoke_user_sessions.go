@@ -0,0 +1,29 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) RevokeUserSessions(ctx context.Context, req *pb.RevokeUserSessionsRequest) (*pb.RevokeUserSessionsResponse, error) {
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations := []*errdetails.BadRequest_FieldViolation{fieldViolation("username", err)}
+		return nil, invalidArgumentError(violations)
+	}
+
+	revoked, err := srv.store.BlockUserSessions(ctx, req.GetUsername())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot block sessions: %s", err)
+	}
+	srv.revocation.ForgetUser(req.GetUsername())
+
+	rsp := &pb.RevokeUserSessionsResponse{
+		RevokedSessions: revoked,
+	}
+	return rsp, nil
+}
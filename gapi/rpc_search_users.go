@@ -0,0 +1,111 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// likeEscaper escape wildcards of the LIKE pattern, _ is allowed in usernames
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (srv *Server) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	if violations := validateSearchUsersRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	filter := db.CountUsersParams{
+		UsernamePrefix: pgtype.Text{
+			String: likeEscaper.Replace(req.GetUsernamePrefix()),
+			Valid:  req.GetUsernamePrefix() != "",
+		},
+		Email: pgtype.Text{
+			String: req.GetEmail(),
+			Valid:  req.GetEmail() != "",
+		},
+		FullName: pgtype.Text{
+			String: req.GetFullName(),
+			Valid:  req.GetFullName() != "",
+		},
+		IsEmailVerified: pgtype.Bool{
+			Bool:  req.GetIsEmailVerified(),
+			Valid: req.IsEmailVerified != nil,
+		},
+	}
+	if req.GetCreatedFrom() != nil {
+		filter.CreatedFrom = pgtype.Timestamptz{Time: req.GetCreatedFrom().AsTime(), Valid: true}
+	}
+	if req.GetCreatedTo() != nil {
+		filter.CreatedTo = pgtype.Timestamptz{Time: req.GetCreatedTo().AsTime(), Valid: true}
+	}
+
+	// one more row is fetched to know if there is the next page
+	users, err := srv.store.SearchUsers(ctx, db.SearchUsersParams{
+		UsernamePrefix:  filter.UsernamePrefix,
+		Email:           filter.Email,
+		FullName:        filter.FullName,
+		IsEmailVerified: filter.IsEmailVerified,
+		CreatedFrom:     filter.CreatedFrom,
+		CreatedTo:       filter.CreatedTo,
+		AfterUsername:   req.GetCursor(),
+		Limit:           req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot search users: %s", err)
+	}
+
+	total, err := srv.store.CountUsers(ctx, filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot count users: %s", err)
+	}
+
+	rsp := &pb.SearchUsersResponse{
+		TotalCount: total,
+	}
+	if len(users) > int(req.GetPageSize()) {
+		users = users[:req.GetPageSize()]
+		rsp.NextCursor = users[len(users)-1].Username
+	}
+	rsp.Users = convertUsers(users)
+	return rsp, nil
+}
+
+func validateSearchUsersRequest(req *pb.SearchUsersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetUsernamePrefix() != "" {
+		if err := val.ValidateUsernamePrefix(req.GetUsernamePrefix()); err != nil {
+			violations = append(violations, fieldViolation("username_prefix", err))
+		}
+	}
+	if req.GetEmail() != "" {
+		if err := val.ValidateEmail(req.GetEmail()); err != nil {
+			violations = append(violations, fieldViolation("email", err))
+		}
+	}
+	if req.GetFullName() != "" {
+		if err := val.ValidateFullname(req.GetFullName()); err != nil {
+			violations = append(violations, fieldViolation("full_name", err))
+		}
+	}
+	if req.GetCreatedFrom() != nil && req.GetCreatedTo() != nil {
+		if !req.GetCreatedFrom().AsTime().Before(req.GetCreatedTo().AsTime()) {
+			violations = append(violations, fieldViolation("created_to", fmt.Errorf("must be after created_from")))
+		}
+	}
+	if req.GetCursor() != "" {
+		if err := val.ValidateUsername(req.GetCursor()); err != nil {
+			violations = append(violations, fieldViolation("cursor", err))
+		}
+	}
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	return
+}
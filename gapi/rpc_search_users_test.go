@@ -0,0 +1,208 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSearchUsersGAPI(t *testing.T) {
+	user, _ := randomUser()
+	banker, _ := randomUser()
+	banker.Role = util.BankerRole
+
+	n := 6
+	users := make([]db.User, n)
+	for i := range users {
+		users[i], _ = randomUser()
+	}
+	verified := true
+	createdFrom := time.Now().Add(-time.Hour)
+	createdTo := time.Now()
+
+	testCases := []struct {
+		name          string
+		req           *pb.SearchUsersRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.SearchUsersResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.SearchUsersRequest{
+				UsernamePrefix:  "us_r",
+				IsEmailVerified: &verified,
+				CreatedFrom:     timestamppb.New(createdFrom),
+				CreatedTo:       timestamppb.New(createdTo),
+				PageSize:        int32(n - 1),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				filter := db.CountUsersParams{
+					UsernamePrefix:  pgtype.Text{String: `us\_r`, Valid: true},
+					IsEmailVerified: pgtype.Bool{Bool: true, Valid: true},
+					CreatedFrom:     pgtype.Timestamptz{Time: createdFrom.UTC(), Valid: true},
+					CreatedTo:       pgtype.Timestamptz{Time: createdTo.UTC(), Valid: true},
+				}
+				arg := db.SearchUsersParams{
+					UsernamePrefix:  filter.UsernamePrefix,
+					IsEmailVerified: filter.IsEmailVerified,
+					CreatedFrom:     filter.CreatedFrom,
+					CreatedTo:       filter.CreatedTo,
+					Limit:           int32(n),
+				}
+				store.EXPECT().
+					SearchUsers(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(users, nil)
+				store.EXPECT().
+					CountUsers(gomock.Any(), gomock.Eq(filter)).
+					Times(1).
+					Return(int64(42), nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.SearchUsersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetUsers(), n-1)
+				require.Equal(t, users[n-2].Username, res.GetNextCursor())
+				require.Equal(t, int64(42), res.GetTotalCount())
+			},
+		}, {
+			name: "LastPage",
+			req:  &pb.SearchUsersRequest{Email: user.Email, Cursor: users[0].Username, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchUsers(gomock.Any(), gomock.Eq(db.SearchUsersParams{
+						Email:         pgtype.Text{String: user.Email, Valid: true},
+						AfterUsername: users[0].Username,
+						Limit:         6,
+					})).
+					Times(1).
+					Return([]db.User{user}, nil)
+				store.EXPECT().
+					CountUsers(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.SearchUsersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetUsers(), 1)
+				require.Equal(t, user.Username, res.GetUsers()[0].GetUsername())
+				require.Empty(t, res.GetNextCursor())
+				require.Equal(t, int64(1), res.GetTotalCount())
+			},
+		}, {
+			name: "NotBanker",
+			req:  &pb.SearchUsersRequest{PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchUsers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.SearchUsersResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		}, {
+			name: "InvalidCreatedRange",
+			req: &pb.SearchUsersRequest{
+				CreatedFrom: timestamppb.New(createdTo),
+				CreatedTo:   timestamppb.New(createdFrom),
+				PageSize:    5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchUsers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.SearchUsersResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		}, {
+			name: "InvalidUsernamePrefix",
+			req:  &pb.SearchUsersRequest{UsernamePrefix: "us%", PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchUsers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.SearchUsersResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		}, {
+			name: "InvalidPageSize",
+			req:  &pb.SearchUsersRequest{PageSize: 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchUsers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.SearchUsersResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		}, {
+			name: "InternalError",
+			req:  &pb.SearchUsersRequest{PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchUsers(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+				store.EXPECT().
+					CountUsers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.SearchUsersResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_SearchUsers_FullMethodName, tc.req, server.SearchUsers)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (srv *Server) StartImpersonation(ctx context.Context, req *pb.StartImpersonationRequest) (*pb.StartImpersonationResponse, error) {
+	actor, err := authPayload(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if violations := validateStartImpersonationRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	if req.GetUsername() == actor.Username {
+		violations := []*errdetails.BadRequest_FieldViolation{fieldViolation("username", fmt.Errorf("can not impersonate yourself"))}
+		return nil, invalidArgumentError(violations)
+	}
+	// the token lives only while the login session of the banker does
+	if actor.SessionID.IsNil() {
+		return nil, status.Errorf(codes.PermissionDenied, "impersonation requires the token of the login session")
+	}
+
+	user, err := srv.store.GetUser(ctx, req.GetUsername())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get user: %s", err)
+	}
+
+	// staff users are not impersonated, the token would grant their permissions to the actor
+	permissions, err := srv.store.GetUserPermissions(ctx, user.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get user permissions: %s", err)
+	}
+	if len(permissions) > 0 {
+		return nil, status.Errorf(codes.PermissionDenied, "user: %s has role %s which can not be impersonated", user.Username, user.Role)
+	}
+
+	accessToken, payload, err := srv.tokenMaker.CreateToken(
+		user.Username,
+		user.Role,
+		srv.config.ImpersonationDuration,
+		token.WithActor(actor.Username),
+		token.WithSessionID(actor.SessionID),
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create access token: %s", err)
+	}
+
+	// the token is not returned until its start is audited
+	err = srv.recordImpersonationEvent(ctx, payload, pb.SimpleBank_StartImpersonation_FullMethodName, true, req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.StartImpersonationResponse{
+		AccessToken:      accessToken,
+		AccessTokenExpAt: timestamppb.New(payload.ExpiredAt),
+		User:             convertUser(user),
+	}
+	return rsp, nil
+}
+
+func validateStartImpersonationRequest(req *pb.StartImpersonationRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+	if err := val.ValidateImpersonationReason(req.GetReason()); err != nil {
+		violations = append(violations, fieldViolation("reason", err))
+	}
+	return
+}
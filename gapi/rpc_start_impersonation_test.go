@@ -0,0 +1,240 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	gofrsuuid "github.com/gofrs/uuid/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStartImpersonationGAPI(t *testing.T) {
+	user, _ := randomUser()
+	banker, _ := randomUser()
+	banker.Role = util.BankerRole
+	reason := "customer support ticket 4242"
+	bankerSessionID := gofrsuuid.Must(gofrsuuid.NewV4())
+
+	testCases := []struct {
+		name          string
+		req           *pb.StartImpersonationRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, tokenMaker token.Maker, res *pb.StartImpersonationResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.StartImpersonationRequest{Username: user.Username, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return([]string{}, nil)
+				store.EXPECT().
+					CreateImpersonationEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateImpersonationEventParams) (db.ImpersonationEvent, error) {
+						require.Equal(t, banker.Username, arg.Actor)
+						require.Equal(t, user.Username, arg.Subject)
+						require.Equal(t, pb.SimpleBank_StartImpersonation_FullMethodName, arg.Method)
+						require.True(t, arg.Allowed)
+						require.Equal(t, reason, arg.Reason)
+						return db.ImpersonationEvent{}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute, token.WithSessionID(bankerSessionID))
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, res *pb.StartImpersonationResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, user.Username, res.GetUser().GetUsername())
+				require.WithinDuration(t, time.Now().Add(time.Minute*15), res.GetAccessTokenExpAt().AsTime(), time.Second)
+
+				payload, err := tokenMaker.VerifyToken(res.GetAccessToken())
+				require.NoError(t, err)
+				require.Equal(t, user.Username, payload.Username)
+				require.Equal(t, user.Role, payload.Role)
+				require.Equal(t, banker.Username, payload.Actor)
+				require.Equal(t, bankerSessionID, payload.SessionID)
+			},
+		}, {
+			name: "NoSession",
+			req:  &pb.StartImpersonationRequest{Username: user.Username, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateImpersonationEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, res *pb.StartImpersonationResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		}, {
+			name: "NotBanker",
+			req:  &pb.StartImpersonationRequest{Username: banker.Username, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateImpersonationEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, res *pb.StartImpersonationResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		}, {
+			name: "ImpersonateSelf",
+			req:  &pb.StartImpersonationRequest{Username: banker.Username, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, res *pb.StartImpersonationResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		}, {
+			name: "ImpersonateStaff",
+			req:  &pb.StartImpersonationRequest{Username: user.Username, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return([]string{util.PermissionUsersUnlock}, nil)
+				store.EXPECT().
+					CreateImpersonationEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute, token.WithSessionID(bankerSessionID))
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, res *pb.StartImpersonationResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		}, {
+			name: "Impersonated",
+			req:  &pb.StartImpersonationRequest{Username: user.Username, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImpersonationEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateImpersonationEventParams) (db.ImpersonationEvent, error) {
+						require.False(t, arg.Allowed)
+						return db.ImpersonationEvent{}, nil
+					})
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute, token.WithActor(util.RandomOwner()))
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, res *pb.StartImpersonationResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		}, {
+			name: "ShortReason",
+			req:  &pb.StartImpersonationRequest{Username: user.Username, Reason: "help"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, res *pb.StartImpersonationResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		}, {
+			name: "UserNotFound",
+			req:  &pb.StartImpersonationRequest{Username: user.Username, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					CreateImpersonationEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute, token.WithSessionID(bankerSessionID))
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, res *pb.StartImpersonationResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		}, {
+			name: "AuditError",
+			req:  &pb.StartImpersonationRequest{Username: user.Username, Reason: reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return([]string{}, nil)
+				store.EXPECT().
+					CreateImpersonationEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImpersonationEvent{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return BuildContext(t, tokenMaker, banker.Username, banker.Role, time.Minute, token.WithSessionID(bankerSessionID))
+			},
+			checkResponse: func(t *testing.T, tokenMaker token.Maker, res *pb.StartImpersonationResponse, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+				require.Empty(t, res.GetAccessToken())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_StartImpersonation_FullMethodName, tc.req, server.StartImpersonation)
+			tc.checkResponse(t, server.tokenMaker, res, err)
+		})
+	}
+}
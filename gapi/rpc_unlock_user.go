@@ -0,0 +1,33 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) UnlockUser(ctx context.Context, req *pb.UnlockUserRequest) (*pb.UnlockUserResponse, error) {
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations := []*errdetails.BadRequest_FieldViolation{fieldViolation("username", err)}
+		return nil, invalidArgumentError(violations)
+	}
+
+	user, err := srv.store.UnlockUserTx(ctx, req.GetUsername())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "cannot unlock user: %s", err)
+	}
+
+	rsp := &pb.UnlockUserResponse{
+		User: convertUser(user),
+	}
+	return rsp, nil
+}
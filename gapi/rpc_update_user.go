@@ -17,7 +17,7 @@ import (
 )
 
 func (srv *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
-	payload, err := srv.authorizeUser(ctx)
+	payload, err := authPayload(ctx)
 	if err != nil {
 		return nil, unauthenticatedError(err)
 	}
@@ -26,21 +26,45 @@ func (srv *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*
 		return nil, invalidArgumentError(violation)
 	}
 
-	if payload.Role != util.BankerRole && payload.Username != req.GetUsername() {
-		err := fmt.Errorf("user: %s is not authorized to make changes in another user: %s",
-			payload.Username,
-			req.GetUsername(),
-		)
-		return nil, unauthenticatedError(err)
+	if payload.Username != req.GetUsername() {
+		ok, err := srv.permissions.HasPermission(ctx, payload, util.PermissionUsersUpdateAny)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot check permission: %s", err)
+		}
+		if !ok {
+			err := fmt.Errorf("user: %s is not authorized to make changes in another user: %s",
+				payload.Username,
+				req.GetUsername(),
+			)
+			return nil, unauthenticatedError(err)
+		}
 	}
 
-	arg := db.UpdateUserParams{
-		Username: req.GetUsername(),
-		FullName: pgtype.Text{
-			String: req.GetFullName(),
-			Valid:  req.FullName != nil,
+	if req.Password != nil {
+		// the request may not carry the email, check the password against the stored one
+		user, err := srv.store.GetUser(ctx, req.GetUsername())
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, status.Errorf(codes.NotFound, "cannot find user in database: %s", err)
+			}
+			return nil, status.Errorf(codes.Internal, "cannot get user: %s", err)
+		}
+		errs := srv.passwordPolicy.Validate(req.GetPassword(), user.Username, user.Email, req.GetEmail())
+		if violations := passwordViolations("password", errs); violations != nil {
+			return nil, invalidArgumentError(violations)
+		}
+	}
+
+	// the new email is only swapped in after verification
+	arg := db.UpdateUserTxParams{
+		UpdateUserParams: db.UpdateUserParams{
+			Username: req.GetUsername(),
+			FullName: pgtype.Text{
+				String: req.GetFullName(),
+				Valid:  req.FullName != nil,
+			},
 		},
-		Email: pgtype.Text{
+		NewEmail: pgtype.Text{
 			String: req.GetEmail(),
 			Valid:  req.Email != nil,
 		},
@@ -61,13 +85,16 @@ func (srv *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*
 		}
 	}
 
-	user, err := srv.store.UpdateUser(ctx, arg)
+	user, err := srv.store.UpdateUserTx(ctx, arg)
 	if err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
 			return nil, status.Errorf(codes.NotFound, "cannot find user in database: %s", err)
 		}
 		return nil, status.Errorf(codes.Internal, "cannot Update user: %s", err)
 	}
+	if arg.PasswordChangedAt.Valid {
+		srv.revocation.ForgetUser(user.Username)
+	}
 
 	rsp := &pb.UpdateUserResponse{
 		User: convertUser(user),
@@ -3,6 +3,7 @@ package gapi
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,9 +12,11 @@ import (
 	"github.com/dubass83/simplebank/pb"
 	"github.com/dubass83/simplebank/token"
 	"github.com/dubass83/simplebank/util"
+	"github.com/dubass83/simplebank/val"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -39,28 +42,34 @@ func TestUpdateUserGAPI(t *testing.T) {
 				Email:    &newEmail,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.UpdateUserParams{
-					Username: user.Username,
-					FullName: pgtype.Text{
-						String: newName,
-						Valid:  true,
+				arg := db.UpdateUserTxParams{
+					UpdateUserParams: db.UpdateUserParams{
+						Username: user.Username,
+						FullName: pgtype.Text{
+							String: newName,
+							Valid:  true,
+						},
 					},
-					Email: pgtype.Text{
+					NewEmail: pgtype.Text{
 						String: newEmail,
 						Valid:  true,
 					},
 				}
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Eq(arg)).
+					UpdateUserTx(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
 					Return(db.User{
 						Username:          user.Username,
 						HashedPassword:    user.HashedPassword,
 						FullName:          newName,
-						Email:             newEmail,
+						Email:             user.Email,
 						PasswordChangedAt: user.PasswordChangedAt,
 						CreatedAt:         user.PasswordChangedAt,
 						IsEmailVerified:   user.IsEmailVerified,
+						PendingEmail: pgtype.Text{
+							String: newEmail,
+							Valid:  true,
+						},
 					}, nil)
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
@@ -72,7 +81,8 @@ func TestUpdateUserGAPI(t *testing.T) {
 				updateDbUser := res.GetUser()
 				require.Equal(t, user.Username, updateDbUser.Username)
 				require.Equal(t, newName, updateDbUser.FullName)
-				require.Equal(t, newEmail, updateDbUser.Email)
+				require.Equal(t, user.Email, updateDbUser.Email)
+				require.Equal(t, newEmail, updateDbUser.PendingEmail)
 
 			},
 		}, {
@@ -83,19 +93,21 @@ func TestUpdateUserGAPI(t *testing.T) {
 				Email:    &newEmail,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.UpdateUserParams{
-					Username: user.Username,
-					FullName: pgtype.Text{
-						String: newName,
-						Valid:  true,
+				arg := db.UpdateUserTxParams{
+					UpdateUserParams: db.UpdateUserParams{
+						Username: user.Username,
+						FullName: pgtype.Text{
+							String: newName,
+							Valid:  true,
+						},
 					},
-					Email: pgtype.Text{
+					NewEmail: pgtype.Text{
 						String: newEmail,
 						Valid:  true,
 					},
 				}
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Eq(arg)).
+					UpdateUserTx(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
 					Return(db.User{}, sql.ErrConnDone)
 			},
@@ -117,7 +129,7 @@ func TestUpdateUserGAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
@@ -138,7 +150,7 @@ func TestUpdateUserGAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
@@ -160,7 +172,7 @@ func TestUpdateUserGAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
@@ -182,7 +194,7 @@ func TestUpdateUserGAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
@@ -202,19 +214,21 @@ func TestUpdateUserGAPI(t *testing.T) {
 				Email:    &newEmail,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.UpdateUserParams{
-					Username: user.Username,
-					FullName: pgtype.Text{
-						String: newName,
-						Valid:  true,
+				arg := db.UpdateUserTxParams{
+					UpdateUserParams: db.UpdateUserParams{
+						Username: user.Username,
+						FullName: pgtype.Text{
+							String: newName,
+							Valid:  true,
+						},
 					},
-					Email: pgtype.Text{
+					NewEmail: pgtype.Text{
 						String: newEmail,
 						Valid:  true,
 					},
 				}
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Eq(arg)).
+					UpdateUserTx(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
 					Return(db.User{}, db.ErrRecordNotFound)
 			},
@@ -244,10 +258,89 @@ func TestUpdateUserGAPI(t *testing.T) {
 			server := NewTestServer(t, store, nil)
 			// create context
 			ctx := tc.buildContext(t, server.tokenMaker)
-			res, err := server.UpdateUser(ctx, tc.req)
+			res, err := callUnary(ctx, server, pb.SimpleBank_UpdateUser_FullMethodName, tc.req, server.UpdateUser)
 
 			tc.checkResponse(t, res, err)
 		})
 
 	}
 }
+
+func TestUpdateUserPasswordPolicyGAPI(t *testing.T) {
+	user, _ := randomUser()
+	newEmail := util.RandomEmail()
+	emailLocal, _, _ := strings.Cut(user.Email, "@")
+	newEmailLocal, _, _ := strings.Cut(newEmail, "@")
+
+	testCases := []struct {
+		name       string
+		password   string
+		email      *string
+		violations int
+	}{
+		{
+			name:       "Strong",
+			password:   "correct-Horse-battery-5taple",
+			violations: 0,
+		},
+		{
+			// the request does not carry the email, the stored one is checked
+			name:       "ContainsStoredEmail",
+			password:   "Kettle-" + emailLocal + "-drum-42-zebra",
+			violations: 1,
+		},
+		{
+			name:       "ContainsNewEmail",
+			password:   "Kettle-" + newEmailLocal + "-drum-42-zebra",
+			email:      &newEmail,
+			violations: 1,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUser(gomock.Any(), gomock.Eq(user.Username)).
+				Times(1).
+				Return(user, nil)
+			times := 0
+			if tc.violations == 0 {
+				times = 1
+			}
+			store.EXPECT().
+				UpdateUserTx(gomock.Any(), gomock.Any()).
+				Times(times).
+				Return(user, nil)
+
+			server := NewTestServer(t, store, nil)
+			server.passwordPolicy = val.PasswordPolicy{MinClasses: 3}
+			ctx := BuildContext(t, server.tokenMaker, user.Username, user.Role, time.Minute)
+			req := &pb.UpdateUserRequest{
+				Username: user.Username,
+				Password: &tc.password,
+				Email:    tc.email,
+			}
+			_, err := callUnary(ctx, server, pb.SimpleBank_UpdateUser_FullMethodName, req, server.UpdateUser)
+			if tc.violations == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			require.Equal(t, codes.InvalidArgument, st.Code())
+			require.Len(t, st.Details(), 1)
+			badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+			require.True(t, ok)
+			require.Len(t, badRequest.GetFieldViolations(), tc.violations)
+			for _, violation := range badRequest.GetFieldViolations() {
+				require.Equal(t, "password", violation.GetField())
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package gapi
 
 import (
 	"context"
+	"errors"
 
 	db "github.com/dubass83/simplebank/db/sqlc"
 	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/util"
 	"github.com/dubass83/simplebank/val"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
@@ -17,11 +19,17 @@ func (srv *Server) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest)
 	}
 
 	arg := db.VerifyEmailTxParams{
-		ID:         req.Id,
-		SecretCode: req.SecretCode,
+		ID:             req.Id,
+		SecretCodeHash: util.HashSecretCode(req.GetSecretCode()),
 	}
 	resultTx, err := srv.store.VerifyEmailTx(ctx, arg)
 	if err != nil {
+		if errors.Is(err, db.ErrVerifyEmailOutdated) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot validate user email: %s", err)
+		}
+		if db.ErrorCode(err) == db.UniqueViolation {
+			return nil, status.Errorf(codes.AlreadyExists, "email is already used: %s", err)
+		}
 		return nil, status.Errorf(codes.Internal, "cannot validate user email: %s", err)
 	}
 
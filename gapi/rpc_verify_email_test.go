@@ -35,8 +35,8 @@ func TestVerifyEmailGAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				arg := db.VerifyEmailTxParams{
-					ID:         id,
-					SecretCode: secretCode,
+					ID:             id,
+					SecretCodeHash: util.HashSecretCode(secretCode),
 				}
 				user = db.User{
 					Username:          user.Username,
@@ -48,13 +48,13 @@ func TestVerifyEmailGAPI(t *testing.T) {
 					IsEmailVerified:   true,
 				}
 				verifyEmail := db.VerifyEmail{
-					ID:         id,
-					Username:   user.Username,
-					Email:      user.Email,
-					SecretCode: secretCode,
-					IsUsed:     true,
-					CreatedAt:  time.Now(),
-					ExpiredAt:  time.Now().Add(time.Minute),
+					ID:             id,
+					Username:       user.Username,
+					Email:          user.Email,
+					SecretCodeHash: util.HashSecretCode(secretCode),
+					IsUsed:         true,
+					CreatedAt:      time.Now(),
+					ExpiredAt:      time.Now().Add(time.Minute),
 				}
 				res := db.VerifyEmailTxResult{
 					User:        user,
@@ -78,8 +78,8 @@ func TestVerifyEmailGAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				arg := db.VerifyEmailTxParams{
-					ID:         id,
-					SecretCode: secretCode,
+					ID:             id,
+					SecretCodeHash: util.HashSecretCode(secretCode),
 				}
 				store.EXPECT().
 					VerifyEmailTx(gomock.Any(), gomock.Eq(arg)).
@@ -93,6 +93,44 @@ func TestVerifyEmailGAPI(t *testing.T) {
 				require.True(t, ok)
 				require.Equal(t, st.Code(), codes.Internal)
 			},
+		}, {
+			name: "OutdatedEmail",
+			req: &pb.VerifyEmailRequest{
+				Id:         id,
+				SecretCode: secretCode,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VerifyEmailTxResult{}, db.ErrVerifyEmailOutdated)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyEmailResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.FailedPrecondition)
+			},
+		}, {
+			name: "EmailAlreadyUsed",
+			req: &pb.VerifyEmailRequest{
+				Id:         id,
+				SecretCode: secretCode,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VerifyEmailTxResult{}, db.ErrUniqueViolation)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyEmailResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.AlreadyExists)
+			},
 		}, {
 			name: "BadInputID",
 			req: &pb.VerifyEmailRequest{
@@ -0,0 +1,118 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/totp"
+	"github.com/dubass83/simplebank/val"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) VerifyTotpLogin(ctx context.Context, req *pb.VerifyTotpLoginRequest) (*pb.LoginUserResponse, error) {
+	if violations := validateVerifyTotpLoginRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	payload, err := srv.tokenMaker.VerifyToken(req.GetChallengeToken())
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	if payload.Purpose != token.PurposeTotpChallenge {
+		return nil, unauthenticatedError(fmt.Errorf("token is not a totp challenge"))
+	}
+
+	mtdt := srv.extractMetadata(ctx)
+
+	// wrong codes count as failed logins, so the code can not be guessed
+	failures, err := srv.throttleLogin(ctx, payload.Username, mtdt)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := srv.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get user: %s", err)
+	}
+	if !user.TotpEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is not enabled")
+	}
+
+	if isUserLocked(user) {
+		if err := srv.recordLoginEvent(ctx, user.Username, mtdt, false, loginReasonLocked); err != nil {
+			return nil, err
+		}
+		return nil, unauthenticatedError(fmt.Errorf("too many failed logins, try again later"))
+	}
+
+	// the challenge is exchanged for the session only once, it is claimed
+	// before the code in one transaction, so a replayed challenge burns nothing
+	arg := db.VerifyTotpLoginTxParams{
+		ChallengeID: uuid.UUID(payload.ID),
+		Username:    user.Username,
+	}
+	if req.GetTotpCode() != "" {
+		step, ok := totp.ValidateStep(req.GetTotpCode(), user.TotpSecret.String, time.Now())
+		if !ok {
+			if err := srv.recordFailedLogin(ctx, user.Username, mtdt, failures, loginReasonInvalidTotp); err != nil {
+				return nil, err
+			}
+			return nil, unauthenticatedError(fmt.Errorf("totp code does not match or was already used"))
+		}
+		arg.Step = step
+	} else {
+		arg.RecoveryCodeHash = totp.HashRecoveryCode(req.GetRecoveryCode())
+	}
+
+	err = srv.store.VerifyTotpLoginTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrTotpChallengeUsed):
+			return nil, unauthenticatedError(fmt.Errorf("challenge token was already used"))
+		case errors.Is(err, db.ErrTotpCodeUsed):
+			if err := srv.recordFailedLogin(ctx, user.Username, mtdt, failures, loginReasonInvalidTotp); err != nil {
+				return nil, err
+			}
+			return nil, unauthenticatedError(fmt.Errorf("totp code or recovery code is invalid or already used"))
+		}
+		return nil, status.Errorf(codes.Internal, "cannot verify second factor: %s", err)
+	}
+
+	if failures > 0 {
+		err = srv.store.DeleteFailedLogins(ctx, user.Username)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot reset failed logins")
+		}
+	}
+
+	return srv.createLoginSession(ctx, user)
+}
+
+func validateVerifyTotpLoginRequest(req *pb.VerifyTotpLoginRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateRefreshToken(req.GetChallengeToken()); err != nil {
+		violations = append(violations, fieldViolation("challenge_token", err))
+	}
+	switch {
+	case req.GetTotpCode() != "" && req.GetRecoveryCode() != "":
+		violations = append(violations, fieldViolation("recovery_code", fmt.Errorf("only one of totp_code or recovery_code is allowed")))
+	case req.GetTotpCode() != "":
+		if err := val.ValidateTotpCode(req.GetTotpCode()); err != nil {
+			violations = append(violations, fieldViolation("totp_code", err))
+		}
+	case req.GetRecoveryCode() != "":
+		if err := val.ValidateRecoveryCode(req.GetRecoveryCode()); err != nil {
+			violations = append(violations, fieldViolation("recovery_code", err))
+		}
+	default:
+		violations = append(violations, fieldViolation("totp_code", fmt.Errorf("totp_code or recovery_code is required")))
+	}
+	return
+}
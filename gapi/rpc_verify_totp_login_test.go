@@ -0,0 +1,373 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/totp"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestVerifyTotpLoginGAPI(t *testing.T) {
+	user, code := randomTotpUser(t)
+	recoveryCode := "abcde-fghij"
+
+	challengeToken := func(t *testing.T, tokenMaker token.Maker) string {
+		challenge, _, err := tokenMaker.CreateToken(user.Username, user.Role, time.Minute,
+			token.WithPurpose(token.PurposeTotpChallenge))
+		require.NoError(t, err)
+		return challenge
+	}
+
+	testCases := []struct {
+		name          string
+		buildRequest  func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.LoginUserResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildRequest: func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest {
+				return &pb.VerifyTotpLoginRequest{ChallengeToken: challengeToken(t, tokenMaker), TotpCode: code}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginThrottle(store, 0, 0)
+				expectLoginSuccess(store, user.Username)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				expectVerifyTotpLoginTx(store, db.VerifyTotpLoginTxParams{
+					Username: user.Username,
+					Step:     totp.Step(time.Now()),
+				}, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{ID: uuid.New(), Username: user.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+				require.NotEmpty(t, res.GetRefreshToken())
+				require.False(t, res.GetTotpRequired())
+			},
+		}, {
+			name: "RecoveryCode",
+			buildRequest: func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest {
+				return &pb.VerifyTotpLoginRequest{ChallengeToken: challengeToken(t, tokenMaker), RecoveryCode: recoveryCode}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginThrottle(store, 0, 0)
+				expectLoginSuccess(store, user.Username)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				expectVerifyTotpLoginTx(store, db.VerifyTotpLoginTxParams{
+					Username:         user.Username,
+					RecoveryCodeHash: totp.HashRecoveryCode(recoveryCode),
+				}, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{ID: uuid.New(), Username: user.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+			},
+		}, {
+			name: "UsedRecoveryCode",
+			buildRequest: func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest {
+				return &pb.VerifyTotpLoginRequest{ChallengeToken: challengeToken(t, tokenMaker), RecoveryCode: recoveryCode}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginThrottle(store, 0, 0)
+				expectFailedTotp(store, user.Username)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				expectVerifyTotpLoginTx(store, db.VerifyTotpLoginTxParams{
+					Username:         user.Username,
+					RecoveryCodeHash: totp.HashRecoveryCode(recoveryCode),
+				}, db.ErrTotpCodeUsed)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "WrongCode",
+			buildRequest: func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest {
+				return &pb.VerifyTotpLoginRequest{ChallengeToken: challengeToken(t, tokenMaker), TotpCode: wrongTotpCode(code)}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginThrottle(store, 0, 0)
+				expectFailedTotp(store, user.Username)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					VerifyTotpLoginTx(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					LockUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "WrongCodeLocksUser",
+			buildRequest: func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest {
+				return &pb.VerifyTotpLoginRequest{ChallengeToken: challengeToken(t, tokenMaker), TotpCode: wrongTotpCode(code)}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginThrottle(store, 0, testLoginMaxAttempts-1)
+				expectFailedTotp(store, user.Username)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					LockUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		}, {
+			name: "LockedUser",
+			buildRequest: func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest {
+				return &pb.VerifyTotpLoginRequest{ChallengeToken: challengeToken(t, tokenMaker), TotpCode: code}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginThrottle(store, 0, testLoginMaxAttempts)
+				expectLoginEvent(store, user.Username, false, loginReasonLocked)
+
+				locked := user
+				locked.LockedUntil = pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true}
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(locked, nil)
+				store.EXPECT().
+					VerifyTotpLoginTx(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		}, {
+			name: "Throttled",
+			buildRequest: func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest {
+				return &pb.VerifyTotpLoginRequest{ChallengeToken: challengeToken(t, tokenMaker), TotpCode: code}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CountFailedLoginsByClientIp(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(testLoginIpMaxAttempts), nil)
+				expectLoginEvent(store, user.Username, false, loginReasonThrottled)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.ResourceExhausted, status.Code(err))
+			},
+		}, {
+			name: "ReplayedCode",
+			buildRequest: func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest {
+				return &pb.VerifyTotpLoginRequest{ChallengeToken: challengeToken(t, tokenMaker), TotpCode: code}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginThrottle(store, 0, 0)
+				expectFailedTotp(store, user.Username)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				expectVerifyTotpLoginTx(store, db.VerifyTotpLoginTxParams{
+					Username: user.Username,
+					Step:     totp.Step(time.Now()),
+				}, db.ErrTotpCodeUsed)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		}, {
+			name: "UsedChallenge",
+			buildRequest: func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest {
+				return &pb.VerifyTotpLoginRequest{ChallengeToken: challengeToken(t, tokenMaker), RecoveryCode: recoveryCode}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectLoginThrottle(store, 0, 0)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				// the challenge is claimed first, the failed login is not recorded
+				expectVerifyTotpLoginTx(store, db.VerifyTotpLoginTxParams{
+					Username:         user.Username,
+					RecoveryCodeHash: totp.HashRecoveryCode(recoveryCode),
+				}, db.ErrTotpChallengeUsed)
+				store.EXPECT().
+					CreateFailedLogin(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		}, {
+			name: "AccessTokenInsteadOfChallenge",
+			buildRequest: func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest {
+				accessToken, _, err := tokenMaker.CreateToken(user.Username, user.Role, time.Minute)
+				require.NoError(t, err)
+				return &pb.VerifyTotpLoginRequest{ChallengeToken: accessToken, TotpCode: code}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		}, {
+			name: "MissingCode",
+			buildRequest: func(t *testing.T, tokenMaker token.Maker) *pb.VerifyTotpLoginRequest {
+				return &pb.VerifyTotpLoginRequest{ChallengeToken: challengeToken(t, tokenMaker)}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store, nil)
+			res, err := server.VerifyTotpLogin(context.Background(), tc.buildRequest(t, server.tokenMaker))
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+// expectFailedTotp stub the record of the wrong second factor as the failed login
+func expectFailedTotp(store *mockdb.MockStore, username string) {
+	store.EXPECT().
+		CreateFailedLogin(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.FailedLogin{Username: username}, nil)
+	expectLoginEvent(store, username, false, loginReasonInvalidTotp)
+}
+
+// expectVerifyTotpLoginTx stub the exchange of the challenge token for the second factor,
+// the challenge id is taken from the token and only checked to be set
+func expectVerifyTotpLoginTx(store *mockdb.MockStore, arg db.VerifyTotpLoginTxParams, err error) {
+	store.EXPECT().
+		VerifyTotpLoginTx(gomock.Any(), eqVerifyTotpLoginTxParams(arg)).
+		Times(1).
+		Return(err)
+}
+
+type eqVerifyTotpLoginTxParamsMatcher struct {
+	arg db.VerifyTotpLoginTxParams
+}
+
+func (expected eqVerifyTotpLoginTxParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.VerifyTotpLoginTxParams)
+	if !ok || arg.ChallengeID == uuid.Nil {
+		return false
+	}
+	arg.ChallengeID = expected.arg.ChallengeID
+	return arg == expected.arg
+}
+
+func (expected eqVerifyTotpLoginTxParamsMatcher) String() string {
+	return fmt.Sprintf("challenge of %s with step %d or recovery code %s",
+		expected.arg.Username, expected.arg.Step, expected.arg.RecoveryCodeHash)
+}
+
+func eqVerifyTotpLoginTxParams(arg db.VerifyTotpLoginTxParams) gomock.Matcher {
+	return eqVerifyTotpLoginTxParamsMatcher{arg}
+}
+
+// randomTotpUser return user with enabled two-factor authentication and the current code
+func randomTotpUser(t *testing.T) (db.User, string) {
+	user, _ := randomUser()
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	user.TotpSecret = pgtype.Text{String: secret, Valid: true}
+	user.TotpEnabled = true
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	return user, code
+}
@@ -0,0 +1,89 @@
+package gapi
+
+import (
+	"errors"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (srv *Server) WatchAccount(req *pb.WatchAccountRequest, stream pb.SimpleBank_WatchAccountServer) error {
+	ctx := stream.Context()
+
+	payload, err := authPayload(ctx)
+	if err != nil {
+		return unauthenticatedError(err)
+	}
+
+	if violations := validateWatchAccountRequest(req); violations != nil {
+		return invalidArgumentError(violations)
+	}
+
+	account, err := srv.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return status.Errorf(codes.Internal, "cannot get Account: %s", err)
+	}
+
+	if payload.Username != account.Owner {
+		return status.Errorf(
+			codes.PermissionDenied,
+			"user: %s not allowed to watch account ID: %d",
+			payload.Username,
+			req.GetAccountId())
+	}
+
+	// subscribe before the snapshot is read, so no update is lost between them,
+	// events which follow may already be included in the snapshot
+	sub, ok := srv.accountHub.Subscribe(account.ID)
+	if !ok {
+		return status.Errorf(codes.Unavailable, "server is shutting down")
+	}
+	defer srv.accountHub.Unsubscribe(sub)
+
+	account, err = srv.store.GetAccount(ctx, account.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return status.Errorf(codes.Internal, "cannot get Account: %s", err)
+	}
+
+	err = stream.Send(&pb.WatchAccountResponse{
+		Account: convertAccount(account),
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case event, ok := <-sub.Events():
+			if !ok {
+				if srv.accountHub.Lagged(sub) {
+					return status.Errorf(codes.ResourceExhausted, "client is too slow to receive account events")
+				}
+				return status.Errorf(codes.Unavailable, "server is shutting down")
+			}
+			err = stream.Send(convertAccountEvent(event))
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func validateWatchAccountRequest(req *pb.WatchAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateAccountId(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("AccountId", err))
+	}
+	return
+}
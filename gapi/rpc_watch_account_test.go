@@ -0,0 +1,162 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchAccountStream fake server stream which pass sent messages to the channel
+type watchAccountStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *pb.WatchAccountResponse
+}
+
+func (stream *watchAccountStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *watchAccountStream) Send(res *pb.WatchAccountResponse) error {
+	stream.sent <- res
+	return nil
+}
+
+// watchAccount call WatchAccount behind AuthStreamInterceptor like the gRPC server does
+func watchAccount(server *Server, req *pb.WatchAccountRequest, stream *watchAccountStream) error {
+	info := &grpc.StreamServerInfo{FullMethod: pb.SimpleBank_WatchAccount_FullMethodName, IsServerStream: true}
+	return server.AuthStreamInterceptor(server, stream, info, func(_ any, authStream grpc.ServerStream) error {
+		return server.WatchAccount(req, &watchAccountStream{ctx: authStream.Context(), sent: stream.sent})
+	})
+}
+
+func TestWatchAccountGAPI(t *testing.T) {
+	user, _ := randomUser()
+	account := db.Account{
+		ID:        util.RandomInt(1, 1000),
+		Owner:     user.Username,
+		Balance:   util.RandomMoney(),
+		Carrency:  util.USD,
+		CreatedAt: time.Now(),
+	}
+
+	event := db.AccountEvent{
+		AccountID:   account.ID,
+		Owner:       account.Owner,
+		Balance:     account.Balance + 10,
+		Currency:    account.Carrency,
+		EntryID:     1,
+		EntryAmount: 10,
+		CreatedAt:   time.Now(),
+	}
+
+	var server *Server
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().
+			GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+			Times(1).
+			Return(account, nil),
+		// the update lands between the subscription and the snapshot
+		store.EXPECT().
+			GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+			Times(1).
+			DoAndReturn(func(ctx context.Context, id int64) (db.Account, error) {
+				server.accountHub.Publish(event)
+				return account, nil
+			}),
+	)
+
+	server = NewTestServer(t, store, nil)
+	ctx, cancel := context.WithCancel(BuildContext(t, server.tokenMaker, user.Username, user.Role, time.Minute))
+	stream := &watchAccountStream{
+		ctx:  ctx,
+		sent: make(chan *pb.WatchAccountResponse, 1),
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- watchAccount(server, &pb.WatchAccountRequest{AccountId: account.ID}, stream)
+	}()
+
+	snapshot := <-stream.sent
+	require.Equal(t, account.ID, snapshot.GetAccount().GetId())
+	require.Equal(t, account.Balance, snapshot.GetAccount().GetBalance())
+	require.Nil(t, snapshot.GetEntry())
+
+	update := <-stream.sent
+	require.Equal(t, event.Balance, update.GetAccount().GetBalance())
+	require.Equal(t, event.EntryID, update.GetEntry().GetId())
+	require.Equal(t, event.EntryAmount, update.GetEntry().GetAmount())
+
+	// client disconnect must remove subscriber from the hub
+	cancel()
+	err := <-errCh
+	require.Equal(t, codes.Canceled, status.Code(err))
+	require.Empty(t, server.accountHub.subscribers)
+}
+
+func TestWatchAccountShutdownGAPI(t *testing.T) {
+	user, _ := randomUser()
+	account := db.Account{ID: 1, Owner: user.Username, Carrency: util.USD}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+		Times(2).
+		Return(account, nil)
+
+	server := NewTestServer(t, store, nil)
+	stream := &watchAccountStream{
+		ctx:  BuildContext(t, server.tokenMaker, user.Username, user.Role, time.Minute),
+		sent: make(chan *pb.WatchAccountResponse, 1),
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- watchAccount(server, &pb.WatchAccountRequest{AccountId: account.ID}, stream)
+	}()
+	<-stream.sent
+
+	server.CloseAccountHub()
+	err := <-errCh
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestWatchAccountNotOwnerGAPI(t *testing.T) {
+	user, _ := randomUser()
+	otherUser, _ := randomUser()
+	account := db.Account{ID: 1, Owner: otherUser.Username, Carrency: util.USD}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+		Times(1).
+		Return(account, nil)
+
+	server := NewTestServer(t, store, nil)
+	stream := &watchAccountStream{
+		ctx:  BuildContext(t, server.tokenMaker, user.Username, user.Role, time.Minute),
+		sent: make(chan *pb.WatchAccountResponse, 1),
+	}
+
+	err := watchAccount(server, &pb.WatchAccountRequest{AccountId: account.ID}, stream)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.Empty(t, stream.sent)
+}
@@ -1,12 +1,19 @@
 package gapi
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/dubass83/simplebank/apikey"
 	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/fraud"
 	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/permission"
+	"github.com/dubass83/simplebank/refresh"
+	"github.com/dubass83/simplebank/revocation"
 	"github.com/dubass83/simplebank/token"
 	"github.com/dubass83/simplebank/util"
+	"github.com/dubass83/simplebank/val"
 	"github.com/dubass83/simplebank/worker"
 )
 
@@ -16,20 +23,45 @@ type Server struct {
 	store           db.Store
 	tokenMaker      token.Maker
 	taskDestributor worker.TaskDistributor
+	accountHub      *AccountHub
+	fraudEvaluator  fraud.Evaluator
+	revocation      revocation.Checker
+	apiKeys         *apikey.Authenticator
+	refreshTokens   *refresh.Rotator
+	permissions     permission.Checker
+	passwordPolicy  val.PasswordPolicy
 }
 
 // NewServer creates a new gRPC server
-func NewServer(config util.Config, store db.Store, taskDestributor worker.TaskDistributor) (*Server, error) {
-	tokenMaker, err := token.NewJwtMaker(config.TokenString)
+func NewServer(config util.Config, store db.Store, tokenMaker token.Maker, taskDestributor worker.TaskDistributor) (*Server, error) {
+	passwordPolicy, err := val.NewPasswordPolicy(config)
 	if err != nil {
-		return nil, fmt.Errorf("can not create token maker: %w", err)
+		return nil, fmt.Errorf("cannot create password policy: %w", err)
 	}
+
 	server := &Server{
 		config:          config,
 		store:           store,
 		tokenMaker:      tokenMaker,
 		taskDestributor: taskDestributor,
+		accountHub:      NewAccountHub(),
+		fraudEvaluator:  fraud.NewEngine(store, config),
+		revocation:      revocation.NewCachedChecker(store, config.AuthCacheTTL),
+		apiKeys:         apikey.NewAuthenticator(store),
+		permissions:     permission.NewStoreChecker(store),
+		passwordPolicy:  passwordPolicy,
 	}
+	server.refreshTokens = refresh.NewRotator(store, tokenMaker, server.revocation, config.RefreshTokenDuration)
 
 	return server, nil
 }
+
+// RunAccountHub feed WatchAccount streams with account events until context is canceled
+func (srv *Server) RunAccountHub(ctx context.Context) error {
+	return srv.accountHub.Run(ctx, srv.store)
+}
+
+// CloseAccountHub finish all WatchAccount streams, so graceful stop does not wait for them
+func (srv *Server) CloseAccountHub() {
+	srv.accountHub.Close()
+}
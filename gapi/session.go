@@ -0,0 +1,21 @@
+package gapi
+
+import (
+	"errors"
+
+	"github.com/dubass83/simplebank/refresh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// refreshError convert error of the refresh token rotation to the status error
+func refreshError(err error) error {
+	switch {
+	case errors.Is(err, refresh.ErrSessionNotFound):
+		return status.Errorf(codes.NotFound, "session not found")
+	case errors.Is(err, refresh.ErrInvalidToken), errors.Is(err, refresh.ErrTokenReused):
+		return unauthenticatedError(err)
+	default:
+		return status.Errorf(codes.Internal, "%s", err)
+	}
+}
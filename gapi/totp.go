@@ -0,0 +1,28 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/totp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// useTotpCode check the code of the user and mark its time step as used,
+// so the code seen once can not be replayed while it is still valid
+func (srv *Server) useTotpCode(ctx context.Context, user db.User, code string) (bool, error) {
+	step, ok := totp.ValidateStep(code, user.TotpSecret.String, time.Now())
+	if !ok {
+		return false, nil
+	}
+	used, err := srv.store.UseTotpStep(ctx, db.UseTotpStepParams{
+		Username: user.Username,
+		Step:     step,
+	})
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "cannot use totp code: %s", err)
+	}
+	return used > 0, nil
+}
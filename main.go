@@ -15,6 +15,7 @@ import (
 	"github.com/dubass83/simplebank/gapi"
 	"github.com/dubass83/simplebank/mail"
 	"github.com/dubass83/simplebank/pb"
+	"github.com/dubass83/simplebank/token"
 	"github.com/dubass83/simplebank/util"
 	"github.com/dubass83/simplebank/worker"
 	"github.com/golang-migrate/migrate/v4"
@@ -28,6 +29,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -54,6 +56,12 @@ func main() {
 		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
 
+	util.SetPasswordParams(util.PasswordParams{
+		Time:    conf.PasswordArgon2Time,
+		Memory:  conf.PasswordArgon2Memory,
+		Threads: conf.PasswordArgon2Threads,
+	})
+
 	ctx, stop := signal.NotifyContext(context.Background(), interruptSignas...)
 	defer stop()
 
@@ -72,13 +80,22 @@ func main() {
 
 	RedisTaskDestributor := worker.NewRedisTaskDistributor(redisOpts)
 
+	tokenMaker, err := token.NewMaker(conf)
+	if err != nil {
+		log.Fatal().
+			Err(err).
+			Str("method", "main").
+			Msg("cannot create token maker")
+	}
+
 	runDbMigration(conf.MigrationURL, conf.DBSource)
 
 	waitGroup, ctx := errgroup.WithContext(ctx)
 
-	runTaskProcessor(ctx, waitGroup, conf, redisOpts, store)
-	runGateWayServer(ctx, waitGroup, conf, store, RedisTaskDestributor)
-	runGRPCServer(ctx, waitGroup, conf, store, RedisTaskDestributor)
+	runTaskProcessor(ctx, waitGroup, conf, redisOpts, store, RedisTaskDestributor)
+	runOutboxRelay(ctx, waitGroup, conf, store, RedisTaskDestributor)
+	runGateWayServer(ctx, waitGroup, conf, tokenMaker)
+	runGRPCServer(ctx, waitGroup, conf, store, tokenMaker, RedisTaskDestributor)
 
 	err = waitGroup.Wait()
 	if err != nil {
@@ -109,17 +126,8 @@ func runGateWayServer(
 	ctx context.Context,
 	waitGroup *errgroup.Group,
 	conf util.Config,
-	store db.Store,
-	taskDistributor worker.TaskDistributor,
+	tokenMaker token.Maker,
 ) {
-	server, err := gapi.NewServer(conf, store, taskDistributor)
-	if err != nil {
-		log.Fatal().
-			Err(err).
-			Str("method", "main").
-			Msg("cannot create server")
-	}
-
 	jsonOptions := runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
 		MarshalOptions: protojson.MarshalOptions{
 			UseProtoNames: true,
@@ -131,7 +139,10 @@ func runGateWayServer(
 
 	grpcMux := runtime.NewServeMux(jsonOptions)
 
-	err = pb.RegisterSimpleBankHandlerServer(ctx, grpcMux, server)
+	// the gateway calls the gRPC server over the network, so every request
+	// pass the same interceptors as direct gRPC calls
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	err := pb.RegisterSimpleBankHandlerFromEndpoint(ctx, grpcMux, conf.GRPCAddressString, dialOpts)
 	if err != nil {
 		log.Fatal().
 			Err(err).
@@ -141,6 +152,9 @@ func runGateWayServer(
 
 	mux := http.NewServeMux()
 	mux.Handle("/", grpcMux)
+	mux.Handle(gapi.JWKSPath, gapi.JWKSHandler(tokenMaker))
+	mux.Handle(gapi.ResetPasswordPagePath, gapi.ResetPasswordPageHandler())
+	mux.Handle(gapi.LoginLinkPagePath, gapi.LoginLinkPageHandler())
 
 	statikFS, err := fs.New()
 	if err != nil {
@@ -193,6 +207,7 @@ func runTaskProcessor(
 	conf util.Config,
 	redisOpts asynq.RedisClientOpt,
 	store db.Store,
+	taskDistributor worker.TaskDistributor,
 ) {
 	sender := mail.NewMailtrapSender(
 		conf.EmailSenderName,
@@ -200,7 +215,7 @@ func runTaskProcessor(
 		conf.MailtrapLogin,
 		conf.MailtrapPass,
 	)
-	taskProcessor := worker.NewRedisTaskProcessor(redisOpts, store, sender)
+	taskProcessor := worker.NewRedisTaskProcessor(redisOpts, store, sender, taskDistributor)
 	log.Info().Msg("start processing tasks from redis queue")
 	err := taskProcessor.Start()
 	if err != nil {
@@ -219,15 +234,33 @@ func runTaskProcessor(
 	})
 }
 
+// runOutboxRelay publish events from the outbox table to the redis queue
+func runOutboxRelay(
+	ctx context.Context,
+	waitGroup *errgroup.Group,
+	conf util.Config,
+	store db.Store,
+	taskDistributor worker.TaskDistributor,
+) {
+	relay := worker.NewOutboxRelay(store, taskDistributor, conf.OutboxPollInterval)
+	waitGroup.Go(func() error {
+		log.Info().Msg("start relaying outbox events")
+		err := relay.Start(ctx)
+		log.Info().Msg("outbox relay is stoped")
+		return err
+	})
+}
+
 // runGRPCServer run gRPC server
 func runGRPCServer(
 	ctx context.Context,
 	waitGroup *errgroup.Group,
 	conf util.Config,
 	store db.Store,
+	tokenMaker token.Maker,
 	taskDistributor worker.TaskDistributor,
 ) {
-	server, err := gapi.NewServer(conf, store, taskDistributor)
+	server, err := gapi.NewServer(conf, store, tokenMaker, taskDistributor)
 	if err != nil {
 		log.Fatal().
 			Err(err).
@@ -235,8 +268,9 @@ func runGRPCServer(
 			Msg("cannot create server")
 	}
 
-	grpcLogger := grpc.UnaryInterceptor(gapi.GrpcLogger)
-	grpcServer := grpc.NewServer(grpcLogger)
+	unaryInterceptors := grpc.ChainUnaryInterceptor(gapi.GrpcLogger, server.AuthInterceptor)
+	streamInterceptors := grpc.ChainStreamInterceptor(gapi.GrpcStreamLogger, server.AuthStreamInterceptor)
+	grpcServer := grpc.NewServer(unaryInterceptors, streamInterceptors)
 	pb.RegisterSimpleBankServer(grpcServer, server)
 	reflection.Register(grpcServer)
 
@@ -262,9 +296,15 @@ func runGRPCServer(
 		}
 		return nil
 	})
+	waitGroup.Go(func() error {
+		log.Info().Msg("start listening account events")
+		return server.RunAccountHub(ctx)
+	})
 	waitGroup.Go(func() error {
 		<-ctx.Done()
 		log.Info().Msg("graceful stop gRPC server")
+		// streams wait for account events forever, close them first
+		server.CloseAccountHub()
 		grpcServer.GracefulStop()
 		log.Info().Msg("gRPC server is stoped")
 		return nil
@@ -272,8 +312,8 @@ func runGRPCServer(
 }
 
 // runGinServer run http server with Gin framework
-func runGinServer(conf util.Config, store db.Store) {
-	server, err := api.NewServer(conf, store)
+func runGinServer(conf util.Config, store db.Store, tokenMaker token.Maker) {
+	server, err := api.NewServer(conf, store, tokenMaker)
 	if err != nil {
 		log.Fatal().
 			Err(err).
@@ -0,0 +1,226 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v5.26.1
+// source: rpc_admin_get_account.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AdminGetAccountRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *AdminGetAccountRequest) Reset() {
+	*x = AdminGetAccountRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_admin_get_account_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminGetAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminGetAccountRequest) ProtoMessage() {}
+
+func (x *AdminGetAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_admin_get_account_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminGetAccountRequest.ProtoReflect.Descriptor instead.
+func (*AdminGetAccountRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_admin_get_account_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AdminGetAccountRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type AdminGetAccountResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account *Account `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Owner   *User    `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+}
+
+func (x *AdminGetAccountResponse) Reset() {
+	*x = AdminGetAccountResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_admin_get_account_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminGetAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminGetAccountResponse) ProtoMessage() {}
+
+func (x *AdminGetAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_admin_get_account_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminGetAccountResponse.ProtoReflect.Descriptor instead.
+func (*AdminGetAccountResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_admin_get_account_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AdminGetAccountResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *AdminGetAccountResponse) GetOwner() *User {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+var File_rpc_admin_get_account_proto protoreflect.FileDescriptor
+
+var file_rpc_admin_get_account_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x72, 0x70, 0x63, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x5f, 0x67, 0x65, 0x74, 0x5f,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70,
+	0x62, 0x1a, 0x0d, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x28, 0x0a, 0x16,
+	0x41, 0x64, 0x6d, 0x69, 0x6e, 0x47, 0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x60, 0x0a, 0x17, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x47,
+	0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x25, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52,
+	0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x70, 0x62, 0x2e, 0x55, 0x73, 0x65,
+	0x72, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x42, 0x23, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x75, 0x62, 0x61, 0x73, 0x73, 0x38, 0x33, 0x2f,
+	0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_admin_get_account_proto_rawDescOnce sync.Once
+	file_rpc_admin_get_account_proto_rawDescData = file_rpc_admin_get_account_proto_rawDesc
+)
+
+func file_rpc_admin_get_account_proto_rawDescGZIP() []byte {
+	file_rpc_admin_get_account_proto_rawDescOnce.Do(func() {
+		file_rpc_admin_get_account_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_admin_get_account_proto_rawDescData)
+	})
+	return file_rpc_admin_get_account_proto_rawDescData
+}
+
+var file_rpc_admin_get_account_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_admin_get_account_proto_goTypes = []interface{}{
+	(*AdminGetAccountRequest)(nil),  // 0: pb.AdminGetAccountRequest
+	(*AdminGetAccountResponse)(nil), // 1: pb.AdminGetAccountResponse
+	(*Account)(nil),                 // 2: pb.Account
+	(*User)(nil),                    // 3: pb.User
+}
+var file_rpc_admin_get_account_proto_depIdxs = []int32{
+	2, // 0: pb.AdminGetAccountResponse.account:type_name -> pb.Account
+	3, // 1: pb.AdminGetAccountResponse.owner:type_name -> pb.User
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_admin_get_account_proto_init() }
+func file_rpc_admin_get_account_proto_init() {
+	if File_rpc_admin_get_account_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	file_user_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_admin_get_account_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminGetAccountRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_admin_get_account_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminGetAccountResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_admin_get_account_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_admin_get_account_proto_goTypes,
+		DependencyIndexes: file_rpc_admin_get_account_proto_depIdxs,
+		MessageInfos:      file_rpc_admin_get_account_proto_msgTypes,
+	}.Build()
+	File_rpc_admin_get_account_proto = out.File
+	file_rpc_admin_get_account_proto_rawDesc = nil
+	file_rpc_admin_get_account_proto_goTypes = nil
+	file_rpc_admin_get_account_proto_depIdxs = nil
+}
@@ -0,0 +1,38 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/token"
+)
+
+// Checker decide if the caller is granted the permission
+type Checker interface {
+	HasPermission(ctx context.Context, payload *token.Payload, permission string) (bool, error)
+}
+
+// StoreChecker read permissions of the user current role from the store,
+// so a changed role takes effect without waiting for the token to expire
+type StoreChecker struct {
+	store db.Store
+}
+
+// NewStoreChecker creates a new StoreChecker
+func NewStoreChecker(store db.Store) *StoreChecker {
+	return &StoreChecker{store: store}
+}
+
+func (checker *StoreChecker) HasPermission(ctx context.Context, payload *token.Payload, permission string) (bool, error) {
+	permissions, err := checker.store.GetUserPermissions(ctx, payload.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("cannot get user permissions: %w", err)
+	}
+	return slices.Contains(permissions, permission), nil
+}
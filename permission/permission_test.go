@@ -0,0 +1,95 @@
+package permission
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasPermission(t *testing.T) {
+	payload, err := token.NewPayload(util.RandomOwner(), util.BankerRole, time.Minute)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		check      func(t *testing.T, ok bool, err error)
+	}{
+		{
+			name: "Granted",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(payload.Username)).
+					Times(1).
+					Return([]string{util.PermissionUsersUnlock, util.PermissionTransfersApprove}, nil)
+			},
+			check: func(t *testing.T, ok bool, err error) {
+				require.NoError(t, err)
+				require.True(t, ok)
+			},
+		},
+		{
+			// the role in the token is stale, the current role has no permission
+			name: "NotGranted",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(payload.Username)).
+					Times(1).
+					Return([]string{}, nil)
+			},
+			check: func(t *testing.T, ok bool, err error) {
+				require.NoError(t, err)
+				require.False(t, ok)
+			},
+		},
+		{
+			name: "UserNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(payload.Username)).
+					Times(1).
+					Return(nil, db.ErrRecordNotFound)
+			},
+			check: func(t *testing.T, ok bool, err error) {
+				require.NoError(t, err)
+				require.False(t, ok)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(payload.Username)).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			check: func(t *testing.T, ok bool, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.False(t, ok)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			checker := NewStoreChecker(store)
+			ok, err := checker.HasPermission(context.Background(), payload, util.PermissionUsersUnlock)
+			tc.check(t, ok, err)
+		})
+	}
+}
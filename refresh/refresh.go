@@ -0,0 +1,131 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/revocation"
+	"github.com/dubass83/simplebank/token"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrInvalidToken    = errors.New("invalid refresh token")
+	ErrSessionNotFound = errors.New("session not found")
+	ErrTokenReused     = errors.New("refresh token was already used")
+)
+
+// Rotator verify refresh tokens and exchange them for the new ones in the
+// same session family, shared by the gRPC and the HTTP servers
+type Rotator struct {
+	store      db.Store
+	tokenMaker token.Maker
+	revocation revocation.Checker
+	duration   time.Duration
+}
+
+// NewRotator creates a new Rotator issuing refresh tokens valid for duration
+func NewRotator(store db.Store, tokenMaker token.Maker, checker revocation.Checker, duration time.Duration) *Rotator {
+	return &Rotator{
+		store:      store,
+		tokenMaker: tokenMaker,
+		revocation: checker,
+		duration:   duration,
+	}
+}
+
+// Verify check the refresh token and return the active session issued with it.
+// Presenting the already rotated token revokes the whole session family.
+func (rotator *Rotator) Verify(ctx context.Context, refreshToken string) (*token.Payload, db.Session, error) {
+	payload, err := rotator.tokenMaker.VerifyToken(refreshToken)
+	if err != nil {
+		return nil, db.Session{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if payload.Purpose != token.PurposeRefresh {
+		return nil, db.Session{}, fmt.Errorf("%w: token is not a refresh token", ErrInvalidToken)
+	}
+
+	session, err := rotator.store.GetSession(ctx, uuid.UUID(payload.ID))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, db.Session{}, ErrSessionNotFound
+		}
+		return nil, db.Session{}, fmt.Errorf("cannot get session: %w", err)
+	}
+
+	// the token was already exchanged for a new one, someone replays it
+	if session.RotatedAt.Valid {
+		return nil, db.Session{}, rotator.revokeFamily(ctx, session)
+	}
+
+	if session.IsBloked {
+		return nil, db.Session{}, fmt.Errorf("%w: session is bloked", ErrInvalidToken)
+	}
+
+	if session.Username != payload.Username {
+		return nil, db.Session{}, fmt.Errorf("%w: user in session does not much to user in refresh Payload", ErrInvalidToken)
+	}
+
+	if session.RefreshToken != refreshToken {
+		return nil, db.Session{}, fmt.Errorf("%w: refresh token does not much", ErrInvalidToken)
+	}
+
+	if time.Now().After(session.ExpiredAt) {
+		return nil, db.Session{}, fmt.Errorf("%w: expired session", ErrInvalidToken)
+	}
+
+	// refresh tokens issued before the password change are revoked as well
+	if err := rotator.revocation.Check(ctx, payload); err != nil {
+		return nil, db.Session{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	return payload, session, nil
+}
+
+// Rotate issue new refresh token and replace the session with the new one in the same family
+func (rotator *Rotator) Rotate(ctx context.Context, session db.Session, role, userAgent, clientIP string) (string, db.Session, error) {
+	refreshToken, refreshPayload, err := rotator.tokenMaker.CreateToken(session.Username, role, rotator.duration,
+		token.WithPurpose(token.PurposeRefresh))
+	if err != nil {
+		return "", db.Session{}, fmt.Errorf("failed create refresh token: %w", err)
+	}
+
+	newSession, err := rotator.store.RotateSessionTx(ctx, db.RotateSessionTxParams{
+		SessionID: session.ID,
+		CreateSessionParams: db.CreateSessionParams{
+			ID:           uuid.UUID(refreshPayload.ID),
+			Username:     session.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    userAgent,
+			ClientIp:     clientIP,
+			IsBloked:     false,
+			ExpiredAt:    refreshPayload.ExpiredAt,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRefreshTokenReused) {
+			return "", db.Session{}, rotator.revokeFamily(ctx, session)
+		}
+		return "", db.Session{}, fmt.Errorf("cannot rotate session: %w", err)
+	}
+	rotator.revocation.ForgetSession(session.ID)
+
+	return refreshToken, newSession, nil
+}
+
+// revokeFamily block all sessions issued from the same login as the reused one
+func (rotator *Rotator) revokeFamily(ctx context.Context, session db.Session) error {
+	revoked, err := rotator.store.RevokeSessionFamilyTx(ctx, session)
+	if err != nil {
+		return fmt.Errorf("cannot revoke sessions: %w", err)
+	}
+	rotator.revocation.ForgetUser(session.Username)
+	log.Warn().Str("username", session.Username).Str("family_id", session.FamilyID.String()).
+		Int64("revoked_sessions", revoked).Msg("refresh token reuse detected")
+	return ErrTokenReused
+}
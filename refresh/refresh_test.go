@@ -0,0 +1,203 @@
+package refresh
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// revocationStub return the same result for every token without store lookups
+type revocationStub struct {
+	err error
+}
+
+func (stub revocationStub) Check(ctx context.Context, payload *token.Payload) error {
+	return stub.err
+}
+
+func (stub revocationStub) ForgetSession(sessionID uuid.UUID) {}
+
+func (stub revocationStub) ForgetUser(username string) {}
+
+func newTestMaker(t *testing.T) token.Maker {
+	tokenMaker, err := token.NewMaker(util.Config{TokenString: util.RandomString(32)})
+	require.NoError(t, err)
+	return tokenMaker
+}
+
+// randomSession create refresh token and the session issued with it
+func randomSession(t *testing.T, tokenMaker token.Maker, username string, opts ...token.PayloadOption) (string, db.Session) {
+	refreshToken, payload, err := tokenMaker.CreateToken(username, util.DepositorRole, time.Hour, opts...)
+	require.NoError(t, err)
+
+	return refreshToken, db.Session{
+		ID:           uuid.UUID(payload.ID),
+		Username:     username,
+		RefreshToken: refreshToken,
+		ExpiredAt:    payload.ExpiredAt,
+		CreatedAt:    payload.IssuedAt,
+		FamilyID:     uuid.UUID(payload.ID),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		opts       []token.PayloadOption
+		revoked    error
+		buildStubs func(store *mockdb.MockStore, session db.Session)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			opts: []token.PayloadOption{token.WithPurpose(token.PurposeRefresh)},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		}, {
+			name: "AccessToken",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidToken)
+			},
+		}, {
+			name: "RotatedToken",
+			opts: []token.PayloadOption{token.WithPurpose(token.PurposeRefresh)},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				session.IsBloked = true
+				session.RotatedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RevokeSessionFamilyTx(gomock.Any(), gomock.Eq(session)).
+					Times(1).
+					Return(int64(2), nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTokenReused)
+			},
+		}, {
+			name:    "Revoked",
+			opts:    []token.PayloadOption{token.WithPurpose(token.PurposeRefresh)},
+			revoked: sql.ErrNoRows,
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidToken)
+			},
+		}, {
+			name: "SessionNotFound",
+			opts: []token.PayloadOption{token.WithPurpose(token.PurposeRefresh)},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(db.Session{}, db.ErrRecordNotFound)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrSessionNotFound)
+			},
+		}, {
+			name: "StoreError",
+			opts: []token.PayloadOption{token.WithPurpose(token.PurposeRefresh)},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(db.Session{}, sql.ErrConnDone)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+
+			tokenMaker := newTestMaker(t)
+			refreshToken, session := randomSession(t, tokenMaker, username, tc.opts...)
+			tc.buildStubs(store, session)
+
+			rotator := NewRotator(store, tokenMaker, revocationStub{err: tc.revoked}, time.Hour)
+			_, _, err := rotator.Verify(context.Background(), refreshToken)
+			tc.checkErr(t, err)
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	tokenMaker := newTestMaker(t)
+	_, session := randomSession(t, tokenMaker, username, token.WithPurpose(token.PurposeRefresh))
+	rotator := NewRotator(store, tokenMaker, revocationStub{}, time.Hour)
+
+	var created db.CreateSessionParams
+	store.EXPECT().
+		RotateSessionTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.RotateSessionTxParams) (db.Session, error) {
+			require.Equal(t, session.ID, arg.SessionID)
+			created = arg.CreateSessionParams
+			return db.Session{ID: arg.ID, Username: arg.Username, FamilyID: session.FamilyID}, nil
+		})
+	refreshToken, newSession, err := rotator.Rotate(context.Background(), session, util.DepositorRole, "agent", "127.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, created.RefreshToken, refreshToken)
+	require.Equal(t, session.FamilyID, newSession.FamilyID)
+
+	// the new refresh token is not accepted as the access token
+	payload, err := tokenMaker.VerifyToken(refreshToken)
+	require.NoError(t, err)
+	require.Equal(t, token.PurposeRefresh, payload.Purpose)
+
+	// the concurrent request rotated the session first
+	store.EXPECT().
+		RotateSessionTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.Session{}, db.ErrRefreshTokenReused)
+	store.EXPECT().
+		RevokeSessionFamilyTx(gomock.Any(), gomock.Eq(session)).
+		Times(1).
+		Return(int64(1), nil)
+	_, _, err = rotator.Rotate(context.Background(), session, util.DepositorRole, "agent", "127.0.0.1")
+	require.ErrorIs(t, err, ErrTokenReused)
+}
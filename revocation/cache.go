@@ -0,0 +1,79 @@
+package revocation
+
+import (
+	"sync"
+	"time"
+)
+
+// maxCacheEntries keep the cache small, expired entries are dropped when it is full
+const maxCacheEntries = 10000
+
+type cacheEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// ttlCache in-memory map which forgets values after ttl
+type ttlCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[K]cacheEntry[V]
+}
+
+func newTTLCache[K comparable, V any](ttl time.Duration) *ttlCache[K, V] {
+	return &ttlCache[K, V]{
+		ttl:     ttl,
+		entries: make(map[K]cacheEntry[V]),
+	}
+}
+
+func (cache *ttlCache[K, V]) get(key K, now time.Time) (V, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+func (cache *ttlCache[K, V]) set(key K, value V, now time.Time) {
+	if cache.ttl <= 0 {
+		return
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if len(cache.entries) >= maxCacheEntries {
+		for k, entry := range cache.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(cache.entries, k)
+			}
+		}
+		if len(cache.entries) >= maxCacheEntries {
+			cache.entries = make(map[K]cacheEntry[V])
+		}
+	}
+	cache.entries[key] = cacheEntry[V]{value: value, expiresAt: now.Add(cache.ttl)}
+}
+
+func (cache *ttlCache[K, V]) delete(key K) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	delete(cache.entries, key)
+}
+
+// deleteFunc drop every entry the match function returns true for
+func (cache *ttlCache[K, V]) deleteFunc(match func(key K, value V) bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for key, entry := range cache.entries {
+		if match(key, entry.value) {
+			delete(cache.entries, key)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/token"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSessionRevoked       = errors.New("session is revoked")
+	ErrIssuedBeforePassword = errors.New("token was issued before the password change")
+)
+
+// Checker decide if the verified token was revoked after it was issued
+type Checker interface {
+	Check(ctx context.Context, payload *token.Payload) error
+	// ForgetSession and ForgetUser drop cached state after a local revocation,
+	// so this instance does not wait for the cache ttl. ForgetUser drops
+	// cached sessions of the user as well.
+	ForgetSession(sessionID uuid.UUID)
+	ForgetUser(username string)
+}
+
+type sessionState struct {
+	username string
+	blocked  bool
+}
+
+// CachedChecker look up the session and the password change time of the
+// user in the store and keep them in memory for ttl
+type CachedChecker struct {
+	store    db.Store
+	sessions *ttlCache[uuid.UUID, sessionState]
+	users    *ttlCache[string, time.Time]
+	now      func() time.Time
+}
+
+// NewCachedChecker creates a new CachedChecker, zero ttl disables caching
+func NewCachedChecker(store db.Store, ttl time.Duration) *CachedChecker {
+	return &CachedChecker{
+		store:    store,
+		sessions: newTTLCache[uuid.UUID, sessionState](ttl),
+		users:    newTTLCache[string, time.Time](ttl),
+		now:      time.Now,
+	}
+}
+
+// Check reject the token when its session is blocked or the password
+// of the user was changed after the token was issued
+func (checker *CachedChecker) Check(ctx context.Context, payload *token.Payload) error {
+	// impersonated tokens are bound to the login session of the actor,
+	// so signing out the banker or changing the banker password revokes them
+	owner := payload.Username
+	if payload.IsImpersonated() {
+		owner = payload.Actor
+	}
+
+	sessionID := uuid.UUID(payload.SessionID)
+	if sessionID != uuid.Nil {
+		session, err := checker.session(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+		if session.blocked || session.username != owner {
+			return ErrSessionRevoked
+		}
+	}
+
+	passwordChangedAt, err := checker.passwordChangedAt(ctx, owner)
+	if err != nil {
+		return err
+	}
+	// iat of the token has only second precision
+	if payload.IssuedAt.Before(passwordChangedAt.Truncate(time.Second)) {
+		return ErrIssuedBeforePassword
+	}
+	return nil
+}
+
+func (checker *CachedChecker) ForgetSession(sessionID uuid.UUID) {
+	checker.sessions.delete(sessionID)
+}
+
+func (checker *CachedChecker) ForgetUser(username string) {
+	checker.users.delete(username)
+	checker.sessions.deleteFunc(func(_ uuid.UUID, state sessionState) bool {
+		return state.username == username
+	})
+}
+
+func (checker *CachedChecker) session(ctx context.Context, sessionID uuid.UUID) (sessionState, error) {
+	now := checker.now()
+	if state, ok := checker.sessions.get(sessionID, now); ok {
+		return state, nil
+	}
+	session, err := checker.store.GetSession(ctx, sessionID)
+	if err != nil {
+		return sessionState{}, fmt.Errorf("cannot get session: %w", err)
+	}
+	state := sessionState{
+		username: session.Username,
+		blocked:  session.IsBloked,
+	}
+	checker.sessions.set(sessionID, state, now)
+	return state, nil
+}
+
+func (checker *CachedChecker) passwordChangedAt(ctx context.Context, username string) (time.Time, error) {
+	now := checker.now()
+	if changedAt, ok := checker.users.get(username, now); ok {
+		return changedAt, nil
+	}
+	user, err := checker.store.GetUser(ctx, username)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot get user: %w", err)
+	}
+	checker.users.set(username, user.PasswordChangedAt, now)
+	return user.PasswordChangedAt, nil
+}
@@ -0,0 +1,260 @@
+package revocation
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/dubass83/simplebank/db/mock"
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/token"
+	"github.com/dubass83/simplebank/util"
+	gofrsuuid "github.com/gofrs/uuid/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPayload(t *testing.T, username string, sessionID uuid.UUID, issuedAt time.Time) *token.Payload {
+	payload, err := token.NewPayload(username, util.DepositorRole, time.Minute,
+		token.WithSessionID(gofrsuuid.UUID(sessionID)))
+	require.NoError(t, err)
+	payload.IssuedAt = issuedAt
+	return payload
+}
+
+// impersonatedPayload token issued to the actor in the login session of the actor
+func impersonatedPayload(t *testing.T, username, actor string, sessionID uuid.UUID, issuedAt time.Time) *token.Payload {
+	payload := randomPayload(t, username, sessionID, issuedAt)
+	payload.Actor = actor
+	return payload
+}
+
+func TestCheck(t *testing.T) {
+	username := util.RandomOwner()
+	actor := util.RandomOwner()
+	sessionID := uuid.New()
+	now := time.Now()
+
+	testCases := []struct {
+		name       string
+		payload    *token.Payload
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name:    "OK",
+			payload: randomPayload(t, username, sessionID, now),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, Username: username}, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(db.User{Username: username, PasswordChangedAt: now.Add(-time.Hour)}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		}, {
+			name:    "NoSession",
+			payload: randomPayload(t, username, uuid.Nil, now),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(db.User{Username: username}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		}, {
+			name:    "SessionBlocked",
+			payload: randomPayload(t, username, sessionID, now),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, Username: username, IsBloked: true}, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrSessionRevoked)
+			},
+		}, {
+			name:    "SessionOfAnotherUser",
+			payload: randomPayload(t, username, sessionID, now),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, Username: util.RandomOwner()}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrSessionRevoked)
+			},
+		}, {
+			name:    "ImpersonatedInActorSession",
+			payload: impersonatedPayload(t, username, actor, sessionID, now),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, Username: actor}, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(actor)).
+					Times(1).
+					Return(db.User{Username: actor, PasswordChangedAt: now.Add(-time.Hour)}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		}, {
+			name:    "ImpersonatedInSubjectSession",
+			payload: impersonatedPayload(t, username, actor, sessionID, now),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, Username: username}, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrSessionRevoked)
+			},
+		}, {
+			name:    "IssuedBeforePasswordChange",
+			payload: randomPayload(t, username, sessionID, now.Add(-time.Minute)),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.Session{ID: sessionID, Username: username}, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(db.User{Username: username, PasswordChangedAt: now}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrIssuedBeforePassword)
+			},
+		}, {
+			name:    "IssuedInSameSecond",
+			payload: randomPayload(t, username, uuid.Nil, now.Truncate(time.Second)),
+			buildStubs: func(store *mockdb.MockStore) {
+				changedAt := now.Truncate(time.Second).Add(500 * time.Millisecond)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(db.User{Username: username, PasswordChangedAt: changedAt}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		}, {
+			name:    "StoreError",
+			payload: randomPayload(t, username, sessionID, now),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, sql.ErrConnDone)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStubs(store)
+
+			checker := NewCachedChecker(store, 0)
+			tc.checkErr(t, checker.Check(context.Background(), tc.payload))
+		})
+	}
+}
+
+func TestCheckCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	username := util.RandomOwner()
+	sessionID := uuid.New()
+	now := time.Now()
+	payload := randomPayload(t, username, sessionID, now)
+
+	// every lookup is done once per ttl
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(sessionID)).
+		Times(3).
+		Return(db.Session{ID: sessionID, Username: username}, nil)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(username)).
+		Times(3).
+		Return(db.User{Username: username}, nil)
+
+	checker := NewCachedChecker(store, time.Minute)
+	checker.now = func() time.Time { return now }
+
+	require.NoError(t, checker.Check(context.Background(), payload))
+	require.NoError(t, checker.Check(context.Background(), payload))
+
+	// ttl expired
+	checker.now = func() time.Time { return now.Add(time.Minute) }
+	require.NoError(t, checker.Check(context.Background(), payload))
+	require.NoError(t, checker.Check(context.Background(), payload))
+
+	// local revocation drops the user with its sessions
+	checker.ForgetUser(username)
+	require.NoError(t, checker.Check(context.Background(), payload))
+}
+
+func TestForgetSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	username := util.RandomOwner()
+	sessionID := uuid.New()
+	payload := randomPayload(t, username, sessionID, time.Now())
+
+	gomock.InOrder(
+		store.EXPECT().
+			GetSession(gomock.Any(), gomock.Eq(sessionID)).
+			Times(1).
+			Return(db.Session{ID: sessionID, Username: username}, nil),
+		store.EXPECT().
+			GetSession(gomock.Any(), gomock.Eq(sessionID)).
+			Times(1).
+			Return(db.Session{ID: sessionID, Username: username, IsBloked: true}, nil),
+	)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(username)).
+		Times(1).
+		Return(db.User{Username: username}, nil)
+
+	checker := NewCachedChecker(store, time.Minute)
+	require.NoError(t, checker.Check(context.Background(), payload))
+
+	checker.ForgetSession(sessionID)
+	require.ErrorIs(t, checker.Check(context.Background(), payload), ErrSessionRevoked)
+}
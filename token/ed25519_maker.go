@@ -0,0 +1,150 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Ed25519Maker sign tokens with the private key and verify them with any of
+// the known public keys, so old keys keep working while the key is rotated
+type Ed25519Maker struct {
+	signingKey ed25519.PrivateKey
+	signingKid string
+	verifyKeys map[string]ed25519.PublicKey
+}
+
+// NewEd25519Maker creates a new Ed25519Maker, the public key of the signing
+// key is always accepted for verification
+func NewEd25519Maker(signingKey ed25519.PrivateKey, verifyKeys ...ed25519.PublicKey) (Maker, error) {
+	if len(signingKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid ed25519 private key size: %d", len(signingKey))
+	}
+	publicKey := signingKey.Public().(ed25519.PublicKey)
+	maker := &Ed25519Maker{
+		signingKey: signingKey,
+		signingKid: keyID(publicKey),
+		verifyKeys: map[string]ed25519.PublicKey{keyID(publicKey): publicKey},
+	}
+	for _, key := range verifyKeys {
+		if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 public key size: %d", len(key))
+		}
+		maker.verifyKeys[keyID(key)] = key
+	}
+	return maker, nil
+}
+
+// LoadEd25519Maker read PEM encoded PKCS #8 signing key and PKIX public keys
+// of the previous signing keys from files
+func LoadEd25519Maker(signingKeyFile string, verifyKeyFiles ...string) (Maker, error) {
+	signingKey, verifyKeys, err := loadEd25519Keys(signingKeyFile, verifyKeyFiles...)
+	if err != nil {
+		return nil, err
+	}
+	return NewEd25519Maker(signingKey, verifyKeys...)
+}
+
+// loadEd25519Keys read the signing key and the public keys of the previous signing keys
+func loadEd25519Keys(signingKeyFile string, verifyKeyFiles ...string) (ed25519.PrivateKey, []ed25519.PublicKey, error) {
+	block, err := readPEM(signingKeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse signing key %s: %w", signingKeyFile, err)
+	}
+	signingKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("signing key %s is not ed25519 key", signingKeyFile)
+	}
+
+	verifyKeys := make([]ed25519.PublicKey, 0, len(verifyKeyFiles))
+	for _, file := range verifyKeyFiles {
+		block, err := readPEM(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot parse verify key %s: %w", file, err)
+		}
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, nil, fmt.Errorf("verify key %s is not ed25519 key", file)
+		}
+		verifyKeys = append(verifyKeys, publicKey)
+	}
+	return signingKey, verifyKeys, nil
+}
+
+func readPEM(file string) (*pem.Block, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in key file %s", file)
+	}
+	return block, nil
+}
+
+// CreateToken create new token for username and duration
+func (maker *Ed25519Maker) CreateToken(username string, role string, duration time.Duration, opts ...PayloadOption) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration, opts...)
+	if err != nil {
+		return "", payload, err
+	}
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodEdDSA, newJwtClaims(payload))
+	jwtToken.Header["kid"] = maker.signingKid
+	ss, err := jwtToken.SignedString(maker.signingKey)
+	if err != nil {
+		return "", payload, err
+	}
+	return ss, payload, nil
+}
+
+// VerifyToken check is token valid or not, the key is picked by kid header
+func (maker *Ed25519Maker) VerifyToken(tokenString string) (*Payload, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, ok := maker.verifyKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %q", kid)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("can not cast token.Claims to jwt.MapClaims struct %v", token.Claims)
+	}
+	return payloadFromClaims(claims)
+}
+
+// JWKS return public keys accepted for verification, so other services can
+// verify our tokens without access to the signing key
+func (maker *Ed25519Maker) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(maker.verifyKeys))}
+	// the current signing key goes first
+	set.Keys = append(set.Keys, newEd25519JWK(maker.signingKid, maker.verifyKeys[maker.signingKid]))
+	for kid, key := range maker.verifyKeys {
+		if kid != maker.signingKid {
+			set.Keys = append(set.Keys, newEd25519JWK(kid, key))
+		}
+	}
+	return set
+}
@@ -0,0 +1,150 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEd25519Key(t *testing.T) ed25519.PrivateKey {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func TestEd25519Maker(t *testing.T) {
+	maker, err := NewEd25519Maker(randomEd25519Key(t))
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	role := util.DepositorRole
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(username, role, duration, WithPurpose(PurposeTotpChallenge))
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotZero(t, payload.ID)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, PurposeTotpChallenge, payload.Purpose)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+}
+
+func TestExpiredEd25519Token(t *testing.T) {
+	maker, err := NewEd25519Maker(randomEd25519Key(t))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, -time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestEd25519MakerKeyRotation(t *testing.T) {
+	oldKey := randomEd25519Key(t)
+	oldMaker, err := NewEd25519Maker(oldKey)
+	require.NoError(t, err)
+
+	oldToken, _, err := oldMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	// the new key signs, the old one is still accepted
+	newMaker, err := NewEd25519Maker(randomEd25519Key(t), oldKey.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+
+	_, err = newMaker.VerifyToken(oldToken)
+	require.NoError(t, err)
+
+	newToken, _, err := newMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	_, err = newMaker.VerifyToken(newToken)
+	require.NoError(t, err)
+
+	// services which do not know the new key yet reject its tokens
+	_, err = oldMaker.VerifyToken(newToken)
+	require.ErrorContains(t, err, "unknown signing key")
+}
+
+func TestEd25519MakerRejectHMAC(t *testing.T) {
+	key := randomEd25519Key(t)
+	maker, err := NewEd25519Maker(key)
+	require.NoError(t, err)
+
+	// token signed with the public key as the HMAC secret must not pass
+	payload, err := NewPayload(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, newJwtClaims(payload))
+	jwtToken.Header["kid"] = keyID(key.Public().(ed25519.PublicKey))
+	token, err := jwtToken.SignedString([]byte(key.Public().(ed25519.PublicKey)))
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	require.ErrorContains(t, err, "unexpected signing method")
+	require.Nil(t, payload)
+}
+
+func TestLoadEd25519Maker(t *testing.T) {
+	dir := t.TempDir()
+
+	signingKey := randomEd25519Key(t)
+	der, err := x509.MarshalPKCS8PrivateKey(signingKey)
+	require.NoError(t, err)
+	signingKeyFile := filepath.Join(dir, "signing.pem")
+	require.NoError(t, os.WriteFile(signingKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+	oldKey := randomEd25519Key(t)
+	der, err = x509.MarshalPKIXPublicKey(oldKey.Public())
+	require.NoError(t, err)
+	verifyKeyFile := filepath.Join(dir, "old.pub.pem")
+	require.NoError(t, os.WriteFile(verifyKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+
+	maker, err := LoadEd25519Maker(signingKeyFile, verifyKeyFile)
+	require.NoError(t, err)
+
+	jwks := maker.(KeySetProvider).JWKS()
+	require.Len(t, jwks.Keys, 2)
+	// the current signing key goes first
+	require.Equal(t, keyID(signingKey.Public().(ed25519.PublicKey)), jwks.Keys[0].Kid)
+	require.Equal(t, keyID(oldKey.Public().(ed25519.PublicKey)), jwks.Keys[1].Kid)
+	for _, key := range jwks.Keys {
+		require.Equal(t, "OKP", key.Kty)
+		require.Equal(t, "Ed25519", key.Crv)
+		require.Equal(t, "EdDSA", key.Alg)
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		require.NoError(t, err)
+		require.Len(t, x, ed25519.PublicKeySize)
+	}
+
+	_, err = LoadEd25519Maker(filepath.Join(dir, "missing.pem"))
+	require.Error(t, err)
+
+	_, err = LoadEd25519Maker(verifyKeyFile)
+	require.Error(t, err)
+}
+
+func TestKeyID(t *testing.T) {
+	// RFC 8037 appendix A.3 thumbprint of the example key
+	x, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	require.NoError(t, err)
+	require.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", keyID(ed25519.PublicKey(x)))
+}
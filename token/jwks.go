@@ -0,0 +1,48 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// JWKSet JSON Web Key Set served at /.well-known/jwks.json (RFC 7517)
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK public key of the OKP type used for EdDSA signatures (RFC 8037)
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+// KeySetProvider is implemented by makers which sign tokens with asymmetric keys
+type KeySetProvider interface {
+	JWKS() JWKSet
+}
+
+func newEd25519JWK(kid string, key ed25519.PublicKey) JWK {
+	return JWK{
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(key),
+		Kid: kid,
+		Use: "sig",
+		Alg: "EdDSA",
+	}
+}
+
+// keyID is the JWK thumbprint of the public key (RFC 7638)
+func keyID(key ed25519.PublicKey) string {
+	x := base64.RawURLEncoding.EncodeToString(key)
+	// members in lexicographic order without whitespace
+	canonical := fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, x)
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
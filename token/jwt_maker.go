@@ -11,6 +11,14 @@ import (
 
 const minSecretKey = 32
 
+// jwtClaims registered claims with the id of the login session
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	SessionID string `json:"sid,omitempty"`
+	Purpose   string `json:"pur,omitempty"`
+	Actor     string `json:"act,omitempty"`
+}
+
 type JwtMaker struct {
 	secretKey string
 }
@@ -23,21 +31,12 @@ func NewJwtMaker(secretKey string) (Maker, error) {
 }
 
 // CreateToken create new token for username and duration
-func (maker JwtMaker) CreateToken(username string, role string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, role, duration)
+func (maker JwtMaker) CreateToken(username string, role string, duration time.Duration, opts ...PayloadOption) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration, opts...)
 	if err != nil {
 		return "", payload, err
 	}
-	// prepare claim for jwt from payload struct
-	claim := &jwt.RegisteredClaims{
-		ID:        payload.ID.String(),
-		Subject:   payload.Username,
-		Audience:  jwt.ClaimStrings{payload.Role},
-		IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
-		ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
-	}
-
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, newJwtClaims(payload))
 	ss, err := jwtToken.SignedString([]byte(maker.secretKey))
 	if err != nil {
 		return "", payload, err
@@ -68,6 +67,30 @@ func (maker JwtMaker) VerifyToken(tokenString string) (*Payload, error) {
 	if !ok {
 		return nil, fmt.Errorf("can not cast token.Claims to jwt.MapClaims struct %v", token.Claims)
 	}
+	return payloadFromClaims(claims)
+}
+
+// newJwtClaims prepare claims for jwt from payload struct
+func newJwtClaims(payload *Payload) *jwtClaims {
+	claim := &jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        payload.ID.String(),
+			Subject:   payload.Username,
+			Audience:  jwt.ClaimStrings{payload.Role},
+			IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
+		},
+		Purpose: payload.Purpose,
+		Actor:   payload.Actor,
+	}
+	if payload.SessionID != uuid.Nil {
+		claim.SessionID = payload.SessionID.String()
+	}
+	return claim
+}
+
+// payloadFromClaims convert verified jwt claims back to the payload struct
+func payloadFromClaims(claims jwt.MapClaims) (*Payload, error) {
 	// convert claims["iat"] to golang time.Time type
 	var tiat time.Time
 	switch iat := claims["iat"].(type) {
@@ -91,11 +114,24 @@ func (maker JwtMaker) VerifyToken(tokenString string) (*Payload, error) {
 	if err != nil {
 		return nil, err
 	}
+	// tokens issued before sessions were bound to them have no sid claim
+	var sessionID uuid.UUID
+	if sid, ok := claims["sid"].(string); ok {
+		sessionID, err = uuid.FromString(sid)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sid claim: %w", err)
+		}
+	}
+	purpose, _ := claims["pur"].(string)
+	actor, _ := claims["act"].(string)
 	return &Payload{
 		ID:        uuid.Must(uuid.FromString(claims["jti"].(string))),
 		Username:  claims["sub"].(string),
 		Role:      role[0],
 		IssuedAt:  tiat,
 		ExpiredAt: texp,
+		SessionID: sessionID,
+		Purpose:   purpose,
+		Actor:     actor,
 	}, nil
 }
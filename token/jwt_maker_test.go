@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/dubass83/simplebank/util"
+	"github.com/gofrs/uuid/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
 )
@@ -74,3 +75,58 @@ func TestInvalidJWTTokenAlgNone(t *testing.T) {
 	require.EqualError(t, err, ErrInvalidToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestJWTMakerSessionID(t *testing.T) {
+	maker, err := NewJwtMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	sessionID, err := uuid.NewV4()
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute, WithSessionID(sessionID))
+	require.NoError(t, err)
+	require.Equal(t, sessionID, payload.SessionID)
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, sessionID, payload.SessionID)
+
+	// token without session has empty session id
+	token, _, err = maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, uuid.Nil, payload.SessionID)
+}
+
+func TestJWTMakerPurpose(t *testing.T) {
+	maker, err := NewJwtMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute, WithPurpose(PurposeTotpChallenge))
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, PurposeTotpChallenge, payload.Purpose)
+}
+
+func TestJWTMakerActor(t *testing.T) {
+	maker, err := NewJwtMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	actor := util.RandomOwner()
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute, WithActor(actor))
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, actor, payload.Actor)
+	require.True(t, payload.IsImpersonated())
+
+	token, _, err = maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.False(t, payload.IsImpersonated())
+}
@@ -1,12 +1,49 @@
 package token
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/dubass83/simplebank/util"
+)
+
+// Types of the token maker selected by TOKEN_TYPE
+const (
+	TypeJwt            = "jwt"
+	TypePasetoV2       = "paseto_v2"
+	TypePasetoV4Local  = "paseto_v4_local"
+	TypePasetoV4Public = "paseto_v4_public"
 )
 
 type Maker interface {
 	// CreateToken create new token for username and duration
-	CreateToken(username string, role string, duration time.Duration) (string, *Payload, error)
+	CreateToken(username string, role string, duration time.Duration, opts ...PayloadOption) (string, *Payload, error)
 	// VerifyToken check is token valid or not
 	VerifyToken(token string) (*Payload, error)
 }
+
+// NewMaker creates the token maker selected by config, jwt is used when the
+// type is not set. JWT is signed with the Ed25519 key when it is configured,
+// otherwise with the shared symmetric secret
+func NewMaker(config util.Config) (Maker, error) {
+	options := PasetoV4Options{
+		Issuer:   config.TokenIssuer,
+		Audience: config.TokenAudience,
+		Footer:   config.TokenFooter,
+	}
+	switch config.TokenType {
+	case TypeJwt, "":
+		if config.TokenSigningKeyFile != "" {
+			return LoadEd25519Maker(config.TokenSigningKeyFile, config.TokenVerifyKeyFiles...)
+		}
+		return NewJwtMaker(config.TokenString)
+	case TypePasetoV2:
+		return NewPasetoMaker(config.TokenString)
+	case TypePasetoV4Local:
+		return NewPasetoV4LocalMaker(config.TokenString, options)
+	case TypePasetoV4Public:
+		return LoadPasetoV4PublicMaker(options, config.TokenSigningKeyFile, config.TokenVerifyKeyFiles...)
+	default:
+		return nil, fmt.Errorf("unsupported token type: %s", config.TokenType)
+	}
+}
@@ -28,8 +28,8 @@ func NewPasetoMaker(secretKey string) (Maker, error) {
 }
 
 // CreateToken create new token for username and duration
-func (maker PasetoMaker) CreateToken(username string, role string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, role, duration)
+func (maker PasetoMaker) CreateToken(username string, role string, duration time.Duration, opts ...PayloadOption) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration, opts...)
 	if err != nil {
 		return "", payload, err
 	}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/dubass83/simplebank/util"
+	"github.com/gofrs/uuid/v5"
 	"github.com/stretchr/testify/require"
 )
 
@@ -49,3 +50,50 @@ func TestExpiredPasetoToken(t *testing.T) {
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestPasetoMakerSessionID(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	sessionID, err := uuid.NewV4()
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute, WithSessionID(sessionID))
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, sessionID, payload.SessionID)
+}
+
+func TestPasetoMakerPurpose(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute, WithPurpose(PurposeTotpChallenge))
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, PurposeTotpChallenge, payload.Purpose)
+}
+
+func TestPasetoMakerActor(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	actor := util.RandomOwner()
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute, WithActor(actor))
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, actor, payload.Actor)
+	require.True(t, payload.IsImpersonated())
+
+	token, _, err = maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.False(t, payload.IsImpersonated())
+}
@@ -0,0 +1,217 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/gofrs/uuid/v5"
+)
+
+// PasetoV4Options claims and footer put into every PASETO v4 token and
+// required when the token is verified, empty values are not checked
+type PasetoV4Options struct {
+	Issuer   string
+	Audience string
+	Footer   string
+}
+
+// PasetoV4LocalMaker encrypt tokens with the shared symmetric key (v4.local)
+type PasetoV4LocalMaker struct {
+	key     paseto.V4SymmetricKey
+	options PasetoV4Options
+}
+
+// NewPasetoV4LocalMaker creates a new PasetoV4LocalMaker from the 32 bytes secret key
+func NewPasetoV4LocalMaker(secretKey string, options PasetoV4Options) (Maker, error) {
+	key, err := paseto.V4SymmetricKeyFromBytes([]byte(secretKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key size: %d must be exactly 32", len(secretKey))
+	}
+	return &PasetoV4LocalMaker{key: key, options: options}, nil
+}
+
+// CreateToken create new token for username and duration
+func (maker *PasetoV4LocalMaker) CreateToken(username string, role string, duration time.Duration, opts ...PayloadOption) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration, opts...)
+	if err != nil {
+		return "", payload, err
+	}
+	pasetoToken := newPasetoToken(payload, maker.options)
+	return pasetoToken.V4Encrypt(maker.key, nil), payload, nil
+}
+
+// VerifyToken check is token valid or not
+func (maker *PasetoV4LocalMaker) VerifyToken(token string) (*Payload, error) {
+	pasetoToken, err := newPasetoParser(maker.options).ParseV4Local(maker.key, token, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return payloadFromPasetoToken(pasetoToken, maker.options)
+}
+
+// PasetoV4PublicMaker sign tokens with the Ed25519 key (v4.public) and
+// verify them with any of the known public keys, so the key can be rotated
+type PasetoV4PublicMaker struct {
+	signingKey paseto.V4AsymmetricSecretKey
+	verifyKeys []paseto.V4AsymmetricPublicKey
+	options    PasetoV4Options
+}
+
+// NewPasetoV4PublicMaker creates a new PasetoV4PublicMaker, the public key
+// of the signing key is always accepted for verification
+func NewPasetoV4PublicMaker(options PasetoV4Options, signingKey ed25519.PrivateKey, verifyKeys ...ed25519.PublicKey) (Maker, error) {
+	if len(signingKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid ed25519 private key size: %d", len(signingKey))
+	}
+	secretKey, err := paseto.NewV4AsymmetricSecretKeyFromEd25519(signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ed25519 private key: %w", err)
+	}
+	maker := &PasetoV4PublicMaker{
+		signingKey: secretKey,
+		verifyKeys: []paseto.V4AsymmetricPublicKey{secretKey.Public()},
+		options:    options,
+	}
+	for _, key := range verifyKeys {
+		publicKey, err := paseto.NewV4AsymmetricPublicKeyFromEd25519(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ed25519 public key: %w", err)
+		}
+		maker.verifyKeys = append(maker.verifyKeys, publicKey)
+	}
+	return maker, nil
+}
+
+// LoadPasetoV4PublicMaker read the keys from files in the same format as LoadEd25519Maker
+func LoadPasetoV4PublicMaker(options PasetoV4Options, signingKeyFile string, verifyKeyFiles ...string) (Maker, error) {
+	signingKey, verifyKeys, err := loadEd25519Keys(signingKeyFile, verifyKeyFiles...)
+	if err != nil {
+		return nil, err
+	}
+	return NewPasetoV4PublicMaker(options, signingKey, verifyKeys...)
+}
+
+// CreateToken create new token for username and duration
+func (maker *PasetoV4PublicMaker) CreateToken(username string, role string, duration time.Duration, opts ...PayloadOption) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration, opts...)
+	if err != nil {
+		return "", payload, err
+	}
+	pasetoToken := newPasetoToken(payload, maker.options)
+	return pasetoToken.V4Sign(maker.signingKey, nil), payload, nil
+}
+
+// VerifyToken check is token valid or not, v4.public has no key id,
+// so every known key is tried starting from the current signing key
+func (maker *PasetoV4PublicMaker) VerifyToken(token string) (*Payload, error) {
+	parser := newPasetoParser(maker.options)
+	for _, key := range maker.verifyKeys {
+		pasetoToken, err := parser.ParseV4Public(key, token, nil)
+		if err != nil {
+			continue
+		}
+		return payloadFromPasetoToken(pasetoToken, maker.options)
+	}
+	return nil, ErrInvalidToken
+}
+
+// newPasetoToken prepare claims for PASETO from payload struct
+func newPasetoToken(payload *Payload, options PasetoV4Options) paseto.Token {
+	pasetoToken := paseto.NewToken()
+	pasetoToken.SetJti(payload.ID.String())
+	pasetoToken.SetSubject(payload.Username)
+	pasetoToken.SetIssuedAt(payload.IssuedAt)
+	pasetoToken.SetNotBefore(payload.IssuedAt)
+	pasetoToken.SetExpiration(payload.ExpiredAt)
+	pasetoToken.SetString("role", payload.Role)
+	if payload.SessionID != uuid.Nil {
+		pasetoToken.SetString("sid", payload.SessionID.String())
+	}
+	if payload.Purpose != "" {
+		pasetoToken.SetString("pur", payload.Purpose)
+	}
+	if payload.Actor != "" {
+		pasetoToken.SetString("act", payload.Actor)
+	}
+	if options.Issuer != "" {
+		pasetoToken.SetIssuer(options.Issuer)
+	}
+	if options.Audience != "" {
+		pasetoToken.SetAudience(options.Audience)
+	}
+	pasetoToken.SetFooter([]byte(options.Footer))
+	return pasetoToken
+}
+
+// newPasetoParser require configured issuer and audience, expiration is
+// checked by payload so expired tokens return ErrExpiredToken
+func newPasetoParser(options PasetoV4Options) paseto.Parser {
+	parser := paseto.MakeParser([]paseto.Rule{paseto.NotBeforeNbf()})
+	if options.Issuer != "" {
+		parser.AddRule(paseto.IssuedBy(options.Issuer))
+	}
+	if options.Audience != "" {
+		parser.AddRule(paseto.ForAudience(options.Audience))
+	}
+	return parser
+}
+
+// payloadFromPasetoToken convert verified PASETO claims back to the payload struct
+func payloadFromPasetoToken(pasetoToken *paseto.Token, options PasetoV4Options) (*Payload, error) {
+	// footer is authenticated but not checked by the parser
+	if subtle.ConstantTimeCompare(pasetoToken.Footer(), []byte(options.Footer)) != 1 {
+		return nil, ErrInvalidToken
+	}
+
+	jti, err := pasetoToken.GetJti()
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	tokenID, err := uuid.FromString(jti)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	username, err := pasetoToken.GetSubject()
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	role, err := pasetoToken.GetString("role")
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	issuedAt, err := pasetoToken.GetIssuedAt()
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	expiredAt, err := pasetoToken.GetExpiration()
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var sessionID uuid.UUID
+	if sid, err := pasetoToken.GetString("sid"); err == nil {
+		sessionID, err = uuid.FromString(sid)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+	}
+	purpose, _ := pasetoToken.GetString("pur")
+	actor, _ := pasetoToken.GetString("act")
+
+	payload := &Payload{
+		ID:        tokenID,
+		Username:  username,
+		Role:      role,
+		IssuedAt:  issuedAt,
+		ExpiredAt: expiredAt,
+		SessionID: sessionID,
+		Purpose:   purpose,
+		Actor:     actor,
+	}
+	if err := payload.Valid(); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,228 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPasetoV4Options() PasetoV4Options {
+	return PasetoV4Options{
+		Issuer:   util.RandomString(6),
+		Audience: util.RandomString(6),
+		Footer:   util.RandomString(6),
+	}
+}
+
+func TestPasetoV4Makers(t *testing.T) {
+	options := randomPasetoV4Options()
+	localMaker, err := NewPasetoV4LocalMaker(util.RandomString(32), options)
+	require.NoError(t, err)
+	publicMaker, err := NewPasetoV4PublicMaker(options, randomEd25519Key(t))
+	require.NoError(t, err)
+
+	for name, maker := range map[string]Maker{"local": localMaker, "public": publicMaker} {
+		t.Run(name, func(t *testing.T) {
+			username := util.RandomOwner()
+			role := util.DepositorRole
+			duration := time.Minute
+			sessionID, err := uuid.NewV4()
+			require.NoError(t, err)
+
+			issuedAt := time.Now()
+			expiredAt := issuedAt.Add(duration)
+
+			actor := util.RandomOwner()
+			token, payload, err := maker.CreateToken(username, role, duration, WithSessionID(sessionID), WithPurpose(PurposeTotpChallenge), WithActor(actor))
+			require.NoError(t, err)
+			require.NotEmpty(t, token)
+			require.NotEmpty(t, payload)
+
+			payload, err = maker.VerifyToken(token)
+			require.NoError(t, err)
+			require.NotZero(t, payload.ID)
+			require.Equal(t, username, payload.Username)
+			require.Equal(t, role, payload.Role)
+			require.Equal(t, sessionID, payload.SessionID)
+			require.Equal(t, PurposeTotpChallenge, payload.Purpose)
+			require.Equal(t, actor, payload.Actor)
+			require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+			require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+
+			token, _, err = maker.CreateToken(username, role, -time.Minute)
+			require.NoError(t, err)
+			payload, err = maker.VerifyToken(token)
+			require.EqualError(t, err, ErrExpiredToken.Error())
+			require.Nil(t, payload)
+		})
+	}
+}
+
+func TestPasetoV4LocalMakerOptions(t *testing.T) {
+	secretKey := util.RandomString(32)
+	options := randomPasetoV4Options()
+	maker, err := NewPasetoV4LocalMaker(secretKey, options)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		update func(options *PasetoV4Options)
+	}{
+		{
+			name:   "OtherIssuer",
+			update: func(options *PasetoV4Options) { options.Issuer = util.RandomString(6) },
+		},
+		{
+			name:   "OtherAudience",
+			update: func(options *PasetoV4Options) { options.Audience = util.RandomString(6) },
+		},
+		{
+			name:   "OtherFooter",
+			update: func(options *PasetoV4Options) { options.Footer = util.RandomString(6) },
+		},
+		{
+			name:   "NoFooter",
+			update: func(options *PasetoV4Options) { options.Footer = "" },
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			otherOptions := options
+			tc.update(&otherOptions)
+			otherMaker, err := NewPasetoV4LocalMaker(secretKey, otherOptions)
+			require.NoError(t, err)
+
+			token, _, err := otherMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+			require.NoError(t, err)
+
+			payload, err := maker.VerifyToken(token)
+			require.EqualError(t, err, ErrInvalidToken.Error())
+			require.Nil(t, payload)
+		})
+	}
+}
+
+func TestPasetoV4LocalMakerInvalidKey(t *testing.T) {
+	maker, err := NewPasetoV4LocalMaker(util.RandomString(16), PasetoV4Options{})
+	require.Error(t, err)
+	require.Nil(t, maker)
+
+	maker, err = NewPasetoV4LocalMaker(util.RandomString(32), PasetoV4Options{})
+	require.NoError(t, err)
+	otherMaker, err := NewPasetoV4LocalMaker(util.RandomString(32), PasetoV4Options{})
+	require.NoError(t, err)
+
+	token, _, err := otherMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoV4PublicMakerKeyRotation(t *testing.T) {
+	options := randomPasetoV4Options()
+	oldKey := randomEd25519Key(t)
+	newKey := randomEd25519Key(t)
+
+	oldMaker, err := NewPasetoV4PublicMaker(options, oldKey)
+	require.NoError(t, err)
+	newMaker, err := NewPasetoV4PublicMaker(options, newKey, oldKey.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	unknownMaker, err := NewPasetoV4PublicMaker(options, randomEd25519Key(t))
+	require.NoError(t, err)
+
+	oldToken, _, err := oldMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	newToken, _, err := newMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	// tokens signed before the rotation are still accepted
+	_, err = newMaker.VerifyToken(oldToken)
+	require.NoError(t, err)
+	_, err = newMaker.VerifyToken(newToken)
+	require.NoError(t, err)
+
+	// the old maker does not know the new key
+	_, err = oldMaker.VerifyToken(newToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	_, err = unknownMaker.VerifyToken(oldToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+}
+
+func TestNewMaker(t *testing.T) {
+	testCases := []struct {
+		name      string
+		tokenType string
+		makerType Maker
+	}{
+		{
+			name:      "Default",
+			tokenType: "",
+			makerType: &JwtMaker{},
+		},
+		{
+			name:      "Jwt",
+			tokenType: TypeJwt,
+			makerType: &JwtMaker{},
+		},
+		{
+			name:      "PasetoV2",
+			tokenType: TypePasetoV2,
+			makerType: &PasetoMaker{},
+		},
+		{
+			name:      "PasetoV4Local",
+			tokenType: TypePasetoV4Local,
+			makerType: &PasetoV4LocalMaker{},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			maker, err := NewMaker(util.Config{
+				TokenType:   tc.tokenType,
+				TokenString: util.RandomString(32),
+			})
+			require.NoError(t, err)
+			require.IsType(t, tc.makerType, maker)
+		})
+	}
+
+	maker, err := NewMaker(util.Config{TokenType: "unknown", TokenString: util.RandomString(32)})
+	require.Error(t, err)
+	require.Nil(t, maker)
+}
+
+func TestNewMakerPasetoV4Public(t *testing.T) {
+	der, err := x509.MarshalPKCS8PrivateKey(randomEd25519Key(t))
+	require.NoError(t, err)
+	signingKeyFile := filepath.Join(t.TempDir(), "signing.pem")
+	require.NoError(t, os.WriteFile(signingKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+	maker, err := NewMaker(util.Config{
+		TokenType:           TypePasetoV4Public,
+		TokenSigningKeyFile: signingKeyFile,
+		TokenIssuer:         util.RandomString(6),
+	})
+	require.NoError(t, err)
+	require.IsType(t, &PasetoV4PublicMaker{}, maker)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+}
@@ -2,6 +2,7 @@ package token
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/gofrs/uuid/v5"
@@ -21,10 +22,48 @@ type Payload struct {
 	Role      string    `json:"role"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
+	// SessionID of the login session the token belongs to, empty for tokens without session
+	SessionID uuid.UUID `json:"session_id"`
+	// Purpose of the token which is not accepted for authorization, empty for access tokens
+	Purpose string `json:"purpose,omitempty"`
+	// Scopes allowed for the API key, nil for tokens of the user which are not restricted
+	Scopes []string `json:"scopes,omitempty"`
+	// Actor username of the banker impersonating the user, empty for tokens issued to the user
+	Actor string `json:"actor,omitempty"`
+}
+
+// PurposeTotpChallenge token returned by login when the second factor is still required
+const PurposeTotpChallenge = "totp_challenge"
+
+// PurposeRefresh token which can only be exchanged for the new access token
+const PurposeRefresh = "refresh"
+
+// PayloadOption set optional field of the token payload
+type PayloadOption func(*Payload)
+
+// WithSessionID bind token to the login session, so revoking the session revokes the token
+func WithSessionID(sessionID uuid.UUID) PayloadOption {
+	return func(payload *Payload) {
+		payload.SessionID = sessionID
+	}
+}
+
+// WithPurpose limit the token to the single purpose, such token is not accepted for authorization
+func WithPurpose(purpose string) PayloadOption {
+	return func(payload *Payload) {
+		payload.Purpose = purpose
+	}
+}
+
+// WithActor mark the token as issued to the actor impersonating the user
+func WithActor(actor string) PayloadOption {
+	return func(payload *Payload) {
+		payload.Actor = actor
+	}
 }
 
 // NewPayload get username and duration and create new token payload
-func NewPayload(username string, role string, duration time.Duration) (*Payload, error) {
+func NewPayload(username string, role string, duration time.Duration, opts ...PayloadOption) (*Payload, error) {
 	tokenID, err := uuid.NewV4()
 	if err != nil {
 		return nil, err
@@ -36,10 +75,23 @@ func NewPayload(username string, role string, duration time.Duration) (*Payload,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
+	for _, opt := range opts {
+		opt(payload)
+	}
 
 	return payload, nil
 }
 
+// HasScope report if the payload is allowed to use the scope
+func (payload Payload) HasScope(scope string) bool {
+	return payload.Scopes == nil || slices.Contains(payload.Scopes, scope)
+}
+
+// IsImpersonated report if the token was issued to the actor impersonating the user
+func (payload Payload) IsImpersonated() bool {
+	return payload.Actor != ""
+}
+
 func (payload Payload) Valid() error {
 	if time.Now().After(payload.ExpiredAt) {
 		return ErrExpiredToken
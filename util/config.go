@@ -9,19 +9,58 @@ import (
 // Config store all configuration of the application
 // the values read by viper from config file or enviroment variables
 type Config struct {
-	Enviroment           string        `mapstructure:"ENVIROMENT"`
-	DBSource             string        `mapstructure:"DB_SOURCE"`
-	RedisAddress         string        `mapstructure:"REDIS_ADDRESS"`
-	MigrationURL         string        `mapstructure:"MIGRATION_URL"`
-	HTTPAddressString    string        `mapstructure:"HTTP_ADDRESS_STRING"`
-	GRPCAddressString    string        `mapstructure:"GRPC_ADDRESS_STRING"`
-	TokenString          string        `mapstructure:"TOKEN_STRING"`
-	TokenDuration        time.Duration `mapstructure:"TOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
-	EmailSenderName      string        `mapstructure:"EMAIL_SENDER_NAME"`
-	EmailSenderEmailFrom string        `mapstructure:"EMAIL_SENDER_EMAIL_FROM"`
-	MailtrapLogin        string        `mapstructure:"MAILTRAP_LOGIN"`
-	MailtrapPass         string        `mapstructure:"MAILTRAP_PASS"`
+	Enviroment            string        `mapstructure:"ENVIROMENT"`
+	DBSource              string        `mapstructure:"DB_SOURCE"`
+	RedisAddress          string        `mapstructure:"REDIS_ADDRESS"`
+	MigrationURL          string        `mapstructure:"MIGRATION_URL"`
+	HTTPAddressString     string        `mapstructure:"HTTP_ADDRESS_STRING"`
+	GRPCAddressString     string        `mapstructure:"GRPC_ADDRESS_STRING"`
+	TokenType             string        `mapstructure:"TOKEN_TYPE"`
+	TokenString           string        `mapstructure:"TOKEN_STRING"`
+	TokenSigningKeyFile   string        `mapstructure:"TOKEN_SIGNING_KEY_FILE"`
+	TokenVerifyKeyFiles   []string      `mapstructure:"TOKEN_VERIFY_KEY_FILES"`
+	TokenIssuer           string        `mapstructure:"TOKEN_ISSUER"`
+	TokenAudience         string        `mapstructure:"TOKEN_AUDIENCE"`
+	TokenFooter           string        `mapstructure:"TOKEN_FOOTER"`
+	TokenDuration         time.Duration `mapstructure:"TOKEN_DURATION"`
+	RefreshTokenDuration  time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	EmailSenderName       string        `mapstructure:"EMAIL_SENDER_NAME"`
+	EmailSenderEmailFrom  string        `mapstructure:"EMAIL_SENDER_EMAIL_FROM"`
+	MailtrapLogin         string        `mapstructure:"MAILTRAP_LOGIN"`
+	MailtrapPass          string        `mapstructure:"MAILTRAP_PASS"`
+	OutboxPollInterval    time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL"`
+	FraudReviewScore      int32         `mapstructure:"FRAUD_REVIEW_SCORE"`
+	FraudBlockScore       int32         `mapstructure:"FRAUD_BLOCK_SCORE"`
+	FraudVelocityCount    int64         `mapstructure:"FRAUD_VELOCITY_COUNT"`
+	FraudVelocityWindow   time.Duration `mapstructure:"FRAUD_VELOCITY_WINDOW"`
+	FraudLargeAmount      int64         `mapstructure:"FRAUD_LARGE_AMOUNT"`
+	FraudMinSessionAge    time.Duration `mapstructure:"FRAUD_MIN_SESSION_AGE"`
+	TransferReviewAmount  int64         `mapstructure:"TRANSFER_REVIEW_AMOUNT"`
+	TotpChallengeDuration time.Duration `mapstructure:"TOTP_CHALLENGE_DURATION"`
+	TotpTransferAmount    int64         `mapstructure:"TOTP_TRANSFER_AMOUNT"`
+	LoginMaxAttempts      int64         `mapstructure:"LOGIN_MAX_ATTEMPTS"`
+	LoginIpMaxAttempts    int64         `mapstructure:"LOGIN_IP_MAX_ATTEMPTS"`
+	LoginAttemptWindow    time.Duration `mapstructure:"LOGIN_ATTEMPT_WINDOW"`
+	LoginLockoutDuration  time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	LoginBaseDelay        time.Duration `mapstructure:"LOGIN_BASE_DELAY"`
+	AuthCacheTTL          time.Duration `mapstructure:"AUTH_CACHE_TTL"`
+	PasswordArgon2Time    uint32        `mapstructure:"PASSWORD_ARGON2_TIME"`
+	PasswordArgon2Memory  uint32        `mapstructure:"PASSWORD_ARGON2_MEMORY"`
+	PasswordArgon2Threads uint8         `mapstructure:"PASSWORD_ARGON2_THREADS"`
+	PasswordMinClasses    int           `mapstructure:"PASSWORD_MIN_CLASSES"`
+	PasswordMinEntropy    float64       `mapstructure:"PASSWORD_MIN_ENTROPY"`
+	PasswordBreachedFile  string        `mapstructure:"PASSWORD_BREACHED_FILE"`
+	VerifyEmailMaxResends int64         `mapstructure:"VERIFY_EMAIL_MAX_RESENDS"`
+	VerifyEmailWindow     time.Duration `mapstructure:"VERIFY_EMAIL_WINDOW"`
+	LoginLinkMaxEmails    int64         `mapstructure:"LOGIN_LINK_MAX_EMAILS"`
+	LoginLinkIpMaxEmails  int64         `mapstructure:"LOGIN_LINK_IP_MAX_EMAILS"`
+	LoginLinkWindow       time.Duration `mapstructure:"LOGIN_LINK_WINDOW"`
+	UnverifiedMaxTransfer int64         `mapstructure:"UNVERIFIED_MAX_TRANSFER"`
+	ImpersonationDuration time.Duration `mapstructure:"IMPERSONATION_DURATION"`
+
+	PasswordResetMaxEmails   int64         `mapstructure:"PASSWORD_RESET_MAX_EMAILS"`
+	PasswordResetIpMaxEmails int64         `mapstructure:"PASSWORD_RESET_IP_MAX_EMAILS"`
+	PasswordResetWindow      time.Duration `mapstructure:"PASSWORD_RESET_WINDOW"`
 }
 
 // LoadConfig read configuration from config file or enviroment variables
@@ -1,21 +1,141 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword generate password hash or return error
+const (
+	argon2idPrefix   = "$argon2id$"
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var (
+	ErrPasswordMismatch    = bcrypt.ErrMismatchedHashAndPassword
+	ErrUnknownPasswordHash = errors.New("unknown password hash format")
+)
+
+// PasswordParams cost of argon2id, memory is in KiB
+type PasswordParams struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+// DefaultPasswordParams follow the OWASP recommendation for argon2id
+var DefaultPasswordParams = PasswordParams{
+	Time:    2,
+	Memory:  19 * 1024,
+	Threads: 1,
+}
+
+var passwordParams = DefaultPasswordParams
+
+// SetPasswordParams change cost of the new hashes, zero values keep the default,
+// hashes with other parameters are upgraded on the next login
+func SetPasswordParams(params PasswordParams) {
+	if params.Time == 0 {
+		params.Time = DefaultPasswordParams.Time
+	}
+	if params.Memory == 0 {
+		params.Memory = DefaultPasswordParams.Memory
+	}
+	if params.Threads == 0 {
+		params.Threads = DefaultPasswordParams.Threads
+	}
+	passwordParams = params
+}
+
+// HashPassword generate argon2id password hash in PHC string format:
+// $argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>
 func HashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", fmt.Errorf("error: can not generate hash from password - %v", err)
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("error: can not generate salt for password - %v", err)
 	}
-	return string(hash), nil
+	params := passwordParams
+	key := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, argon2KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		params.Memory,
+		params.Time,
+		params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
-// CheckPassword check if provided password correct or not.
+// CheckPassword check if provided password correct or not,
+// legacy bcrypt hashes are still accepted
 func CheckPassword(password, hash string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if isBcryptHash(hash) {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	}
+	params, salt, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return err
+	}
+	other := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+// dummySalt fixed salt of the password checked for unknown users
+var dummySalt = make([]byte, argon2SaltLength)
+
+// CheckDummyPassword spend the same time as CheckPassword of the hash with
+// the current parameters. It is called when the user does not exist, so the
+// response time does not tell whether the username is registered.
+func CheckDummyPassword(password string) {
+	params := passwordParams
+	argon2.IDKey([]byte(password), dummySalt, params.Time, params.Memory, params.Threads, argon2KeyLength)
+}
+
+// NeedsRehash report if the hash is not argon2id with the current parameters
+func NeedsRehash(hash string) bool {
+	params, _, _, err := decodeArgon2idHash(hash)
+	return err != nil || params != passwordParams
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func decodeArgon2idHash(hash string) (params PasswordParams, salt []byte, key []byte, err error) {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads)
+	// argon2 panics on zero cost
+	if err != nil || params.Memory == 0 || params.Time == 0 || params.Threads == 0 {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	return params, salt, key, nil
 }
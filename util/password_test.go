@@ -1,7 +1,9 @@
 package util
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
@@ -23,3 +25,67 @@ func TestHashPassword(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEqual(t, hash, hash2)
 }
+
+func TestCheckDummyPassword(t *testing.T) {
+	password := RandomString(8)
+	hash, err := HashPassword(password)
+	require.NoError(t, err)
+
+	start := time.Now()
+	require.NoError(t, CheckPassword(password, hash))
+	checkDuration := time.Since(start)
+
+	start = time.Now()
+	CheckDummyPassword(password)
+	dummyDuration := time.Since(start)
+
+	// the bound is loose, the test only catches the check which is skipped
+	require.Greater(t, dummyDuration, checkDuration/4)
+}
+
+func TestHashPasswordArgon2id(t *testing.T) {
+	hash, err := HashPassword(RandomString(8))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$"))
+	require.False(t, NeedsRehash(hash))
+
+	// passwords are not limited by 72 bytes of bcrypt
+	password := RandomString(100)
+	hash, err = HashPassword(password)
+	require.NoError(t, err)
+	require.NoError(t, CheckPassword(password, hash))
+	require.ErrorIs(t, CheckPassword(password[:72], hash), ErrPasswordMismatch)
+}
+
+func TestCheckPasswordBcrypt(t *testing.T) {
+	password := RandomString(8)
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	require.NoError(t, CheckPassword(password, string(hash)))
+	require.ErrorIs(t, CheckPassword(RandomString(8), string(hash)), ErrPasswordMismatch)
+	require.True(t, NeedsRehash(string(hash)))
+}
+
+func TestCheckPasswordInvalidHash(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"plain",
+		"$argon2id$v=19$m=0,t=0,p=0$c2FsdA$a2V5",
+		"$argon2id$v=16$m=19456,t=2,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=19456,t=2,p=1$c2FsdA",
+	} {
+		require.ErrorIs(t, CheckPassword(RandomString(8), hash), ErrUnknownPasswordHash, hash)
+		require.True(t, NeedsRehash(hash))
+	}
+}
+
+func TestSetPasswordParams(t *testing.T) {
+	hash, err := HashPassword(RandomString(8))
+	require.NoError(t, err)
+
+	SetPasswordParams(PasswordParams{Time: 3})
+	defer SetPasswordParams(DefaultPasswordParams)
+	require.Equal(t, PasswordParams{Time: 3, Memory: DefaultPasswordParams.Memory, Threads: DefaultPasswordParams.Threads}, passwordParams)
+	require.True(t, NeedsRehash(hash))
+}
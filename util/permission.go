@@ -0,0 +1,35 @@
+package util
+
+// Permissions which can be granted to roles
+const (
+	PermissionAccountsReadAny   = "accounts:read:any"
+	PermissionUsersUpdateAny    = "users:update:any"
+	PermissionUsersUnlock       = "users:unlock"
+	PermissionSessionsRevokeAny = "sessions:revoke:any"
+	PermissionTransfersApprove  = "transfers:approve"
+	PermissionRolesAssign       = "roles:assign"
+	PermissionUsersImpersonate  = "users:impersonate"
+	PermissionUsersReadAny      = "users:read:any"
+)
+
+// Permissions registry of all known permissions
+var Permissions = []string{
+	PermissionAccountsReadAny,
+	PermissionUsersUpdateAny,
+	PermissionUsersUnlock,
+	PermissionSessionsRevokeAny,
+	PermissionTransfersApprove,
+	PermissionRolesAssign,
+	PermissionUsersImpersonate,
+	PermissionUsersReadAny,
+}
+
+// IfSupportedPermission check if the permission is in the registry
+func IfSupportedPermission(permission string) bool {
+	for _, p := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
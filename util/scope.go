@@ -0,0 +1,19 @@
+package util
+
+// Scopes which can be granted to API keys
+const (
+	ScopeUsersRead      = "users:read"
+	ScopeAccountsRead   = "accounts:read"
+	ScopeAccountsWrite  = "accounts:write"
+	ScopeTransfersWrite = "transfers:write"
+	ScopeWebhooks       = "webhooks"
+)
+
+// IfSupportedScope check if the scope can be granted to API key
+func IfSupportedScope(scope string) bool {
+	switch scope {
+	case ScopeUsersRead, ScopeAccountsRead, ScopeAccountsWrite, ScopeTransfersWrite, ScopeWebhooks:
+		return true
+	}
+	return false
+}
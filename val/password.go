@@ -0,0 +1,208 @@
+package val
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/nbutton23/zxcvbn-go"
+	"github.com/rs/zerolog/log"
+)
+
+// minUserInputLength shorter parts of username or email are not checked
+const minUserInputLength = 3
+
+// PasswordPolicy rules for new passwords, zero value checks only the length
+type PasswordPolicy struct {
+	// MinClasses of lower case letters, upper case letters, digits and symbols
+	MinClasses int
+	// MinEntropy bits estimated by zxcvbn
+	MinEntropy float64
+	Breached   *BreachedList
+}
+
+// NewPasswordPolicy creates a new PasswordPolicy from config
+// and load the breached passwords list if it is set
+func NewPasswordPolicy(config util.Config) (PasswordPolicy, error) {
+	policy := PasswordPolicy{
+		MinClasses: config.PasswordMinClasses,
+		MinEntropy: config.PasswordMinEntropy,
+	}
+	if config.PasswordBreachedFile != "" {
+		list, err := LoadBreachedList(config.PasswordBreachedFile)
+		if err != nil {
+			return policy, err
+		}
+		policy.Breached = list
+	}
+	return policy, nil
+}
+
+// Validate return every rule the password breaks, userInputs like username
+// and email must not be a part of the password
+func (policy PasswordPolicy) Validate(password string, userInputs ...string) (errs []error) {
+	if err := ValidatePassword(password); err != nil {
+		return []error{err}
+	}
+	if classes := passwordClasses(password); classes < policy.MinClasses {
+		errs = append(errs, fmt.Errorf("must contain at least %d of: low case letters, upper case letters, numbers, symbols", policy.MinClasses))
+	}
+	inputs := passwordUserInputs(userInputs)
+	lowerPassword := strings.ToLower(password)
+	for _, input := range inputs {
+		if strings.Contains(lowerPassword, input) {
+			errs = append(errs, fmt.Errorf("must not contain username or email"))
+			break
+		}
+	}
+	if policy.MinEntropy > 0 {
+		if strength := zxcvbn.PasswordStrength(password, inputs); strength.Entropy < policy.MinEntropy {
+			errs = append(errs, fmt.Errorf("is too easy to guess"))
+		}
+	}
+	if policy.Breached != nil && policy.Breached.Contains(password) {
+		errs = append(errs, fmt.Errorf("appears in a list of breached passwords"))
+	}
+	return errs
+}
+
+func passwordClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+// passwordUserInputs split email into local part and domain, so both are checked
+func passwordUserInputs(userInputs []string) (inputs []string) {
+	for _, input := range userInputs {
+		input = strings.ToLower(input)
+		parts := []string{input}
+		if local, domain, ok := strings.Cut(input, "@"); ok {
+			parts = append(parts, local, domain)
+		}
+		for _, part := range parts {
+			if len(part) >= minUserInputLength {
+				inputs = append(inputs, part)
+			}
+		}
+	}
+	return inputs
+}
+
+// BreachedList file of SHA-1 hashes of breached passwords sorted by hash,
+// like the one from the Have I Been Pwned downloader. The file is not loaded
+// into memory, every lookup is a binary search over the lines of the file.
+type BreachedList struct {
+	file *os.File
+	size int64
+}
+
+// LoadBreachedList open the file with one "HASH" or "HASH:COUNT" per line,
+// where HASH is hex SHA-1 of the password and lines are sorted by HASH
+func LoadBreachedList(path string) (*BreachedList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open breached passwords list: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("cannot read breached passwords list: %w", err)
+	}
+
+	list := &BreachedList{file: file, size: info.Size()}
+	// catch the wrong file at start instead of on every lookup
+	if _, _, err := list.hashAt(0); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return list, nil
+}
+
+// Close the file of the list
+func (list *BreachedList) Close() error {
+	return list.file.Close()
+}
+
+// Contains check if the password is in the list
+func (list *BreachedList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	// lo is always the start of a line, lines starting before lo are less than hash
+	// and lines starting at hi or after are greater than hash
+	lo, hi := int64(0), list.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		lineHash, next, err := list.hashAt(mid)
+		if err != nil {
+			log.Error().Err(err).Msg("cannot check breached passwords list")
+			return false
+		}
+		start := next - int64(len(lineHash))
+		if lineHash == "" || start >= hi {
+			hi = mid
+			continue
+		}
+		switch strings.Compare(hash, lineHash) {
+		case 0:
+			return true
+		case -1:
+			hi = start
+		default:
+			lo = next
+		}
+	}
+	return false
+}
+
+// hashAt return the hash from the first not empty line starting at offset or
+// after it, next is the offset after the hash. The hash is empty at the end of the file.
+func (list *BreachedList) hashAt(offset int64) (hash string, next int64, err error) {
+	if offset > 0 {
+		// the line containing offset-1 started before offset, skip it
+		offset--
+	}
+	reader := bufio.NewReader(io.NewSectionReader(list.file, offset, list.size-offset))
+	if offset > 0 {
+		skipped, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", 0, fmt.Errorf("cannot read breached passwords list: %w", err)
+		}
+		offset += int64(len(skipped))
+	}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", 0, fmt.Errorf("cannot read breached passwords list: %w", err)
+		}
+		hash, _, _ = strings.Cut(strings.TrimSpace(line), ":")
+		if hash != "" {
+			if len(hash) != sha1.Size*2 {
+				return "", 0, fmt.Errorf("invalid hash in breached passwords list at offset %d", offset)
+			}
+			return strings.ToUpper(hash), offset + int64(len(hash)), nil
+		}
+		offset += int64(len(line))
+		if err == io.EOF {
+			return "", offset, nil
+		}
+	}
+}
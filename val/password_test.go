@@ -0,0 +1,68 @@
+package val
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBreachedList(t *testing.T) {
+	dir := t.TempDir()
+
+	// sha1 of "password" in upper and lower case, with and without count
+	file := filepath.Join(dir, "breached.txt")
+	content := "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\n\n7c4a8d09ca3762af61e59520943dc26494f8941b\n"
+	require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+
+	list, err := LoadBreachedList(file)
+	require.NoError(t, err)
+	defer list.Close()
+	require.True(t, list.Contains("password"))
+	require.True(t, list.Contains("123456"))
+	require.False(t, list.Contains("correct-Horse-battery-5taple"))
+
+	invalidFile := filepath.Join(dir, "invalid.txt")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("5BAA61E4:1\n"), 0600))
+	_, err = LoadBreachedList(invalidFile)
+	require.Error(t, err)
+
+	_, err = LoadBreachedList(filepath.Join(dir, "missing.txt"))
+	require.Error(t, err)
+}
+
+func TestBreachedListSearch(t *testing.T) {
+	var passwords, hashes []string
+	for i := 0; i < 1000; i++ {
+		password := fmt.Sprintf("password-%d", i)
+		sum := sha1.Sum([]byte(password))
+		passwords = append(passwords, password)
+		hashes = append(hashes, fmt.Sprintf("%X:%d", sum, i+1))
+	}
+	sort.Strings(hashes)
+
+	file := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(file, []byte(strings.Join(hashes, "\r\n")), 0600))
+	list, err := LoadBreachedList(file)
+	require.NoError(t, err)
+	defer list.Close()
+
+	for _, password := range passwords {
+		require.True(t, list.Contains(password), password)
+		require.False(t, list.Contains(password+"!"), password)
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	policy := PasswordPolicy{MinClasses: 3, MinEntropy: 40}
+
+	require.Empty(t, policy.Validate("correct-Horse-battery-5taple", "alice", "alice@example.com"))
+	require.Len(t, policy.Validate("short"), 1)
+	require.Len(t, policy.Validate("Staple-Horse-91-Bobby", "robert", "bobby@example.com"), 1)
+	require.Empty(t, PasswordPolicy{}.Validate("aaaaaaaa"))
+}
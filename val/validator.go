@@ -3,15 +3,25 @@ package val
 import (
 	"fmt"
 	"net/mail"
+	"net/netip"
+	"net/url"
 	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	db "github.com/dubass83/simplebank/db/sqlc"
 	"github.com/dubass83/simplebank/util"
 )
 
+// maxApiKeyLifetime keys have to be rotated at least once a year
+const maxApiKeyLifetime = 365 * 24 * time.Hour
+
 var (
 	isValidUsername = regexp.MustCompile(`^[a-z0-9_]+$`).MatchString
 	isValidFullName = regexp.MustCompile(`^[a-zA-Z\s]+$`).MatchString
+	isValidTotpCode = regexp.MustCompile(`^[0-9]{6}$`).MatchString
+	isValidRecovery = regexp.MustCompile(`^[a-z2-7]{5}-?[a-z2-7]{5}$`).MatchString
 )
 
 func validateString(val string, min, max int) error {
@@ -32,6 +42,16 @@ func ValidateUsername(username string) error {
 	return nil
 }
 
+func ValidateUsernamePrefix(prefix string) error {
+	if err := validateString(prefix, 1, 20); err != nil {
+		return err
+	}
+	if !isValidUsername(prefix) {
+		return fmt.Errorf("must contain only low case letters number and _")
+	}
+	return nil
+}
+
 func ValidateFullname(fullName string) error {
 	if err := validateString(fullName, 4, 100); err != nil {
 		return err
@@ -43,7 +63,7 @@ func ValidateFullname(fullName string) error {
 }
 
 func ValidatePassword(password string) error {
-	return validateString(password, 8, 21)
+	return validateString(password, 8, 128)
 }
 
 func ValidateEmail(email string) error {
@@ -113,3 +133,140 @@ func ValidateVerifyEmailID(id int64) error {
 func ValidateVerifyEmailSecretCode(secretCode string) error {
 	return validateString(secretCode, 32, 128)
 }
+
+func ValidateWebhookURL(webhookURL string) error {
+	if err := validateString(webhookURL, 10, 2048); err != nil {
+		return err
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return fmt.Errorf("is not a valid url")
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("url scheme must be https")
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("url must contain host")
+	}
+	// names are checked again by the worker when the address is resolved
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return fmt.Errorf("url host must be public")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicAddr(addr) {
+		return fmt.Errorf("url host must be public")
+	}
+	return nil
+}
+
+// nonPublicPrefixes ranges which are not covered by the netip.Addr checks
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// IsPublicAddr report if the address is routable on the internet, loopback,
+// private, link-local (including cloud metadata) and multicast addresses are not
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func ValidateWebhookEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("at least one event type is required")
+	}
+	for i, eventType := range eventTypes {
+		if !slices.Contains(db.WebhookEventTypes, eventType) {
+			return fmt.Errorf("not supported event type: %s", eventType)
+		}
+		if slices.Contains(eventTypes[:i], eventType) {
+			return fmt.Errorf("duplicated event type: %s", eventType)
+		}
+	}
+	return nil
+}
+
+func ValidateWebhookSecret(secret string) error {
+	return validateString(secret, 16, 128)
+}
+
+func ValidateApiKeyName(name string) error {
+	return validateString(name, 1, 100)
+}
+
+func ValidateApiKeyScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for i, scope := range scopes {
+		if !util.IfSupportedScope(scope) {
+			return fmt.Errorf("not supported scope: %s", scope)
+		}
+		if slices.Contains(scopes[:i], scope) {
+			return fmt.Errorf("duplicated scope: %s", scope)
+		}
+	}
+	return nil
+}
+
+// ValidatePermissions check that the role grants only permissions from the registry
+func ValidatePermissions(permissions []string) error {
+	for _, permission := range permissions {
+		if !util.IfSupportedPermission(permission) {
+			return fmt.Errorf("not supported permission: %s", permission)
+		}
+	}
+	return nil
+}
+
+func ValidateApiKeyExpiredAt(expiredAt time.Time) error {
+	now := time.Now()
+	if !expiredAt.After(now) {
+		return fmt.Errorf("must be in the future")
+	}
+	if expiredAt.After(now.Add(maxApiKeyLifetime)) {
+		return fmt.Errorf("must be within %s", maxApiKeyLifetime)
+	}
+	return nil
+}
+
+func ValidateRefreshToken(refreshToken string) error {
+	return validateString(refreshToken, 1, 4096)
+}
+
+func ValidateTotpCode(code string) error {
+	if !isValidTotpCode(code) {
+		return fmt.Errorf("must contain exactly 6 digits")
+	}
+	return nil
+}
+
+func ValidateRecoveryCode(code string) error {
+	if !isValidRecovery(code) {
+		return fmt.Errorf("is not a valid recovery code")
+	}
+	return nil
+}
+
+func ValidateRoleName(role string) error {
+	if err := validateString(role, 1, 50); err != nil {
+		return err
+	}
+	if !isValidUsername(role) {
+		return fmt.Errorf("must contain only low case letters number and _")
+	}
+	return nil
+}
+
+func ValidateImpersonationReason(reason string) error {
+	return validateString(reason, 10, 500)
+}
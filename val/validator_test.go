@@ -0,0 +1,44 @@
+package val
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/dubass83/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	for addr, public := range map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fd00:ec2::254":    false,
+		"fe80::1":          false,
+		"::ffff:127.0.0.1": false,
+	} {
+		require.Equal(t, public, IsPublicAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	require.NoError(t, ValidateWebhookURL("https://example.com/hooks"))
+	require.Error(t, ValidateWebhookURL("http://example.com/hooks"))
+	require.Error(t, ValidateWebhookURL("https://localhost/hooks"))
+	require.Error(t, ValidateWebhookURL("https://127.0.0.1/hooks"))
+	require.Error(t, ValidateWebhookURL("https://169.254.169.254/latest/meta-data"))
+	require.Error(t, ValidateWebhookURL("https://[::1]:8443/hooks"))
+}
+
+func TestValidatePermissions(t *testing.T) {
+	require.NoError(t, ValidatePermissions(util.Permissions))
+	require.NoError(t, ValidatePermissions(nil))
+	require.Error(t, ValidatePermissions([]string{util.PermissionUsersUnlock, "accounts:delete:any"}))
+}
@@ -12,6 +12,36 @@ type TaskDistributor interface {
 		payload *PayloadSendVerifyEmail,
 		opts ...asynq.Option,
 	) error
+	DestributeTaskFanOutWebhookEvent(
+		ctx context.Context,
+		payload *PayloadFanOutWebhookEvent,
+		opts ...asynq.Option,
+	) error
+	DestributeTaskDeliverWebhook(
+		ctx context.Context,
+		payload *PayloadDeliverWebhook,
+		opts ...asynq.Option,
+	) error
+	DestributeTaskSendTransferReviewEmail(
+		ctx context.Context,
+		payload *PayloadSendTransferReviewEmail,
+		opts ...asynq.Option,
+	) error
+	DestributeTaskSendSecurityAlertEmail(
+		ctx context.Context,
+		payload *PayloadSendSecurityAlertEmail,
+		opts ...asynq.Option,
+	) error
+	DestributeTaskSendPasswordResetEmail(
+		ctx context.Context,
+		payload *PayloadSendPasswordResetEmail,
+		opts ...asynq.Option,
+	) error
+	DestributeTaskSendLoginLinkEmail(
+		ctx context.Context,
+		payload *PayloadSendLoginLinkEmail,
+		opts ...asynq.Option,
+	) error
 }
 
 type RedisTaskDistributor struct {
@@ -36,6 +36,120 @@ func (m *MockTaskDistributor) EXPECT() *MockTaskDistributorMockRecorder {
 	return m.recorder
 }
 
+// DestributeTaskDeliverWebhook mocks base method.
+func (m *MockTaskDistributor) DestributeTaskDeliverWebhook(arg0 context.Context, arg1 *worker.PayloadDeliverWebhook, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DestributeTaskDeliverWebhook", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DestributeTaskDeliverWebhook indicates an expected call of DestributeTaskDeliverWebhook.
+func (mr *MockTaskDistributorMockRecorder) DestributeTaskDeliverWebhook(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestributeTaskDeliverWebhook", reflect.TypeOf((*MockTaskDistributor)(nil).DestributeTaskDeliverWebhook), varargs...)
+}
+
+// DestributeTaskFanOutWebhookEvent mocks base method.
+func (m *MockTaskDistributor) DestributeTaskFanOutWebhookEvent(arg0 context.Context, arg1 *worker.PayloadFanOutWebhookEvent, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DestributeTaskFanOutWebhookEvent", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DestributeTaskFanOutWebhookEvent indicates an expected call of DestributeTaskFanOutWebhookEvent.
+func (mr *MockTaskDistributorMockRecorder) DestributeTaskFanOutWebhookEvent(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestributeTaskFanOutWebhookEvent", reflect.TypeOf((*MockTaskDistributor)(nil).DestributeTaskFanOutWebhookEvent), varargs...)
+}
+
+// DestributeTaskSendLoginLinkEmail mocks base method.
+func (m *MockTaskDistributor) DestributeTaskSendLoginLinkEmail(arg0 context.Context, arg1 *worker.PayloadSendLoginLinkEmail, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DestributeTaskSendLoginLinkEmail", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DestributeTaskSendLoginLinkEmail indicates an expected call of DestributeTaskSendLoginLinkEmail.
+func (mr *MockTaskDistributorMockRecorder) DestributeTaskSendLoginLinkEmail(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestributeTaskSendLoginLinkEmail", reflect.TypeOf((*MockTaskDistributor)(nil).DestributeTaskSendLoginLinkEmail), varargs...)
+}
+
+// DestributeTaskSendPasswordResetEmail mocks base method.
+func (m *MockTaskDistributor) DestributeTaskSendPasswordResetEmail(arg0 context.Context, arg1 *worker.PayloadSendPasswordResetEmail, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DestributeTaskSendPasswordResetEmail", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DestributeTaskSendPasswordResetEmail indicates an expected call of DestributeTaskSendPasswordResetEmail.
+func (mr *MockTaskDistributorMockRecorder) DestributeTaskSendPasswordResetEmail(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestributeTaskSendPasswordResetEmail", reflect.TypeOf((*MockTaskDistributor)(nil).DestributeTaskSendPasswordResetEmail), varargs...)
+}
+
+// DestributeTaskSendSecurityAlertEmail mocks base method.
+func (m *MockTaskDistributor) DestributeTaskSendSecurityAlertEmail(arg0 context.Context, arg1 *worker.PayloadSendSecurityAlertEmail, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DestributeTaskSendSecurityAlertEmail", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DestributeTaskSendSecurityAlertEmail indicates an expected call of DestributeTaskSendSecurityAlertEmail.
+func (mr *MockTaskDistributorMockRecorder) DestributeTaskSendSecurityAlertEmail(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestributeTaskSendSecurityAlertEmail", reflect.TypeOf((*MockTaskDistributor)(nil).DestributeTaskSendSecurityAlertEmail), varargs...)
+}
+
+// DestributeTaskSendTransferReviewEmail mocks base method.
+func (m *MockTaskDistributor) DestributeTaskSendTransferReviewEmail(arg0 context.Context, arg1 *worker.PayloadSendTransferReviewEmail, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DestributeTaskSendTransferReviewEmail", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DestributeTaskSendTransferReviewEmail indicates an expected call of DestributeTaskSendTransferReviewEmail.
+func (mr *MockTaskDistributorMockRecorder) DestributeTaskSendTransferReviewEmail(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestributeTaskSendTransferReviewEmail", reflect.TypeOf((*MockTaskDistributor)(nil).DestributeTaskSendTransferReviewEmail), varargs...)
+}
+
 // DestributeTaskSendVerifyEmail mocks base method.
 func (m *MockTaskDistributor) DestributeTaskSendVerifyEmail(arg0 context.Context, arg1 *worker.PayloadSendVerifyEmail, arg2 ...asynq.Option) error {
 	m.ctrl.T.Helper()
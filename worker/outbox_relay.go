@@ -0,0 +1,260 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	outboxBatchSize    = 100
+	outboxLockDuration = time.Minute
+	outboxMaxBackoff   = 5 * time.Minute
+)
+
+// OutboxRelay publish domain events from the outbox_events table to the redis queue.
+// Events are marked as sent only after the task was enqueued, so every event
+// is delivered at least once and task processors must tolerate duplicates.
+type OutboxRelay struct {
+	store       db.Store
+	distributor TaskDistributor
+	interval    time.Duration
+}
+
+func NewOutboxRelay(store db.Store, distributor TaskDistributor, interval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		store:       store,
+		distributor: distributor,
+		interval:    interval,
+	}
+}
+
+// Start poll the outbox until context is canceled
+func (relay *OutboxRelay) Start(ctx context.Context) error {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := relay.relayBatch(ctx); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("failed to relay outbox events")
+			}
+		}
+	}
+}
+
+// relayBatch claim pending events and publish them one by one
+func (relay *OutboxRelay) relayBatch(ctx context.Context) error {
+	events, err := relay.store.ClaimOutboxEvents(ctx, db.ClaimOutboxEventsParams{
+		LockedUntil: pgtype.Timestamptz{
+			Time:  time.Now().Add(outboxLockDuration),
+			Valid: true,
+		},
+		BatchSize: outboxBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	for _, event := range events {
+		err := relay.publish(ctx, event)
+		if err != nil {
+			log.Error().Err(err).Int64("event_id", event.ID).
+				Str("event_type", event.EventType).Msg("failed to publish outbox event")
+
+			err = relay.store.MarkOutboxEventFailed(ctx, db.MarkOutboxEventFailedParams{
+				ID: event.ID,
+				LastError: pgtype.Text{
+					String: err.Error(),
+					Valid:  true,
+				},
+				RetryAt: pgtype.Timestamptz{
+					Time:  time.Now().Add(outboxBackoff(event.Attempts)),
+					Valid: true,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to mark outbox event %d as failed: %w", event.ID, err)
+			}
+			continue
+		}
+
+		err = relay.store.MarkOutboxEventSent(ctx, event.ID)
+		if err != nil {
+			return fmt.Errorf("failed to mark outbox event %d as sent: %w", event.ID, err)
+		}
+	}
+	return nil
+}
+
+// publish enqueue tasks subscribed to the event type
+func (relay *OutboxRelay) publish(ctx context.Context, event db.OutboxEvent) error {
+	var err error
+
+	switch event.EventType {
+	case db.EventUserCreated:
+		err = relay.publishVerifyEmail(ctx, event)
+	case db.EventTransferCreated, db.EventAccountCreated, db.EventUserEmailVerified:
+		err = relay.publishWebhookEvent(ctx, event)
+	case db.EventTransferReviewed:
+		err = relay.publishTransferReviewEmail(ctx, event)
+	case db.EventRefreshTokenReused:
+		err = relay.publishRefreshTokenReusedEmail(ctx, event)
+	case db.EventUserLocked:
+		err = relay.publishUserLockedEmail(ctx, event)
+	case db.EventUserEmailChanged:
+		err = relay.publishEmailChange(ctx, event)
+	}
+
+	// the task was enqueued before, but the event was not marked as sent
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
+
+func (relay *OutboxRelay) publishVerifyEmail(ctx context.Context, event db.OutboxEvent) error {
+	var userCreated db.UserCreatedEvent
+	if err := json.Unmarshal(event.Payload, &userCreated); err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", err)
+	}
+	payload := &PayloadSendVerifyEmail{
+		Username: userCreated.Username,
+	}
+	return relay.distributor.DestributeTaskSendVerifyEmail(ctx, payload,
+		asynq.MaxRetry(10),
+		asynq.Queue(QueueCritical),
+		asynq.TaskID(outboxTaskID(event, TaskSendVerifyEmail)),
+	)
+}
+
+func (relay *OutboxRelay) publishTransferReviewEmail(ctx context.Context, event db.OutboxEvent) error {
+	var transferReviewed db.TransferReviewedEvent
+	if err := json.Unmarshal(event.Payload, &transferReviewed); err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", err)
+	}
+	payload := &PayloadSendTransferReviewEmail{
+		TransferID: transferReviewed.TransferID,
+	}
+	return relay.distributor.DestributeTaskSendTransferReviewEmail(ctx, payload,
+		asynq.MaxRetry(10),
+		asynq.Queue(QueueDefault),
+		asynq.TaskID(outboxTaskID(event, TaskSendTransferReviewEmail)),
+	)
+}
+
+func (relay *OutboxRelay) publishRefreshTokenReusedEmail(ctx context.Context, event db.OutboxEvent) error {
+	var tokenReused db.RefreshTokenReusedEvent
+	if err := json.Unmarshal(event.Payload, &tokenReused); err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", err)
+	}
+	payload := &PayloadSendSecurityAlertEmail{
+		Username:        tokenReused.Username,
+		Alert:           AlertRefreshTokenReused,
+		UserAgent:       tokenReused.UserAgent,
+		ClientIp:        tokenReused.ClientIp,
+		RevokedSessions: tokenReused.RevokedSessions,
+	}
+	return relay.distributor.DestributeTaskSendSecurityAlertEmail(ctx, payload,
+		asynq.MaxRetry(10),
+		asynq.Queue(QueueCritical),
+		asynq.TaskID(outboxTaskID(event, TaskSendSecurityAlertEmail)),
+	)
+}
+
+func (relay *OutboxRelay) publishUserLockedEmail(ctx context.Context, event db.OutboxEvent) error {
+	var userLocked db.UserLockedEvent
+	if err := json.Unmarshal(event.Payload, &userLocked); err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", err)
+	}
+	payload := &PayloadSendSecurityAlertEmail{
+		Username:    userLocked.Username,
+		Alert:       AlertAccountLocked,
+		UserAgent:   userLocked.UserAgent,
+		ClientIp:    userLocked.ClientIp,
+		LockedUntil: userLocked.LockedUntil,
+	}
+	return relay.distributor.DestributeTaskSendSecurityAlertEmail(ctx, payload,
+		asynq.MaxRetry(10),
+		asynq.Queue(QueueCritical),
+		asynq.TaskID(outboxTaskID(event, TaskSendSecurityAlertEmail)),
+	)
+}
+
+// publishEmailChange send the verify link to the new address and the notice to the old one
+func (relay *OutboxRelay) publishEmailChange(ctx context.Context, event db.OutboxEvent) error {
+	var emailChanged db.UserEmailChangedEvent
+	if err := json.Unmarshal(event.Payload, &emailChanged); err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", err)
+	}
+	verifyPayload := &PayloadSendVerifyEmail{
+		Username: emailChanged.Username,
+		Email:    emailChanged.NewEmail,
+	}
+	err := relay.distributor.DestributeTaskSendVerifyEmail(ctx, verifyPayload,
+		asynq.MaxRetry(10),
+		asynq.Queue(QueueCritical),
+		asynq.TaskID(outboxTaskID(event, TaskSendVerifyEmail)),
+	)
+	// on retry the first task may already exist, the second one must still be enqueued
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return err
+	}
+
+	alertPayload := &PayloadSendSecurityAlertEmail{
+		Username: emailChanged.Username,
+		Alert:    AlertEmailChangeRequested,
+		OldEmail: emailChanged.OldEmail,
+		NewEmail: emailChanged.NewEmail,
+	}
+	return relay.distributor.DestributeTaskSendSecurityAlertEmail(ctx, alertPayload,
+		asynq.MaxRetry(10),
+		asynq.Queue(QueueCritical),
+		asynq.TaskID(outboxTaskID(event, TaskSendSecurityAlertEmail)),
+	)
+}
+
+func (relay *OutboxRelay) publishWebhookEvent(ctx context.Context, event db.OutboxEvent) error {
+	owners, err := webhookEventOwners(event)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", err)
+	}
+	payload := &PayloadFanOutWebhookEvent{
+		EventID:   event.ID,
+		EventType: event.EventType,
+		Owners:    owners,
+		Payload:   event.Payload,
+	}
+	return relay.distributor.DestributeTaskFanOutWebhookEvent(ctx, payload,
+		asynq.MaxRetry(10),
+		asynq.Queue(QueueDefault),
+		asynq.TaskID(outboxTaskID(event, TaskFanOutWebhookEvent)),
+	)
+}
+
+// outboxTaskID build stable task id, so redis rejects the same task enqueued twice
+func outboxTaskID(event db.OutboxEvent, taskType string) string {
+	return fmt.Sprintf("outbox:%d:%s", event.ID, taskType)
+}
+
+// outboxBackoff return exponential delay before the next publish attempt
+func outboxBackoff(attempts int32) time.Duration {
+	if attempts > 8 {
+		return outboxMaxBackoff
+	}
+	backoff := time.Duration(1<<attempts) * time.Second
+	if backoff > outboxMaxBackoff {
+		return outboxMaxBackoff
+	}
+	return backoff
+}
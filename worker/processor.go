@@ -2,6 +2,8 @@ package worker
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	db "github.com/dubass83/simplebank/db/sqlc"
 	"github.com/dubass83/simplebank/mail"
@@ -17,17 +19,30 @@ const (
 
 type TaskProcessor interface {
 	ProcesTaskVerifyEmail(ctx context.Context, task *asynq.Task) error
+	ProcesTaskFanOutWebhookEvent(ctx context.Context, task *asynq.Task) error
+	ProcesTaskDeliverWebhook(ctx context.Context, task *asynq.Task) error
+	ProcesTaskSendTransferReviewEmail(ctx context.Context, task *asynq.Task) error
+	ProcesTaskSendSecurityAlertEmail(ctx context.Context, task *asynq.Task) error
+	ProcesTaskSendPasswordResetEmail(ctx context.Context, task *asynq.Task) error
+	ProcesTaskSendLoginLinkEmail(ctx context.Context, task *asynq.Task) error
 	Start() error
 	Stop()
 }
 
 type RedisTaskProcessor struct {
-	server *asynq.Server
-	store  db.Store
-	sender mail.EmailSender
+	server        *asynq.Server
+	store         db.Store
+	sender        mail.EmailSender
+	distributor   TaskDistributor
+	webhookClient *http.Client
 }
 
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, sender mail.EmailSender) TaskProcessor {
+func NewRedisTaskProcessor(
+	redisOpt asynq.RedisClientOpt,
+	store db.Store,
+	sender mail.EmailSender,
+	distributor TaskDistributor,
+) TaskProcessor {
 	server := asynq.NewServer(
 		redisOpt,
 		asynq.Config{
@@ -40,14 +55,22 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, sender
 				log.Error().Err(err).Str("type", task.Type()).
 					Bytes("payload", task.Payload()).Msg("process task failed")
 			}),
+			RetryDelayFunc: func(n int, err error, task *asynq.Task) time.Duration {
+				if task.Type() == TaskDeliverWebhook {
+					return webhookRetryDelay(n)
+				}
+				return asynq.DefaultRetryDelayFunc(n, err, task)
+			},
 			Logger: NewLoger(),
 		},
 	)
 
 	return &RedisTaskProcessor{
-		server: server,
-		store:  store,
-		sender: sender,
+		server:        server,
+		store:         store,
+		sender:        sender,
+		distributor:   distributor,
+		webhookClient: newWebhookClient(),
 	}
 }
 
@@ -55,6 +78,12 @@ func (processor *RedisTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
 
 	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcesTaskVerifyEmail)
+	mux.HandleFunc(TaskFanOutWebhookEvent, processor.ProcesTaskFanOutWebhookEvent)
+	mux.HandleFunc(TaskDeliverWebhook, processor.ProcesTaskDeliverWebhook)
+	mux.HandleFunc(TaskSendTransferReviewEmail, processor.ProcesTaskSendTransferReviewEmail)
+	mux.HandleFunc(TaskSendSecurityAlertEmail, processor.ProcesTaskSendSecurityAlertEmail)
+	mux.HandleFunc(TaskSendPasswordResetEmail, processor.ProcesTaskSendPasswordResetEmail)
+	mux.HandleFunc(TaskSendLoginLinkEmail, processor.ProcesTaskSendLoginLinkEmail)
 
 	return processor.server.Start(mux)
 }
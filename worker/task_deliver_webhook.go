@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskDeliverWebhook = "task:deliver_webhook"
+
+type PayloadDeliverWebhook struct {
+	DeliveryID int64 `json:"delivery_id"`
+}
+
+func (distributor *RedisTaskDistributor) DestributeTaskDeliverWebhook(
+	ctx context.Context,
+	payload *PayloadDeliverWebhook,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload %w", err)
+	}
+	task := asynq.NewTask(TaskDeliverWebhook, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+	return nil
+}
+
+// ProcesTaskDeliverWebhook post signed event payload to the subscriber URL
+// and record the result in the delivery log
+func (processor *RedisTaskProcessor) ProcesTaskDeliverWebhook(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadDeliverWebhook
+	err := json.Unmarshal(task.Payload(), &payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	delivery, err := processor.store.GetWebhookDelivery(ctx, payload.DeliveryID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("webhook delivery does not exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if delivery.Status == db.WebhookDeliverySucceeded {
+		return nil
+	}
+
+	subscription, err := processor.store.GetWebhookSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	if !subscription.IsActive {
+		log.Info().Int64("delivery_id", delivery.ID).Msg("skip delivery for inactive webhook subscription")
+		return nil
+	}
+
+	statusCode, deliverErr := sendWebhook(ctx, processor.webhookClient, subscription, delivery)
+
+	arg := db.UpdateWebhookDeliveryParams{
+		ID:     delivery.ID,
+		Status: db.WebhookDeliverySucceeded,
+		ResponseCode: pgtype.Int4{
+			Int32: int32(statusCode),
+			Valid: statusCode != 0,
+		},
+		DeliveredAt: pgtype.Timestamptz{
+			Time:  time.Now(),
+			Valid: true,
+		},
+	}
+	if deliverErr != nil {
+		arg.Status = db.WebhookDeliveryFailed
+		arg.LastError = pgtype.Text{
+			String: deliverErr.Error(),
+			Valid:  true,
+		}
+		arg.DeliveredAt = pgtype.Timestamptz{}
+	}
+	_, err = processor.store.UpdateWebhookDelivery(ctx, arg)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	if deliverErr != nil {
+		return fmt.Errorf("failed to deliver webhook: %w", deliverErr)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("delivery_id", delivery.ID).
+		Int("status_code", statusCode).Msg("processed task")
+	return nil
+}
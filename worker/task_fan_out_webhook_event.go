@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskFanOutWebhookEvent = "task:fan_out_webhook_event"
+
+type PayloadFanOutWebhookEvent struct {
+	EventID   int64           `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Owners    []string        `json:"owners"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+func (distributor *RedisTaskDistributor) DestributeTaskFanOutWebhookEvent(
+	ctx context.Context,
+	payload *PayloadFanOutWebhookEvent,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload %w", err)
+	}
+	task := asynq.NewTask(TaskFanOutWebhookEvent, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+	log.Info().Str("type", task.Type()).Int64("event_id", payload.EventID).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+	return nil
+}
+
+// ProcesTaskFanOutWebhookEvent create delivery for every subscription of the event owners
+// and enqueue the delivery tasks
+func (processor *RedisTaskProcessor) ProcesTaskFanOutWebhookEvent(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadFanOutWebhookEvent
+	err := json.Unmarshal(task.Payload(), &payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	subscriptions, err := processor.store.ListWebhookSubscriptionsForEvent(ctx, db.ListWebhookSubscriptionsForEventParams{
+		EventType: payload.EventType,
+		Owners:    payload.Owners,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	for _, subscription := range subscriptions {
+		delivery, err := processor.store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			SubscriptionID: subscription.ID,
+			EventID:        payload.EventID,
+			EventType:      payload.EventType,
+			Payload:        payload.Payload,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create webhook delivery: %w", err)
+		}
+		if delivery.Status == db.WebhookDeliverySucceeded {
+			continue
+		}
+
+		err = processor.distributor.DestributeTaskDeliverWebhook(
+			ctx,
+			&PayloadDeliverWebhook{DeliveryID: delivery.ID},
+			asynq.MaxRetry(WebhookMaxRetry),
+			asynq.Queue(QueueDefault),
+			asynq.TaskID(fmt.Sprintf("webhook_delivery:%d", delivery.ID)),
+		)
+		if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			return err
+		}
+	}
+
+	log.Info().Str("type", task.Type()).Int64("event_id", payload.EventID).
+		Int("subscriptions", len(subscriptions)).Msg("processed task")
+	return nil
+}
+
+// webhookEventOwners return usernames whose subscriptions receive the event
+func webhookEventOwners(event db.OutboxEvent) ([]string, error) {
+	switch event.EventType {
+	case db.EventTransferCreated:
+		var transfer db.TransferCreatedEvent
+		if err := json.Unmarshal(event.Payload, &transfer); err != nil {
+			return nil, err
+		}
+		if transfer.FromOwner == transfer.ToOwner {
+			return []string{transfer.FromOwner}, nil
+		}
+		return []string{transfer.FromOwner, transfer.ToOwner}, nil
+	case db.EventAccountCreated:
+		var account db.AccountCreatedEvent
+		if err := json.Unmarshal(event.Payload, &account); err != nil {
+			return nil, err
+		}
+		return []string{account.Owner}, nil
+	case db.EventUserEmailVerified:
+		var user db.UserEmailVerifiedEvent
+		if err := json.Unmarshal(event.Payload, &user); err != nil {
+			return nil, err
+		}
+		return []string{user.Username}, nil
+	}
+	return nil, fmt.Errorf("event type %s is not supported by webhooks", event.EventType)
+}
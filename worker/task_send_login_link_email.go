@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/util"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TaskSendLoginLinkEmail = "task:send_login_link_email"
+	LoginLinkEmailBody     = `<h1>Hi there, %s!</h1></br>
+	<p>To sign in to your Simple Bank account go to this <a href="%s">link!</a> It expires in 15 minutes and works only once.</p></br>
+	<p>If this was not you, you can safely ignore this email.</p>`
+)
+
+// loginLinkSecretSize random bytes of the code sent by email, hex encoded to 32 characters
+const loginLinkSecretSize = 16
+
+type PayloadSendLoginLinkEmail struct {
+	Username string `json:"username"`
+}
+
+func (distributor *RedisTaskDistributor) DestributeTaskSendLoginLinkEmail(
+	ctx context.Context,
+	payload *PayloadSendLoginLinkEmail,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload %w", err)
+	}
+	task := asynq.NewTask(TaskSendLoginLinkEmail, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcesTaskSendLoginLinkEmail(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendLoginLinkEmail
+	err := json.Unmarshal(task.Payload(), &payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// only the hash is stored, the plain code leaves the system by email
+	secretCode, err := util.RandomSecret(loginLinkSecretSize)
+	if err != nil {
+		return fmt.Errorf("failed to generate secret code: %w", err)
+	}
+	link, err := processor.store.CreateLoginLink(ctx, db.CreateLoginLinkParams{
+		Username:       user.Username,
+		SecretCodeHash: util.HashSecretCode(secretCode),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create login link: %w", err)
+	}
+	subject := "Sign in to Simple Bank"
+	// the link opens the page which POSTs the code to /v1/redeem_login_link,
+	// so mail scanners opening the link do not use it
+	loginURL := fmt.Sprintf("http://localhost:8080/login_link?id=%d&secret_code=%s", link.ID, secretCode)
+	content := fmt.Sprintf(LoginLinkEmailBody, user.FullName, loginURL)
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("email", user.Email).Msg("processed task")
+	return processor.sender.SendEmail(subject, content, []string{user.Email}, nil, nil, nil)
+}
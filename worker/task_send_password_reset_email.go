@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/util"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TaskSendPasswordResetEmail = "task:send_password_reset_email"
+	PasswordResetEmailBody     = `<h1>Hi there, %s!</h1></br>
+	<p>We received a request to reset the password of your Simple Bank account.</p></br>
+	<p>To choose a new password go to this <a href="%s">link!</a> It expires in 15 minutes.</p></br>
+	<p>If this was not you, you can safely ignore this email.</p>`
+)
+
+// passwordResetSecretSize random bytes of the code sent by email, hex encoded to 32 characters
+const passwordResetSecretSize = 16
+
+type PayloadSendPasswordResetEmail struct {
+	Username string `json:"username"`
+}
+
+func (distributor *RedisTaskDistributor) DestributeTaskSendPasswordResetEmail(
+	ctx context.Context,
+	payload *PayloadSendPasswordResetEmail,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload %w", err)
+	}
+	task := asynq.NewTask(TaskSendPasswordResetEmail, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcesTaskSendPasswordResetEmail(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendPasswordResetEmail
+	err := json.Unmarshal(task.Payload(), &payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// only the hash is stored, the plain code leaves the system by email
+	secretCode, err := util.RandomSecret(passwordResetSecretSize)
+	if err != nil {
+		return fmt.Errorf("failed to generate secret code: %w", err)
+	}
+	reset, err := processor.store.CreatePasswordReset(ctx, db.CreatePasswordResetParams{
+		Username:       user.Username,
+		SecretCodeHash: util.HashSecretCode(secretCode),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+	subject := "Reset your Simple Bank password"
+	// the link opens the page which POSTs the new password to /v1/reset_password
+	resetURL := fmt.Sprintf("http://localhost:8080/reset_password?id=%d&secret_code=%s", reset.ID, secretCode)
+	content := fmt.Sprintf(PasswordResetEmailBody, user.FullName, resetURL)
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("email", user.Email).Msg("processed task")
+	return processor.sender.SendEmail(subject, content, []string{user.Email}, nil, nil, nil)
+}
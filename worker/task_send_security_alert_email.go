@@ -0,0 +1,112 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TaskSendSecurityAlertEmail = "task:send_security_alert_email"
+	// AlertRefreshTokenReused refresh token of the rotated session was presented again
+	AlertRefreshTokenReused     = "refresh_token_reused"
+	RefreshTokenReusedEmailBody = `<h1>Hi there, %s!</h1></br>
+	<p>An old sign-in token of your account was used again from %s (%s).</p></br>
+	<p>To protect you we signed out %d device(s). If this was not you, please change your password.</p>`
+	// AlertAccountLocked too many failed logins, the account is temporarily locked
+	AlertAccountLocked     = "account_locked"
+	AccountLockedEmailBody = `<h1>Hi there, %s!</h1></br>
+	<p>We noticed too many failed sign-in attempts to your account, the last one from %s (%s).</p></br>
+	<p>Sign-in is locked until %s. To unlock it right away, reset your password.</p></br>
+	<p>If this was not you, nobody got access to your money, but we recommend to choose a stronger password.</p>`
+	// AlertNewDevice successful login from the user agent and IP not seen before
+	AlertNewDevice     = "new_device"
+	NewDeviceEmailBody = `<h1>Hi there, %s!</h1></br>
+	<p>Your account was just used to sign in from a new device: %s (%s).</p></br>
+	<p>If this was you, there is nothing to do.</p></br>
+	<p>If this was not you, please change your password and sign out other sessions.</p>`
+	// AlertEmailChangeRequested the user asked to move the account to a new email
+	AlertEmailChangeRequested = "email_change_requested"
+	EmailChangeRequestedBody  = `<h1>Hi there, %s!</h1></br>
+	<p>We got a request to change the email of your account to %s.</p></br>
+	<p>This address stays active until the new one is verified.</p></br>
+	<p>If this was not you, please change your password and set your email back.</p>`
+)
+
+type PayloadSendSecurityAlertEmail struct {
+	Username        string    `json:"username"`
+	Alert           string    `json:"alert"`
+	UserAgent       string    `json:"user_agent"`
+	ClientIp        string    `json:"client_ip"`
+	RevokedSessions int64     `json:"revoked_sessions"`
+	LockedUntil     time.Time `json:"locked_until"`
+	OldEmail        string    `json:"old_email,omitempty"`
+	NewEmail        string    `json:"new_email,omitempty"`
+}
+
+func (distributor *RedisTaskDistributor) DestributeTaskSendSecurityAlertEmail(
+	ctx context.Context,
+	payload *PayloadSendSecurityAlertEmail,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload %w", err)
+	}
+	task := asynq.NewTask(TaskSendSecurityAlertEmail, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcesTaskSendSecurityAlertEmail(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendSecurityAlertEmail
+	err := json.Unmarshal(task.Payload(), &payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var subject, content string
+	switch payload.Alert {
+	case AlertRefreshTokenReused:
+		subject = "Security alert: suspicious sign-in to Simple Bank"
+		content = fmt.Sprintf(RefreshTokenReusedEmailBody, user.FullName,
+			payload.UserAgent, payload.ClientIp, payload.RevokedSessions)
+	case AlertAccountLocked:
+		subject = "Security alert: your Simple Bank account is locked"
+		content = fmt.Sprintf(AccountLockedEmailBody, user.FullName,
+			payload.UserAgent, payload.ClientIp, payload.LockedUntil.Format(time.RFC1123))
+	case AlertNewDevice:
+		subject = "Security alert: new sign-in to Simple Bank"
+		content = fmt.Sprintf(NewDeviceEmailBody, user.FullName, payload.UserAgent, payload.ClientIp)
+	case AlertEmailChangeRequested:
+		subject = "Security alert: email change of your Simple Bank account"
+		content = fmt.Sprintf(EmailChangeRequestedBody, user.FullName, payload.NewEmail)
+	default:
+		return fmt.Errorf("unknown security alert %q: %w", payload.Alert, asynq.SkipRetry)
+	}
+
+	// the notice must reach the old address even if the new one is already verified
+	email := user.Email
+	if payload.OldEmail != "" {
+		email = payload.OldEmail
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("email", email).Msg("processed task")
+	return processor.sender.SendEmail(subject, content, []string{email}, nil, nil, nil)
+}
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TaskSendTransferReviewEmail = "task:send_transfer_review_email"
+	TransferApprovedEmailBody   = `<h1>Hi there, %s!</h1></br>
+	<p>Your transfer #%d of %d %s to account #%d was approved by the bank and posted.</p>`
+	TransferRejectedEmailBody = `<h1>Hi there, %s!</h1></br>
+	<p>Your transfer #%d of %d %s to account #%d was rejected by the bank.</p></br>
+	<p>No money was taken from your account.</p>`
+)
+
+type PayloadSendTransferReviewEmail struct {
+	TransferID int64 `json:"transfer_id"`
+}
+
+func (distributor *RedisTaskDistributor) DestributeTaskSendTransferReviewEmail(
+	ctx context.Context,
+	payload *PayloadSendTransferReviewEmail,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload %w", err)
+	}
+	task := asynq.NewTask(TaskSendTransferReviewEmail, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcesTaskSendTransferReviewEmail(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendTransferReviewEmail
+	err := json.Unmarshal(task.Payload(), &payload)
+	if err != nil {
+		return fmt.Errorf("failed unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	transfer, err := processor.store.GetTransfer(ctx, payload.TransferID)
+	if err != nil {
+		return fmt.Errorf("failed to get transfer: %w", err)
+	}
+	if !transfer.InitiatedBy.Valid {
+		return fmt.Errorf("transfer %d has no initiator: %w", transfer.ID, asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUser(ctx, transfer.InitiatedBy.String)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	account, err := processor.store.GetAccount(ctx, transfer.FromAccountID.Int64)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	var subject, content string
+	switch transfer.Status {
+	case db.TransferStatusPosted:
+		subject = "Your transfer was approved"
+		content = fmt.Sprintf(TransferApprovedEmailBody, user.FullName,
+			transfer.ID, transfer.Amount, account.Carrency, transfer.ToAccountID.Int64)
+	case db.TransferStatusRejected:
+		subject = "Your transfer was rejected"
+		content = fmt.Sprintf(TransferRejectedEmailBody, user.FullName,
+			transfer.ID, transfer.Amount, account.Carrency, transfer.ToAccountID.Int64)
+	default:
+		return fmt.Errorf("transfer %d is not reviewed: %w", transfer.ID, asynq.SkipRetry)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("email", user.Email).Msg("processed task")
+	return processor.sender.SendEmail(subject, content, []string{user.Email}, nil, nil, nil)
+}
@@ -17,10 +17,20 @@ const (
 	<p>This is wellcome message from simple bank dev project</p></br>
     <p>To verify email go to this <a href="%s">link!</a></p></br>
 	<p>You can find source code <a href="https://github.com/dubass83/simple_bank_golang">here</a></p>`
+	EmailChangeBody = `<h1>Hi there, %s!</h1></br>
+	<p>You asked to use this address for your Simple Bank account.</p></br>
+	<p>To confirm the new email go to this <a href="%s">link!</a></p></br>
+	<p>Until then we keep sending everything to your previous address.</p>`
 )
 
+// verifyEmailSecretSize random bytes of the code sent by email, hex encoded to 32 characters
+const verifyEmailSecretSize = 16
+
+// PayloadSendVerifyEmail when Email is set the link is sent to the pending
+// email of the user instead of the active one
 type PayloadSendVerifyEmail struct {
 	Username string `json:"username"`
+	Email    string `json:"email,omitempty"`
 }
 
 func (distributor *RedisTaskDistributor) DestributeTaskSendVerifyEmail(
@@ -58,19 +68,37 @@ func (processor *RedisTaskProcessor) ProcesTaskVerifyEmail(ctx context.Context,
 		return fmt.Errorf("failed to get user: %w", err)
 	}
 
-	ve, err := processor.store.CreateVerifyEmail(ctx, db.CreateVerifyEmailParams{
-		Username:   user.Username,
-		Email:      user.Email,
-		SecretCode: util.RandomString(32),
+	email := user.Email
+	subject := "Hello from Simple Bank!"
+	body := EmailBody
+	if payload.Email != "" {
+		if !user.PendingEmail.Valid || user.PendingEmail.String != payload.Email {
+			log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+				Msg("email change was canceled or replaced, skip task")
+			return nil
+		}
+		email = payload.Email
+		subject = "Confirm your new Simple Bank email"
+		body = EmailChangeBody
+	}
+
+	// only the hash is stored, the plain code leaves the system by email
+	secretCode, err := util.RandomSecret(verifyEmailSecretSize)
+	if err != nil {
+		return fmt.Errorf("failed to generate secret code: %w", err)
+	}
+	ve, err := processor.store.ReissueVerifyEmailTx(ctx, db.CreateVerifyEmailParams{
+		Username:       user.Username,
+		Email:          email,
+		SecretCodeHash: util.HashSecretCode(secretCode),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create verify email: %w", err)
 	}
-	subject := "Hello from Simple Bank!"
-	verifyURL := fmt.Sprintf("http://localhost:8080/v1/verify_email?id=%d&secret_code=%s", ve.ID, ve.SecretCode)
-	content := fmt.Sprintf(EmailBody, user.Username, verifyURL)
-	to := []string{"makssych@gmail.com", "makssych@outlook.com"}
+	verifyURL := fmt.Sprintf("http://localhost:8080/v1/verify_email?id=%d&secret_code=%s", ve.ID, secretCode)
+	content := fmt.Sprintf(body, user.Username, verifyURL)
+	to := []string{email}
 	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
-		Str("email", user.Email).Msg("processed task")
+		Str("email", email).Msg("processed task")
 	return processor.sender.SendEmail(subject, content, to, nil, nil, nil)
 }
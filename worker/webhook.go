@@ -0,0 +1,144 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/dubass83/simplebank/val"
+)
+
+// Headers sent with every webhook request
+const (
+	WebhookEventHeader     = "X-Simplebank-Event"
+	WebhookDeliveryHeader  = "X-Simplebank-Delivery"
+	WebhookTimestampHeader = "X-Simplebank-Timestamp"
+	WebhookSignatureHeader = "X-Simplebank-Signature"
+)
+
+// WebhookMaxRetry number of retries before the webhook delivery is given up
+const WebhookMaxRetry = 8
+
+const (
+	webhookTimeout       = 10 * time.Second
+	webhookBaseDelay     = 10 * time.Second
+	webhookMaxRetryDelay = 6 * time.Hour
+)
+
+// errWebhookAddressBlocked returned when the subscriber URL resolves to non-public address
+var errWebhookAddressBlocked = errors.New("webhook address is not public")
+
+// newWebhookClient return client which connects only to public addresses and
+// does not follow redirects. The address is checked after the name is resolved,
+// so DNS rebinding to the internal address is blocked as well.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !val.IsPublicAddr(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", errWebhookAddressBlocked, address)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// webhookBody JSON document posted to the subscriber URL
+type webhookBody struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// SignWebhookPayload return value of the signature header.
+// Receiver should compute HMAC-SHA256 of "<timestamp>.<body>" with the subscription secret
+// and compare it with the header value.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendWebhook post delivery payload to the subscriber and return response status code
+func sendWebhook(
+	ctx context.Context,
+	client *http.Client,
+	subscription db.WebhookSubscription,
+	delivery db.WebhookDelivery,
+) (int, error) {
+	body, err := json.Marshal(webhookBody{
+		ID:        delivery.EventID,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Data:      json.RawMessage(delivery.Payload),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed marshal webhook body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed create webhook request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(subscription.Secret, timestamp, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook receiver responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookRetryDelay return exponential delay between webhook delivery attempts
+func webhookRetryDelay(retried int) time.Duration {
+	if retried > 16 {
+		return webhookMaxRetryDelay
+	}
+	delay := webhookBaseDelay * time.Duration(1<<retried)
+	if delay > webhookMaxRetryDelay {
+		return webhookMaxRetryDelay
+	}
+	return delay
+}
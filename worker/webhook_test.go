@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	db "github.com/dubass83/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendWebhook(t *testing.T) {
+	delivery := db.WebhookDelivery{
+		ID:        7,
+		EventID:   42,
+		EventType: db.EventTransferCreated,
+		Payload:   []byte(`{"transfer_id":1,"amount":10}`),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	var received webhookBody
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+		require.NoError(t, err)
+		require.Equal(t, SignWebhookPayload("secret", timestamp, body), r.Header.Get(WebhookSignatureHeader))
+		require.Equal(t, delivery.EventType, r.Header.Get(WebhookEventHeader))
+		require.Equal(t, "7", r.Header.Get(WebhookDeliveryHeader))
+
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	subscription := db.WebhookSubscription{Url: server.URL, Secret: "secret"}
+	statusCode, err := sendWebhook(context.Background(), server.Client(), subscription, delivery)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, statusCode)
+
+	require.Equal(t, delivery.EventID, received.ID)
+	require.Equal(t, delivery.EventType, received.Type)
+	require.True(t, delivery.CreatedAt.Equal(received.CreatedAt))
+	require.JSONEq(t, string(delivery.Payload), string(received.Data))
+}
+
+func TestSendWebhookErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	subscription := db.WebhookSubscription{Url: server.URL, Secret: "secret"}
+	delivery := db.WebhookDelivery{ID: 1, EventType: db.EventAccountCreated, Payload: []byte(`{}`)}
+
+	statusCode, err := sendWebhook(context.Background(), server.Client(), subscription, delivery)
+	require.Error(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, statusCode)
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := SignWebhookPayload("secret", 1700000000, body)
+
+	require.Equal(t, signature, SignWebhookPayload("secret", 1700000000, body))
+	require.NotEqual(t, signature, SignWebhookPayload("other", 1700000000, body))
+	require.NotEqual(t, signature, SignWebhookPayload("secret", 1700000001, body))
+	require.Len(t, signature, len("sha256=")+64)
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	require.Equal(t, webhookBaseDelay, webhookRetryDelay(0))
+	require.Equal(t, 2*webhookBaseDelay, webhookRetryDelay(1))
+	require.Equal(t, webhookMaxRetryDelay, webhookRetryDelay(20))
+	for i := 1; i < 30; i++ {
+		require.GreaterOrEqual(t, webhookRetryDelay(i), webhookRetryDelay(i-1))
+	}
+}
+
+func TestWebhookEventOwners(t *testing.T) {
+	payload, err := json.Marshal(db.TransferCreatedEvent{FromOwner: "alice", ToOwner: "bob"})
+	require.NoError(t, err)
+	owners, err := webhookEventOwners(db.OutboxEvent{EventType: db.EventTransferCreated, Payload: payload})
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "bob"}, owners)
+
+	payload, err = json.Marshal(db.TransferCreatedEvent{FromOwner: "alice", ToOwner: "alice"})
+	require.NoError(t, err)
+	owners, err = webhookEventOwners(db.OutboxEvent{EventType: db.EventTransferCreated, Payload: payload})
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice"}, owners)
+
+	_, err = webhookEventOwners(db.OutboxEvent{EventType: db.EventUserCreated, Payload: []byte(`{}`)})
+	require.Error(t, err)
+}
+
+func TestWebhookClientBlocksInternalAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("internal address must not be called")
+	}))
+	defer server.Close()
+
+	subscription := db.WebhookSubscription{Url: server.URL, Secret: "secret"}
+	delivery := db.WebhookDelivery{ID: 1, EventType: db.EventAccountCreated, Payload: []byte(`{}`)}
+
+	_, err := sendWebhook(context.Background(), newWebhookClient(), subscription, delivery)
+	require.ErrorIs(t, err, errWebhookAddressBlocked)
+}
+
+func TestWebhookClientDoesNotFollowRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			t.Fatal("redirect must not be followed")
+		}
+		http.Redirect(w, r, "/internal", http.StatusFound)
+	}))
+	defer server.Close()
+
+	// loopback test server is reachable only with its own transport
+	client := newWebhookClient()
+	client.Transport = server.Client().Transport
+
+	subscription := db.WebhookSubscription{Url: server.URL, Secret: "secret"}
+	delivery := db.WebhookDelivery{ID: 1, EventType: db.EventAccountCreated, Payload: []byte(`{}`)}
+
+	statusCode, err := sendWebhook(context.Background(), client, subscription, delivery)
+	require.Error(t, err)
+	require.Equal(t, http.StatusFound, statusCode)
+}